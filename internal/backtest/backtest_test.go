@@ -0,0 +1,171 @@
+package backtest
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/indicators"
+)
+
+func assertClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-6 {
+		t.Fatalf("expected %s %.6f, got %.6f", name, want, got)
+	}
+}
+
+func bar(at time.Time, open, high, low, close float64) indicators.Candle {
+	return indicators.Candle{Time: at, Open: open, High: high, Low: low, Close: close, Volume: 1000}
+}
+
+func mustParse(t *testing.T, yaml string) *Strategy {
+	t.Helper()
+	s, err := ParseStrategy([]byte(yaml))
+	if err != nil {
+		t.Fatalf("parse strategy: %v", err)
+	}
+	return s
+}
+
+func TestRunLongWithStopAndTarget(t *testing.T) {
+	s := mustParse(t, `
+name: breakout
+entry: ["close crosses_above 100"]
+stop_loss: 5
+target: 10
+quantity: 10
+`)
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	candles := []indicators.Candle{
+		bar(day, 95, 99, 94, 98),
+		bar(day.AddDate(0, 0, 1), 98, 102, 97, 101),   // cross: enter next open
+		bar(day.AddDate(0, 0, 2), 102, 105, 100, 104), // long at 102, stop 97, target 112
+		bar(day.AddDate(0, 0, 3), 104, 113, 103, 110), // target 112
+		bar(day.AddDate(0, 0, 4), 110, 111, 95, 96),
+		bar(day.AddDate(0, 0, 5), 96, 101, 95, 100.5), // cross again
+		bar(day.AddDate(0, 0, 6), 99, 100, 90, 92),    // long at 99, stop 94 hit
+		bar(day.AddDate(0, 0, 7), 92, 93, 91, 92.5),
+	}
+
+	r, err := Run(s, candles)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(r.Trades) != 2 {
+		t.Fatalf("expected 2 trades, got %+v", r.Trades)
+	}
+	first, second := r.Trades[0], r.Trades[1]
+	if first.ExitReason != ExitTarget || first.EntryPrice != 102 || first.ExitPrice != 112 || !first.ExitTime.Equal(candles[3].Time) {
+		t.Fatalf("unexpected first trade: %+v", first)
+	}
+	if second.ExitReason != ExitStopLoss || second.EntryPrice != 99 || second.ExitPrice != 94 {
+		t.Fatalf("unexpected second trade: %+v", second)
+	}
+	assertClose(t, "net pnl", r.NetPnL, 50)
+	assertClose(t, "win rate", r.WinRate, 50)
+	assertClose(t, "final equity", r.FinalEquity, 100050)
+	assertClose(t, "max drawdown", r.MaxDrawdown, 50)
+	if len(r.Equity) != len(candles) {
+		t.Fatalf("expected one equity point per bar, got %d", len(r.Equity))
+	}
+	assertClose(t, "equity while open", r.Equity[2].Equity, 100020)
+}
+
+func TestRunShortIntradayWithSlippage(t *testing.T) {
+	s := mustParse(t, `
+side: short
+entry: ["close < 100"]
+stop_loss: 2
+slippage: 1
+capital: 1000
+intraday: true
+`)
+	day1 := time.Date(2026, 10, 1, 9, 15, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	candles := []indicators.Candle{
+		bar(day1, 101, 101, 99, 99.5),
+		bar(day1.Add(15*time.Minute), 99, 99, 94, 95), // short at 98, squared off at 96
+		bar(day2, 96, 96, 94, 95),
+		bar(day2.Add(15*time.Minute), 97, 99, 96, 97), // short at 96, stop 98 fills at 99
+	}
+
+	r, err := Run(s, candles)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(r.Trades) != 2 {
+		t.Fatalf("expected 2 trades, got %+v", r.Trades)
+	}
+	first, second := r.Trades[0], r.Trades[1]
+	if first.Side != "short" || first.ExitReason != ExitSquareOff || first.Quantity != 10 || first.EntryPrice != 98 || first.ExitPrice != 96 {
+		t.Fatalf("unexpected first trade: %+v", first)
+	}
+	assertClose(t, "first gross", first.GrossPnL, 20)
+	if second.ExitReason != ExitStopLoss || second.Quantity != 10 || second.EntryPrice != 96 || second.ExitPrice != 99 {
+		t.Fatalf("unexpected second trade: %+v", second)
+	}
+	assertClose(t, "net pnl", r.NetPnL, -10)
+}
+
+func TestRunGapThroughStopFillsAtOpen(t *testing.T) {
+	s := mustParse(t, `
+entry: ["close > 0"]
+stop_loss: 1%
+quantity: 1
+`)
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	r, err := Run(s, []indicators.Candle{
+		bar(day, 100, 100, 100, 100),
+		bar(day.AddDate(0, 0, 1), 100, 101, 99.5, 100),
+		bar(day.AddDate(0, 0, 2), 90, 92, 89, 91),
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(r.Trades) != 1 || r.Trades[0].ExitReason != ExitStopLoss || r.Trades[0].ExitPrice != 90 {
+		t.Fatalf("expected stop filled at the gap open, got %+v", r.Trades)
+	}
+}
+
+func TestChargesModel(t *testing.T) {
+	// 100 shares at 1000: brokerage capped at 20, exchange 2.97, SEBI 0.10,
+	// GST 18% on those, plus 0.003% stamp on the buy or 0.025% STT on the sell.
+	gst := 0.18 * (20 + 2.97 + 0.1)
+	assertClose(t, "intraday buy", EquityIntraday.Order(true, 1000, 100), 20+2.97+0.1+gst+3)
+	assertClose(t, "intraday sell", EquityIntraday.Order(false, 1000, 100), 20+2.97+0.1+gst+25)
+	assertClose(t, "delivery sell", EquityDelivery.Order(false, 1000, 100), 2.97+0.1+0.18*(3.07)+100+15.93)
+	assertClose(t, "none", NoCharges.Order(true, 1000, 100), 0)
+
+	if _, err := ParseChargesModel("discount"); err == nil {
+		t.Fatalf("expected unknown charges model to fail")
+	}
+}
+
+func TestParseStrategyErrors(t *testing.T) {
+	tests := map[string]string{
+		"no entry":        `exit: ["close < 1"]`,
+		"no exit":         `entry: ["close > 1"]`,
+		"bad operator":    "entry: [\"close => 1\"]\ntarget: 1",
+		"bad operand":     "entry: [\"foo:3 > 1\"]\ntarget: 1",
+		"bbands selector": "entry: [\"close > bbands:20:2\"]\ntarget: 1",
+		"macd selector":   "entry: [\"macd.upper > 0\"]\ntarget: 1",
+		"unknown field":   "entry: [\"close > 1\"]\ntarget: 1\nstoploss: 2",
+		"bad side":        "side: sideways\nentry: [\"close > 1\"]\ntarget: 1",
+		"bad offset":      "entry: [\"close > 1\"]\ntarget: abc",
+	}
+	for name, yaml := range tests {
+		if _, err := ParseStrategy([]byte(yaml)); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+
+	s := mustParse(t, "entry: [\"macd.signal crosses_below macd\", \"close > bbands:20:2.upper\"]\ntarget: 1.5%")
+	if !s.Target.Percent || s.Target.From(200) != 3 || s.Capital != defaultCapital {
+		t.Fatalf("unexpected parsed strategy: %+v", s)
+	}
+	if !strings.Contains(s.Entry[1].Right.raw, "upper") {
+		t.Fatalf("expected bbands selector to be kept, got %+v", s.Entry[1])
+	}
+}
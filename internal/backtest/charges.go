@@ -0,0 +1,73 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ChargesModel names a Zerodha fee schedule for NSE orders. Rates follow the
+// published brokerage calculator and are an estimate; they change from time
+// to time and do not include exchange-specific quirks.
+type ChargesModel string
+
+const (
+	NoCharges      ChargesModel = "none"
+	EquityIntraday ChargesModel = "equity-intraday"
+	EquityDelivery ChargesModel = "equity-delivery"
+	Futures        ChargesModel = "futures"
+)
+
+type chargeRates struct {
+	brokerageRate float64 // fraction of turnover, capped at brokerageCap
+	brokerageCap  float64
+	sttBuy        float64
+	sttSell       float64
+	exchange      float64
+	stampBuy      float64
+	dpSell        float64 // flat per sell order, GST inclusive
+}
+
+const (
+	sebiRate = 10.0 / 1e7 // ₹10 per crore
+	gstRate  = 0.18
+)
+
+var chargeSchedules = map[ChargesModel]chargeRates{
+	EquityIntraday: {brokerageRate: 0.0003, brokerageCap: 20, sttSell: 0.00025, exchange: 0.0000297, stampBuy: 0.00003},
+	EquityDelivery: {sttBuy: 0.001, sttSell: 0.001, exchange: 0.0000297, stampBuy: 0.00015, dpSell: 15.93},
+	Futures:        {brokerageRate: 0.0003, brokerageCap: 20, sttSell: 0.0002, exchange: 0.0000173, stampBuy: 0.00002},
+}
+
+func ParseChargesModel(raw string) (ChargesModel, error) {
+	model := ChargesModel(strings.ToLower(strings.TrimSpace(raw)))
+	if model == "" {
+		return NoCharges, nil
+	}
+	if _, ok := chargeSchedules[model]; ok || model == NoCharges {
+		return model, nil
+	}
+	return "", fmt.Errorf("unknown charges model %q (use none, equity-intraday, equity-delivery, futures)", raw)
+}
+
+// Order returns brokerage plus statutory charges for one executed order.
+func (m ChargesModel) Order(buy bool, price float64, quantity int) float64 {
+	rates, ok := chargeSchedules[m]
+	if !ok {
+		return 0
+	}
+	turnover := price * float64(quantity)
+	brokerage := turnover * rates.brokerageRate
+	if rates.brokerageCap > 0 {
+		brokerage = math.Min(brokerage, rates.brokerageCap)
+	}
+	exchange := turnover * rates.exchange
+	sebi := turnover * sebiRate
+	total := brokerage + exchange + sebi + gstRate*(brokerage+exchange+sebi)
+	if buy {
+		total += turnover * (rates.sttBuy + rates.stampBuy)
+	} else {
+		total += turnover*rates.sttSell + rates.dpSell
+	}
+	return total
+}
@@ -0,0 +1,233 @@
+package backtest
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/indicators"
+)
+
+// Exit reasons recorded on trades.
+const (
+	ExitSignal    = "signal"
+	ExitStopLoss  = "stop_loss"
+	ExitTarget    = "target"
+	ExitSquareOff = "square_off"
+	ExitEndOfData = "end_of_data"
+)
+
+type Trade struct {
+	Side       string    `json:"side"`
+	Quantity   int       `json:"quantity"`
+	EntryTime  time.Time `json:"entry_time"`
+	EntryPrice float64   `json:"entry_price"`
+	ExitTime   time.Time `json:"exit_time"`
+	ExitPrice  float64   `json:"exit_price"`
+	ExitReason string    `json:"exit_reason"`
+	GrossPnL   float64   `json:"gross_pnl"`
+	Charges    float64   `json:"charges"`
+	NetPnL     float64   `json:"net_pnl"`
+}
+
+type EquityPoint struct {
+	Time     time.Time `json:"time"`
+	Equity   float64   `json:"equity"`
+	Drawdown float64   `json:"drawdown"`
+}
+
+type Report struct {
+	Strategy        string        `json:"strategy"`
+	Bars            int           `json:"bars"`
+	StartingCapital float64       `json:"starting_capital"`
+	FinalEquity     float64       `json:"final_equity"`
+	GrossPnL        float64       `json:"gross_pnl"`
+	Charges         float64       `json:"charges"`
+	NetPnL          float64       `json:"net_pnl"`
+	ReturnPercent   float64       `json:"return_percent"`
+	Wins            int           `json:"wins"`
+	Losses          int           `json:"losses"`
+	WinRate         float64       `json:"win_rate"`
+	MaxDrawdown     float64       `json:"max_drawdown"`
+	MaxDrawdownPct  float64       `json:"max_drawdown_percent"`
+	Trades          []Trade       `json:"trades"`
+	Equity          []EquityPoint `json:"equity_curve"`
+}
+
+type position struct {
+	quantity     int
+	entryTime    time.Time
+	entryPrice   float64
+	entryCharges float64
+	stop         float64
+	target       float64
+}
+
+// Run replays the strategy bar by bar. Signals are evaluated on a bar's close
+// and filled at the next bar's open. Stops and targets trigger intrabar, at
+// the open when the bar gaps through them; when both are touched in the same
+// bar the stop is assumed to fill first. Slippage applies to every fill
+// except targets, which behave as resting limit orders.
+func Run(s *Strategy, candles []indicators.Candle) (*Report, error) {
+	if len(candles) == 0 {
+		return nil, errors.New("no candles to backtest")
+	}
+	entry, err := newEvaluator(s.Entry, candles)
+	if err != nil {
+		return nil, err
+	}
+	exit, err := newEvaluator(s.Exit, candles)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{Strategy: s.Name, Bars: len(candles), StartingCapital: s.Capital, Trades: []Trade{}}
+	sign := 1.0
+	side := "long"
+	if s.Short {
+		sign, side = -1, "short"
+	}
+
+	realized := 0.0
+	var pos *position
+	open := func(c indicators.Candle) {
+		price := c.Open + sign*s.Slippage.From(c.Open)
+		qty := s.Quantity
+		if qty == 0 {
+			qty = int((s.Capital + realized) / price)
+		}
+		if qty <= 0 {
+			return
+		}
+		pos = &position{
+			quantity:     qty,
+			entryTime:    c.Time,
+			entryPrice:   price,
+			entryCharges: s.Charges.Order(!s.Short, price, qty),
+			stop:         math.NaN(),
+			target:       math.NaN(),
+		}
+		if s.StopLoss.IsSet() {
+			pos.stop = price - sign*s.StopLoss.From(price)
+		}
+		if s.Target.IsSet() {
+			pos.target = price + sign*s.Target.From(price)
+		}
+	}
+	closePos := func(at time.Time, price float64, reason string, slip bool) {
+		if slip {
+			price -= sign * s.Slippage.From(price)
+		}
+		gross := sign * (price - pos.entryPrice) * float64(pos.quantity)
+		charges := pos.entryCharges + s.Charges.Order(s.Short, price, pos.quantity)
+		trade := Trade{
+			Side:       side,
+			Quantity:   pos.quantity,
+			EntryTime:  pos.entryTime,
+			EntryPrice: pos.entryPrice,
+			ExitTime:   at,
+			ExitPrice:  price,
+			ExitReason: reason,
+			GrossPnL:   gross,
+			Charges:    charges,
+			NetPnL:     gross - charges,
+		}
+		r.Trades = append(r.Trades, trade)
+		realized += trade.NetPnL
+		pos = nil
+	}
+
+	pendingEntry, pendingExit := false, false
+	peak := s.Capital
+	for i, c := range candles {
+		if pos != nil && pendingExit {
+			closePos(c.Time, c.Open, ExitSignal, true)
+		}
+		if pos == nil && pendingEntry {
+			open(c)
+		}
+		pendingEntry, pendingExit = false, false
+
+		if pos != nil {
+			checkStops(pos, c, sign, closePos)
+		}
+
+		last := i == len(candles)-1
+		lastOfDay := last || !sameDay(c.Time, candles[i+1].Time)
+		if pos != nil && s.Intraday && lastOfDay {
+			closePos(c.Time, c.Close, ExitSquareOff, true)
+		}
+		if pos != nil && last {
+			closePos(c.Time, c.Close, ExitEndOfData, true)
+		}
+
+		if pos != nil {
+			pendingExit = exit.all(i)
+		} else if !last && !(s.Intraday && lastOfDay) {
+			pendingEntry = entry.all(i)
+		}
+
+		equity := s.Capital + realized
+		if pos != nil {
+			equity += sign*(c.Close-pos.entryPrice)*float64(pos.quantity) - pos.entryCharges
+		}
+		peak = math.Max(peak, equity)
+		r.Equity = append(r.Equity, EquityPoint{Time: c.Time, Equity: equity, Drawdown: peak - equity})
+		if dd := peak - equity; dd > r.MaxDrawdown {
+			r.MaxDrawdown = dd
+			r.MaxDrawdownPct = dd / peak * 100
+		}
+	}
+	for _, trade := range r.Trades {
+		r.GrossPnL += trade.GrossPnL
+		r.Charges += trade.Charges
+		if trade.NetPnL > 0 {
+			r.Wins++
+		} else {
+			r.Losses++
+		}
+	}
+	r.NetPnL = r.GrossPnL - r.Charges
+	r.FinalEquity = s.Capital + r.NetPnL
+	r.ReturnPercent = r.NetPnL / s.Capital * 100
+	if len(r.Trades) > 0 {
+		r.WinRate = float64(r.Wins) / float64(len(r.Trades)) * 100
+	}
+	return r, nil
+}
+
+func checkStops(pos *position, c indicators.Candle, sign float64, closePos func(time.Time, float64, string, bool)) {
+	// Prices are mirrored for shorts so higher is always favourable.
+	directional := func(price float64) float64 { return sign * price }
+	if !math.IsNaN(pos.stop) {
+		worst := c.Low
+		if sign < 0 {
+			worst = c.High
+		}
+		if directional(c.Open) <= directional(pos.stop) {
+			closePos(c.Time, c.Open, ExitStopLoss, true)
+			return
+		}
+		if directional(worst) <= directional(pos.stop) {
+			closePos(c.Time, pos.stop, ExitStopLoss, true)
+			return
+		}
+	}
+	if !math.IsNaN(pos.target) {
+		best := c.High
+		if sign < 0 {
+			best = c.Low
+		}
+		if directional(c.Open) >= directional(pos.target) {
+			closePos(c.Time, c.Open, ExitTarget, false)
+			return
+		}
+		if directional(best) >= directional(pos.target) {
+			closePos(c.Time, pos.target, ExitTarget, false)
+		}
+	}
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Format(time.DateOnly) == b.Format(time.DateOnly)
+}
@@ -0,0 +1,352 @@
+// Package backtest replays rule-based strategies over historical candles.
+package backtest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/indicators"
+	"gopkg.in/yaml.v3"
+)
+
+// strategyFile is the YAML layout of a strategy, e.g.
+//
+//	name: ema-crossover
+//	side: long
+//	entry: ["ema:9 crosses_above ema:21", "rsi:14 < 70"]
+//	exit: ["ema:9 crosses_below ema:21"]
+//	stop_loss: 1%
+//	target: 2%
+//	quantity: 10
+//	capital: 100000
+//	slippage: 0.05%
+//	charges: equity-intraday
+//	intraday: true
+type strategyFile struct {
+	Name     string   `yaml:"name"`
+	Side     string   `yaml:"side"`
+	Entry    []string `yaml:"entry"`
+	Exit     []string `yaml:"exit"`
+	StopLoss string   `yaml:"stop_loss"`
+	Target   string   `yaml:"target"`
+	Quantity int      `yaml:"quantity"`
+	Capital  float64  `yaml:"capital"`
+	Slippage string   `yaml:"slippage"`
+	Charges  string   `yaml:"charges"`
+	Intraday bool     `yaml:"intraday"`
+}
+
+const defaultCapital = 100000.0
+
+// Strategy is a parsed strategy file. Entry and Exit conditions are ANDed.
+type Strategy struct {
+	Name     string
+	Short    bool
+	Entry    []Condition
+	Exit     []Condition
+	StopLoss Offset
+	Target   Offset
+	Quantity int
+	Capital  float64
+	Slippage Offset
+	Charges  ChargesModel
+	Intraday bool
+}
+
+// Offset is a price distance, either in points or as a percent of a price.
+// The zero value means unset.
+type Offset struct {
+	Value   float64
+	Percent bool
+}
+
+func (o Offset) IsSet() bool {
+	return o.Value > 0
+}
+
+func (o Offset) From(price float64) float64 {
+	if o.Percent {
+		return price * o.Value / 100
+	}
+	return o.Value
+}
+
+func parseOffset(raw, field string) (Offset, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return Offset{}, nil
+	}
+	percent := strings.HasSuffix(value, "%")
+	n, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(value, "%")), 64)
+	if err != nil || n < 0 {
+		return Offset{}, fmt.Errorf("invalid %s %q; use points (e.g. 12.5) or a percent (e.g. 1.5%%)", field, raw)
+	}
+	return Offset{Value: n, Percent: percent}, nil
+}
+
+func ParseStrategy(data []byte) (*Strategy, error) {
+	var file strategyFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("parse strategy: %w", err)
+	}
+
+	s := &Strategy{
+		Name:     strings.TrimSpace(file.Name),
+		Quantity: file.Quantity,
+		Capital:  file.Capital,
+		Intraday: file.Intraday,
+	}
+	switch strings.ToLower(strings.TrimSpace(file.Side)) {
+	case "", "long", "buy":
+	case "short", "sell":
+		s.Short = true
+	default:
+		return nil, fmt.Errorf("invalid side %q; use long or short", file.Side)
+	}
+	if len(file.Entry) == 0 {
+		return nil, errors.New("strategy needs at least one entry condition")
+	}
+	if file.Quantity < 0 || file.Capital < 0 {
+		return nil, errors.New("quantity and capital must not be negative")
+	}
+	if s.Capital == 0 {
+		s.Capital = defaultCapital
+	}
+
+	var err error
+	if s.Entry, err = parseConditions(file.Entry); err != nil {
+		return nil, err
+	}
+	if s.Exit, err = parseConditions(file.Exit); err != nil {
+		return nil, err
+	}
+	if s.StopLoss, err = parseOffset(file.StopLoss, "stop_loss"); err != nil {
+		return nil, err
+	}
+	if s.Target, err = parseOffset(file.Target, "target"); err != nil {
+		return nil, err
+	}
+	if s.Slippage, err = parseOffset(file.Slippage, "slippage"); err != nil {
+		return nil, err
+	}
+	if s.Charges, err = ParseChargesModel(file.Charges); err != nil {
+		return nil, err
+	}
+	if len(s.Exit) == 0 && !s.StopLoss.IsSet() && !s.Target.IsSet() && !s.Intraday {
+		return nil, errors.New("strategy needs an exit: exit conditions, stop_loss, target or intraday")
+	}
+	return s, nil
+}
+
+// Condition compares two operands on a bar, e.g. "rsi:14 < 30" or
+// "ema:9 crosses_above ema:21".
+type Condition struct {
+	Left  Operand
+	Op    string
+	Right Operand
+}
+
+const (
+	opCrossesAbove = "crosses_above"
+	opCrossesBelow = "crosses_below"
+)
+
+var conditionOps = []string{opCrossesAbove, opCrossesBelow, ">", "<", ">=", "<="}
+
+func parseConditions(raw []string) ([]Condition, error) {
+	conditions := make([]Condition, 0, len(raw))
+	for _, entry := range raw {
+		fields := strings.Fields(entry)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid condition %q; use <operand> <op> <operand>", entry)
+		}
+		op := strings.ToLower(fields[1])
+		if !slices.Contains(conditionOps, op) {
+			return nil, fmt.Errorf("invalid operator %q in %q (use %s)", fields[1], entry, strings.Join(conditionOps, ", "))
+		}
+		left, err := parseOperand(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		right, err := parseOperand(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, Condition{Left: left, Op: op, Right: right})
+	}
+	return conditions, nil
+}
+
+// Operand is a number, a candle field (open, high, low, close, volume) or an
+// indicator spec with an optional output selector such as macd.signal or
+// bbands:20:2.upper.
+type Operand struct {
+	raw      string
+	constant float64
+	field    string
+	spec     *indicators.Spec
+	output   string
+}
+
+var candleFields = []string{"open", "high", "low", "close", "volume"}
+
+func parseOperand(raw string) (Operand, error) {
+	operand := Operand{raw: raw}
+	if v, err := strconv.ParseFloat(raw, 64); err == nil {
+		operand.constant = v
+		return operand, nil
+	}
+	name := strings.ToLower(raw)
+	if slices.Contains(candleFields, name) {
+		operand.field = name
+		return operand, nil
+	}
+	if base, output, ok := strings.Cut(name, "."); ok {
+		name, operand.output = base, output
+	}
+	specs, err := indicators.ParseSpecs(name)
+	if err != nil {
+		return operand, fmt.Errorf("invalid operand %q: %w", raw, err)
+	}
+	operand.spec = &specs[0]
+	if outputs := indicatorOutputs[operand.spec.Name]; !slices.Contains(outputs, operand.output) {
+		return operand, fmt.Errorf("invalid operand %q; %s outputs are %s", raw, operand.spec.Name, describeOutputs(outputs))
+	}
+	return operand, nil
+}
+
+// indicatorOutputs lists the selectors each indicator accepts after a dot;
+// "" is the default output.
+var indicatorOutputs = map[string][]string{
+	"sma":    {""},
+	"ema":    {""},
+	"rsi":    {""},
+	"atr":    {""},
+	"vwap":   {""},
+	"macd":   {"", "signal", "hist"},
+	"bbands": {"upper", "middle", "lower"},
+}
+
+func describeOutputs(outputs []string) string {
+	names := make([]string, 0, len(outputs))
+	for _, output := range outputs {
+		if output == "" {
+			names = append(names, "(default)")
+		} else {
+			names = append(names, "."+output)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func candleField(c indicators.Candle, field string) float64 {
+	switch field {
+	case "open":
+		return c.Open
+	case "high":
+		return c.High
+	case "low":
+		return c.Low
+	case "volume":
+		return c.Volume
+	default:
+		return c.Close
+	}
+}
+
+// series returns the operand's values aligned with candles.
+func (o Operand) series(candles []indicators.Candle) ([]float64, error) {
+	values := make([]float64, len(candles))
+	switch {
+	case o.spec != nil:
+		columns := indicators.Compute([]indicators.Spec{*o.spec}, candles)
+		for _, column := range columns {
+			if o.output == "" || strings.HasSuffix(column.Name, "_"+o.output) {
+				return column.Values, nil
+			}
+		}
+		return nil, fmt.Errorf("indicator %q has no output %q", o.spec.Name, o.output)
+	case o.field != "":
+		for i, c := range candles {
+			values[i] = candleField(c, o.field)
+		}
+	default:
+		for i := range values {
+			values[i] = o.constant
+		}
+	}
+	return values, nil
+}
+
+// evaluator holds each condition's operand series for one candle set.
+type evaluator struct {
+	left, right [][]float64
+	ops         []string
+}
+
+func newEvaluator(conditions []Condition, candles []indicators.Candle) (*evaluator, error) {
+	e := &evaluator{}
+	for _, condition := range conditions {
+		left, err := condition.Left.series(candles)
+		if err != nil {
+			return nil, err
+		}
+		right, err := condition.Right.series(candles)
+		if err != nil {
+			return nil, err
+		}
+		e.left = append(e.left, left)
+		e.right = append(e.right, right)
+		e.ops = append(e.ops, condition.Op)
+	}
+	return e, nil
+}
+
+// all reports whether every condition holds at bar i. Bars where an operand
+// is still warming up never match.
+func (e *evaluator) all(i int) bool {
+	if len(e.ops) == 0 {
+		return false
+	}
+	for c, op := range e.ops {
+		a, b := e.left[c][i], e.right[c][i]
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return false
+		}
+		var ok bool
+		switch op {
+		case ">":
+			ok = a > b
+		case "<":
+			ok = a < b
+		case ">=":
+			ok = a >= b
+		case "<=":
+			ok = a <= b
+		case opCrossesAbove, opCrossesBelow:
+			if i == 0 {
+				return false
+			}
+			pa, pb := e.left[c][i-1], e.right[c][i-1]
+			if math.IsNaN(pa) || math.IsNaN(pb) {
+				return false
+			}
+			if op == opCrossesAbove {
+				ok = pa <= pb && a > b
+			} else {
+				ok = pa >= pb && a < b
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
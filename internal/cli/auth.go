@@ -7,7 +7,6 @@ import (
 
 	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
 	"github.com/spf13/cobra"
-	kiteconnect "github.com/zerodha/gokiteconnect/v4"
 )
 
 func newAuthCmd(opts *rootOptions) *cobra.Command {
@@ -20,6 +19,9 @@ func newAuthCmd(opts *rootOptions) *cobra.Command {
 		loginUseCallback bool
 		loginPort        int
 		loginRequest     string
+		loginTLS         bool
+		loginTimeout     time.Duration
+		loginOpen        bool
 	)
 	loginCmd := &cobra.Command{
 		Use:   "login",
@@ -27,7 +29,10 @@ func newAuthCmd(opts *rootOptions) *cobra.Command {
 		Long: "Login and persist access/refresh tokens for the selected profile.\n\n" +
 			"Exactly one token acquisition mode is required:\n" +
 			"  - --request-token <token_or_redirect_url>\n" +
-			"  - --callback [--callback-port <1-65535>]",
+			"  - --callback [--callback-port <1-65535>] [--callback-tls] [--timeout <duration>] [--open]\n\n" +
+			"Callback mode checks that the redirect carries status=success and action=login.\n" +
+			"With --callback-tls the listener uses a self-signed localhost certificate, so the\n" +
+			"app's redirect URL must be https://127.0.0.1:<port>/ and the browser will warn once.",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			requestToken, err := validateAuthLoginFlags(
 				loginUseCallback,
@@ -38,6 +43,9 @@ func newAuthCmd(opts *rootOptions) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := validateCallbackFlags(loginUseCallback, loginTimeout, cmd.Flags().Changed); err != nil {
+				return err
+			}
 
 			ctx, err := newCommandContext(opts)
 			if err != nil {
@@ -59,10 +67,20 @@ func newAuthCmd(opts *rootOptions) *cobra.Command {
 			}
 
 			if loginUseCallback {
-				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Waiting for callback at http://127.0.0.1:%d/ (timeout: 2m)\n", loginPort); err != nil {
+				listener, err := listenCallback(loginPort, loginTLS)
+				if err != nil {
+					return exitcode.Wrap(exitcode.Auth, "failed to start callback listener", err)
+				}
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Waiting for callback at %s (timeout: %s)\n", callbackURL(listener, loginTLS), loginTimeout); err != nil {
+					_ = listener.Close()
 					return err
 				}
-				token, err := captureRequestToken(loginPort, 2*time.Minute)
+				if loginOpen {
+					if err := openBrowser(loginURL); err != nil {
+						_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: could not open browser: %v\n", err)
+					}
+				}
+				token, err := captureRequestToken(listener, loginTimeout)
 				if err != nil {
 					return exitcode.Wrap(exitcode.Auth, "failed to receive request_token via callback", err)
 				}
@@ -111,6 +129,9 @@ func newAuthCmd(opts *rootOptions) *cobra.Command {
 	}
 	loginCmd.Flags().BoolVar(&loginUseCallback, "callback", false, "Capture request_token from localhost callback (cannot be used with --request-token)")
 	loginCmd.Flags().IntVar(&loginPort, "callback-port", 8787, "Local callback port (1-65535, only with --callback)")
+	loginCmd.Flags().BoolVar(&loginTLS, "callback-tls", false, "Serve the callback over HTTPS with a self-signed localhost certificate (only with --callback)")
+	loginCmd.Flags().DurationVar(&loginTimeout, "timeout", defaultCallbackTimeout, "How long to wait for the callback (only with --callback)")
+	loginCmd.Flags().BoolVar(&loginOpen, "open", false, "Open the login URL in the default browser (only with --callback)")
 	loginCmd.Flags().StringVar(&loginRequest, "request-token", "", "Request token or full redirect URL (required unless --callback is used)")
 
 	renewCmd := &cobra.Command{
@@ -241,7 +262,7 @@ func newAuthCmd(opts *rootOptions) *cobra.Command {
 				return exitcode.New(exitcode.Validation, "no refresh token to revoke; pass --refresh-token or login again to store one")
 			}
 
-			revoked, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (bool, error) {
+			revoked, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (bool, error) {
 				return client.InvalidateRefreshToken(token)
 			})
 			if err != nil {
@@ -280,6 +301,6 @@ func newAuthCmd(opts *rootOptions) *cobra.Command {
 	}
 	revokeRefreshCmd.Flags().StringVar(&revokeRefreshToken, "refresh-token", "", "Refresh token (defaults to stored profile refresh token)")
 
-	authCmd.AddCommand(loginCmd, renewCmd, logoutCmd, revokeRefreshCmd)
+	authCmd.AddCommand(loginCmd, renewCmd, logoutCmd, revokeRefreshCmd, newAuthStatusCmd(opts))
 	return authCmd
 }
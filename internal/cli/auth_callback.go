@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"html/template"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+)
+
+const defaultCallbackTimeout = 2 * time.Minute
+
+type callbackResult struct {
+	token string
+	err   error
+}
+
+var callbackPage = template.Must(template.New("callback").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; background: #f6f7f9; color: #222; }
+main { max-width: 32rem; margin: 15vh auto; padding: 2rem; background: #fff; border-radius: 8px; box-shadow: 0 1px 4px rgba(0,0,0,.1); }
+h1 { font-size: 1.4rem; color: {{if .OK}}#1a7f37{{else}}#cf222e{{end}}; }
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+</main>
+</body>
+</html>
+`))
+
+// validateCallbackFlags checks the options that only make sense while
+// waiting for the localhost redirect.
+func validateCallbackFlags(useCallback bool, timeout time.Duration, changed func(string) bool) error {
+	if !useCallback {
+		for _, name := range []string{"callback-tls", "timeout", "open"} {
+			if changed(name) {
+				return exitcode.New(exitcode.Validation, fmt.Sprintf("--%s can only be used with --callback", name))
+			}
+		}
+	}
+	if timeout <= 0 {
+		return exitcode.New(exitcode.Validation, "--timeout must be greater than 0")
+	}
+	return nil
+}
+
+// openBrowser launches the system browser; tests replace it.
+var openBrowser = func(target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	return cmd.Start()
+}
+
+// listenCallback binds the callback listener on localhost, wrapping it in
+// TLS with a throwaway self-signed certificate when useTLS is set.
+func listenCallback(port int, useTLS bool) (net.Listener, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, err
+	}
+	if !useTLS {
+		return listener, nil
+	}
+	cert, err := selfSignedCertificate()
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return tls.NewListener(listener, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+func callbackURL(listener net.Listener, useTLS bool) string {
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/", scheme, listener.Addr().String())
+}
+
+func selfSignedCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("generate callback key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("generate callback serial: %w", err)
+	}
+	now := time.Now()
+	certTemplate := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, certTemplate, certTemplate, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("create callback certificate: %w", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// captureRequestToken serves the login redirect on listener until a valid
+// callback or a failed login arrives, or timeout elapses.
+func captureRequestToken(listener net.Listener, timeout time.Duration) (string, error) {
+	results := make(chan callbackResult, 1)
+	errCh := make(chan error, 1)
+
+	server := &http.Server{
+		Handler:           callbackHandler(results),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer shutdownCancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	select {
+	case result := <-results:
+		return result.token, result.err
+	case err := <-errCh:
+		return "", err
+	case <-ctx.Done():
+		return "", fmt.Errorf("timed out after %s waiting for request_token callback", timeout)
+	}
+}
+
+// callbackHandler checks the Kite redirect and reports the first outcome on
+// results. Requests that carry neither status nor request_token (favicon,
+// stray page loads) are ignored.
+func callbackHandler(results chan<- callbackResult) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/" || (query.Get("status") == "" && query.Get("request_token") == "") {
+			http.NotFound(w, r)
+			return
+		}
+
+		token, err := parseLoginRedirect(query)
+		page := struct {
+			OK      bool
+			Title   string
+			Message string
+		}{OK: true, Title: "Login complete", Message: "You can close this tab and return to the terminal."}
+		if err != nil {
+			page.OK, page.Title, page.Message = false, "Login failed", err.Error()+". Return to the terminal and try again."
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if !page.OK {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_ = callbackPage.Execute(w, page)
+
+		select {
+		case results <- callbackResult{token: token, err: err}:
+		default:
+		}
+	})
+}
+
+func parseLoginRedirect(query url.Values) (string, error) {
+	status := strings.TrimSpace(query.Get("status"))
+	if status != "success" {
+		if status == "" {
+			return "", errors.New("callback is missing the status parameter")
+		}
+		if message := strings.TrimSpace(query.Get("message")); message != "" {
+			return "", fmt.Errorf("login failed with status %q: %s", status, message)
+		}
+		return "", fmt.Errorf("login failed with status %q", status)
+	}
+	if action := strings.TrimSpace(query.Get("action")); action != "login" {
+		return "", fmt.Errorf("unexpected callback action %q", action)
+	}
+	token := strings.TrimSpace(query.Get("request_token"))
+	if token == "" {
+		return "", errors.New("callback is missing request_token")
+	}
+	return token, nil
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/kitefake"
+)
+
+func TestCallbackHandlerValidatesRedirect(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		status    int
+		token     string
+		errMatch  string
+		delivered bool
+	}{
+		{
+			name:      "success",
+			query:     "status=success&action=login&request_token=abc123",
+			status:    http.StatusOK,
+			token:     "abc123",
+			delivered: true,
+		},
+		{
+			name:      "kite error status",
+			query:     "status=error&message=user+cancelled&action=login",
+			status:    http.StatusBadRequest,
+			errMatch:  `login failed with status "error": user cancelled`,
+			delivered: true,
+		},
+		{
+			name:      "wrong action",
+			query:     "status=success&action=basket&request_token=abc123",
+			status:    http.StatusBadRequest,
+			errMatch:  `unexpected callback action "basket"`,
+			delivered: true,
+		},
+		{
+			name:      "missing status",
+			query:     "action=login&request_token=abc123",
+			status:    http.StatusBadRequest,
+			errMatch:  "missing the status parameter",
+			delivered: true,
+		},
+		{
+			name:      "missing token",
+			query:     "status=success&action=login",
+			status:    http.StatusBadRequest,
+			errMatch:  "missing request_token",
+			delivered: true,
+		},
+		{
+			name:   "stray request is ignored",
+			query:  "",
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results := make(chan callbackResult, 1)
+			rec := httptest.NewRecorder()
+			callbackHandler(results).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil))
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, rec.Code)
+			}
+			select {
+			case result := <-results:
+				if !tc.delivered {
+					t.Fatalf("expected no result, got %+v", result)
+				}
+				if result.token != tc.token {
+					t.Fatalf("expected token %q, got %q", tc.token, result.token)
+				}
+				if tc.errMatch == "" && result.err != nil {
+					t.Fatalf("unexpected error: %v", result.err)
+				}
+				if tc.errMatch != "" && (result.err == nil || !strings.Contains(result.err.Error(), tc.errMatch)) {
+					t.Fatalf("expected error containing %q, got %v", tc.errMatch, result.err)
+				}
+				if !strings.Contains(rec.Header().Get("Content-Type"), "text/html") {
+					t.Fatalf("expected an HTML page, got %q", rec.Header().Get("Content-Type"))
+				}
+			default:
+				if tc.delivered {
+					t.Fatalf("expected a result to be delivered")
+				}
+			}
+		})
+	}
+}
+
+func TestCallbackPageEscapesKiteMessage(t *testing.T) {
+	srv := httptest.NewServer(callbackHandler(make(chan callbackResult, 1)))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/?status=error&message=%3Cscript%3Ealert(1)%3C/script%3E")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if !strings.Contains(string(body), "Login failed") || strings.Contains(string(body), "<script>") {
+		t.Fatalf("expected an escaped failure page, got %s", body)
+	}
+}
+
+func TestCaptureRequestTokenOverTLS(t *testing.T) {
+	listener, err := listenCallback(0, true)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	target := callbackURL(listener, true)
+	if !strings.HasPrefix(target, "https://127.0.0.1:") {
+		t.Fatalf("unexpected callback URL %q", target)
+	}
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	go func() {
+		for _, query := range []string{"favicon.ico", "?status=success&action=login&request_token=tls_token"} {
+			resp, err := client.Get(target + query)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}
+	}()
+
+	token, err := captureRequestToken(listener, 5*time.Second)
+	if err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if token != "tls_token" {
+		t.Fatalf("expected tls_token, got %q", token)
+	}
+}
+
+func TestCaptureRequestTokenTimesOut(t *testing.T) {
+	listener, err := listenCallback(0, false)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	_, err = captureRequestToken(listener, 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+}
+
+func TestAuthLoginCallbackOpensBrowser(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+	cfg := loadTestConfig(t, configPath)
+	profile := cfg.Profiles["default"]
+	profile.AccessToken, profile.RefreshToken = "", ""
+	cfg.Profiles["default"] = profile
+	saveTestConfig(t, configPath, cfg)
+
+	probe, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("pick port: %v", err)
+	}
+	port := probe.Addr().(*net.TCPAddr).Port
+	_ = probe.Close()
+
+	var opened string
+	previous := openBrowser
+	openBrowser = func(target string) error {
+		opened = target
+		go func() {
+			resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/?status=success&action=login&request_token=%s", port, kitefake.RequestToken))
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+	t.Cleanup(func() { openBrowser = previous })
+
+	stdout, _, err := executeCLICommand(t, configPath, "auth", "login", "--callback", "--callback-port", fmt.Sprint(port), "--open", "--timeout", "5s")
+	if err != nil {
+		t.Fatalf("auth login: %v", err)
+	}
+	if !strings.Contains(opened, "api_key="+kitefake.APIKey) {
+		t.Fatalf("expected the login URL to be opened, got %q", opened)
+	}
+	if !strings.Contains(stdout, "(timeout: 5s)") {
+		t.Fatalf("expected the timeout to be echoed, got %q", stdout)
+	}
+	if saved := loadTestConfig(t, configPath).Profiles["default"]; saved.AccessToken != srv.AccessToken() {
+		t.Fatalf("expected the session to be saved, got %+v", saved)
+	}
+}
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+)
+
+const (
+	sessionValid   = "valid"
+	sessionExpired = "expired"
+	sessionMissing = "missing"
+	sessionUnknown = "unknown"
+)
+
+// kiteTokenResetHour is when Kite invalidates every access token, daily at
+// around 06:00 IST.
+const kiteTokenResetHour = 6
+
+type authStatus struct {
+	Profile         string     `json:"profile"`
+	APIKey          bool       `json:"api_key"`
+	APISecret       bool       `json:"api_secret"`
+	AccessToken     bool       `json:"access_token"`
+	RefreshToken    bool       `json:"refresh_token"`
+	LastLoginAt     *time.Time `json:"last_login_at,omitempty"`
+	PredictedExpiry *time.Time `json:"predicted_expiry,omitempty"`
+	Checked         bool       `json:"checked"`
+	Status          string     `json:"status"`
+	UserID          string     `json:"user_id,omitempty"`
+	Error           string     `json:"error,omitempty"`
+}
+
+func newAuthStatusCmd(opts *rootOptions) *cobra.Command {
+	var offline bool
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Report whether each profile's session is present and still valid",
+		Long: strings.Join([]string{
+			"Checks the selected profile, or every profile when none is selected with --profile or ZERODHA_PROFILE.",
+			"Each access token is validated live with a profile call unless --offline is set; the predicted expiry is the next 06:00 IST after the last login.",
+			"Exit codes: 0 when every session is valid, 16 when one has expired, 17 when one is missing credentials or a token.",
+		}, " "),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+
+			names, err := authStatusProfiles(ctx)
+			if err != nil {
+				return err
+			}
+			now := nowUTC()
+			statuses := make([]authStatus, 0, len(names))
+			for _, name := range names {
+				profile := ctx.cfg.Profiles[name]
+				if err := ctx.resolveCredentials(name, &profile); err != nil {
+					return err
+				}
+				statuses = append(statuses, checkAuthStatus(ctx, name, profile, now, offline))
+			}
+
+			if err := printAuthStatuses(ctx, cmd, statuses); err != nil {
+				return err
+			}
+			return authStatusError(statuses)
+		},
+	}
+	cmd.Flags().BoolVar(&offline, "offline", false, "Skip the live token check and rely on the predicted expiry")
+	return cmd
+}
+
+func authStatusProfiles(ctx *commandContext) ([]string, error) {
+	if strings.TrimSpace(ctx.opts.profile) != "" {
+		name, _, err := ctx.resolveProfile(true)
+		if err != nil {
+			return nil, err
+		}
+		return []string{name}, nil
+	}
+	names := ctx.profileNames()
+	if len(names) == 0 && credentialEnvSet() {
+		names = []string{envProfileName}
+	}
+	if len(names) == 0 {
+		return nil, exitcode.New(exitcode.SessionMissing, "no profiles configured; add one with `zerodha config profile add`")
+	}
+	return names, nil
+}
+
+func checkAuthStatus(ctx *commandContext, name string, profile config.Profile, now time.Time, offline bool) authStatus {
+	status := authStatus{
+		Profile:      name,
+		APIKey:       strings.TrimSpace(profile.APIKey) != "",
+		APISecret:    strings.TrimSpace(profile.APISecret) != "",
+		AccessToken:  strings.TrimSpace(profile.AccessToken) != "",
+		RefreshToken: strings.TrimSpace(profile.RefreshToken) != "",
+	}
+	if !profile.LastLoginAt.IsZero() {
+		lastLogin := profile.LastLoginAt
+		expiry := predictTokenExpiry(lastLogin)
+		status.LastLoginAt, status.PredictedExpiry = &lastLogin, &expiry
+	}
+
+	if !status.APIKey || !status.AccessToken {
+		status.Status = sessionMissing
+		return status
+	}
+	if offline {
+		status.Status = sessionValid
+		if status.PredictedExpiry == nil {
+			status.Status = sessionUnknown
+		} else if !now.Before(*status.PredictedExpiry) {
+			status.Status = sessionExpired
+		}
+		return status
+	}
+
+	status.Checked = true
+	user, err := newKiteClient(profile, ctx.opts.debug).GetUserProfile()
+	switch {
+	case err == nil:
+		status.Status = sessionValid
+		status.UserID = user.UserID
+	case isTokenError(err):
+		status.Status = sessionExpired
+		status.Error = err.Error()
+	default:
+		status.Status = sessionUnknown
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// predictTokenExpiry returns the first 06:00 IST after lastLogin.
+func predictTokenExpiry(lastLogin time.Time) time.Time {
+	local := lastLogin.In(istLocation)
+	reset := time.Date(local.Year(), local.Month(), local.Day(), kiteTokenResetHour, 0, 0, 0, istLocation)
+	if !reset.After(local) {
+		reset = reset.AddDate(0, 0, 1)
+	}
+	return reset.UTC()
+}
+
+func printAuthStatuses(ctx *commandContext, cmd *cobra.Command, statuses []authStatus) error {
+	printer := ctx.printer(cmd.OutOrStdout())
+	if printer.IsJSON() {
+		return printer.JSON(map[string]any{"profiles": statuses})
+	}
+	rows := make([][]string, 0, len(statuses))
+	for _, status := range statuses {
+		lastLogin, expiry := "-", "-"
+		if status.LastLoginAt != nil {
+			lastLogin = status.LastLoginAt.Format(time.RFC3339)
+		}
+		if status.PredictedExpiry != nil {
+			expiry = status.PredictedExpiry.In(istLocation).Format("2006-01-02 15:04 IST")
+		}
+		rows = append(rows, []string{
+			status.Profile,
+			strings.ToUpper(status.Status),
+			yesNo(status.APIKey),
+			yesNo(status.APISecret),
+			yesNo(status.AccessToken),
+			yesNo(status.RefreshToken),
+			lastLogin,
+			expiry,
+			dashIfEmpty(status.UserID),
+		})
+	}
+	return printer.Table([]string{"PROFILE", "STATUS", "API_KEY", "API_SECRET", "ACCESS", "REFRESH", "LAST_LOGIN", "EXPIRES", "USER_ID"}, rows)
+}
+
+// authStatusError picks the exit code: missing wins over expired, and an
+// unreachable API is reported as a network error.
+func authStatusError(statuses []authStatus) error {
+	var missing, expired, unknown []string
+	for _, status := range statuses {
+		switch status.Status {
+		case sessionMissing:
+			missing = append(missing, status.Profile)
+		case sessionExpired:
+			expired = append(expired, status.Profile)
+		case sessionUnknown:
+			if status.Checked {
+				unknown = append(unknown, status.Profile)
+			}
+		}
+	}
+	switch {
+	case len(missing) > 0:
+		return exitcode.New(exitcode.SessionMissing, fmt.Sprintf("no session for %s; run `zerodha auth login`", strings.Join(missing, ", ")))
+	case len(expired) > 0:
+		return exitcode.New(exitcode.SessionExpired, fmt.Sprintf("session expired for %s; run `zerodha auth login`", strings.Join(expired, ", ")))
+	case len(unknown) > 0:
+		return exitcode.New(exitcode.Network, fmt.Sprintf("could not verify the session for %s", strings.Join(unknown, ", ")))
+	}
+	return nil
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
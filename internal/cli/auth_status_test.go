@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+)
+
+func TestPredictTokenExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		lastLogin time.Time
+		want      time.Time
+	}{
+		{name: "morning login expires next day", lastLogin: time.Date(2026, 10, 16, 9, 15, 0, 0, istLocation), want: time.Date(2026, 10, 17, 6, 0, 0, 0, istLocation)},
+		{name: "late night login expires same morning", lastLogin: time.Date(2026, 10, 16, 2, 30, 0, 0, istLocation), want: time.Date(2026, 10, 16, 6, 0, 0, 0, istLocation)},
+		{name: "login at reset expires next day", lastLogin: time.Date(2026, 10, 16, 6, 0, 0, 0, istLocation), want: time.Date(2026, 10, 17, 6, 0, 0, 0, istLocation)},
+		{name: "utc input", lastLogin: time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 17, 6, 0, 0, 0, istLocation)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := predictTokenExpiry(tc.lastLogin); !got.Equal(tc.want) {
+				t.Fatalf("expected %s, got %s", tc.want, got.In(istLocation))
+			}
+		})
+	}
+}
+
+func TestAuthStatusAgainstFakeKite(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+	cfg := loadTestConfig(t, configPath)
+	profile := cfg.Profiles["default"]
+	profile.LastLoginAt = time.Now().UTC()
+	cfg.Profiles["default"] = profile
+	saveTestConfig(t, configPath, cfg)
+
+	stdout, _, err := executeCLICommand(t, configPath, "auth", "status", "--json")
+	if err != nil {
+		t.Fatalf("auth status: %v", err)
+	}
+	var result struct {
+		Profiles []authStatus `json:"profiles"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("decode status: %v\n%s", err, stdout)
+	}
+	if len(result.Profiles) != 1 {
+		t.Fatalf("expected one profile, got %+v", result.Profiles)
+	}
+	got := result.Profiles[0]
+	if got.Status != sessionValid || got.UserID != "AB1234" || !got.Checked || !got.RefreshToken || got.PredictedExpiry == nil {
+		t.Fatalf("unexpected status: %+v", got)
+	}
+
+	srv.ExpireAccessToken()
+	stdout, _, err = executeCLICommand(t, configPath, "auth", "status")
+	if exitcode.Code(err) != exitcode.SessionExpired || !strings.Contains(stdout, "EXPIRED") {
+		t.Fatalf("expected an expired session, err=%v out=%s", err, stdout)
+	}
+	if srv.Renewals() != 0 {
+		t.Fatalf("expected auth status not to refresh the token, got %d renewals", srv.Renewals())
+	}
+
+	// --offline trusts the predicted expiry, which is still in the future.
+	if _, _, err := executeCLICommand(t, configPath, "auth", "status", "--offline"); err != nil {
+		t.Fatalf("expected offline status to be valid, got %v", err)
+	}
+}
+
+func TestAuthStatusReportsWorstProfile(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+	cfg := loadTestConfig(t, configPath)
+	cfg.Profiles["stale"] = config.Profile{
+		APIKey:      "stale_key",
+		AccessToken: "stale_token",
+		LastLoginAt: time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC),
+		BaseURL:     srv.URL,
+	}
+	cfg.Profiles["new"] = config.Profile{APIKey: "new_key", APISecret: "new_secret"}
+	saveTestConfig(t, configPath, cfg)
+
+	stdout, _, err := executeCLICommand(t, configPath, "auth", "status", "--offline")
+	if exitcode.Code(err) != exitcode.SessionMissing {
+		t.Fatalf("expected the missing session to win, got %v", err)
+	}
+	for _, want := range []string{"MISSING", "EXPIRED", "UNKNOWN"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, stdout)
+		}
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "auth", "status", "--offline", "--profile", "stale"); exitcode.Code(err) != exitcode.SessionExpired {
+		t.Fatalf("expected the stale profile to be expired, got %v", err)
+	}
+}
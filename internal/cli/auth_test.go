@@ -83,6 +83,21 @@ func TestAuthLoginValidationRules(t *testing.T) {
 			args:     []string{"auth", "login", "--callback", "--callback-port", "65536"},
 			errMatch: "--callback-port must be between 1 and 65535",
 		},
+		{
+			name:     "rejects timeout without callback",
+			args:     []string{"auth", "login", "--request-token", "abc", "--timeout", "30s"},
+			errMatch: "--timeout can only be used with --callback",
+		},
+		{
+			name:     "rejects open without callback",
+			args:     []string{"auth", "login", "--request-token", "abc", "--open"},
+			errMatch: "--open can only be used with --callback",
+		},
+		{
+			name:     "rejects non-positive timeout",
+			args:     []string{"auth", "login", "--callback", "--timeout", "0s"},
+			errMatch: "--timeout must be greater than 0",
+		},
 	}
 
 	for _, tc := range tests {
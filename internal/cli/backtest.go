@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/backtest"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultBacktestChartHeight = 12
+	backtestChartPoints        = 80
+)
+
+func newBacktestCmd(opts *rootOptions) *cobra.Command {
+	var (
+		btStrategy    string
+		btInstrument  string
+		btInterval    string
+		btFrom        string
+		btTo          string
+		btContinuous  bool
+		btChartHeight int
+		btNoChart     bool
+	)
+	backtestCmd := &cobra.Command{
+		Use:   "backtest",
+		Short: "Backtest a rule-based strategy over cached candles",
+		Long: strings.Join([]string{
+			"Replays a YAML strategy over candles in the local candle store and reports trades, P&L, win rate and drawdown.",
+			"Runs fully offline: fetch candles first with `quote historical --instrument EXCHANGE:SYMBOL` (or `quote indicators`),",
+			"which saves them to the store. Signals are evaluated on each candle's close and filled at the next open.",
+		}, " "),
+		Example: strings.Join([]string{
+			"  # strategy.yaml",
+			"  name: ema-crossover",
+			"  entry: [\"ema:9 crosses_above ema:21\"]",
+			"  exit: [\"ema:9 crosses_below ema:21\"]",
+			"  stop_loss: 1%",
+			"  target: 2%",
+			"  quantity: 10",
+			"  slippage: 0.05%",
+			"  charges: equity-intraday",
+			"  intraday: true",
+			"",
+			"  zerodha backtest --strategy strategy.yaml --instrument NSE:INFY --interval 15minute --from 2026-01-01 --to 2026-06-30",
+		}, "\n"),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(btStrategy) == "" {
+				return exitcode.New(exitcode.Validation, "--strategy is required")
+			}
+			instrument := strings.TrimSpace(btInstrument)
+			if instrument == "" {
+				return exitcode.New(exitcode.Validation, "--instrument is required")
+			}
+			interval := strings.TrimSpace(btInterval)
+			if interval == "" {
+				return exitcode.New(exitcode.Validation, "--interval is required")
+			}
+			from, err := parseHistoricalTime(btFrom, "--from")
+			if err != nil {
+				return err
+			}
+			to, err := parseHistoricalTime(btTo, "--to")
+			if err != nil {
+				return err
+			}
+			if from.After(to) {
+				return exitcode.New(exitcode.Validation, "--from must be before or equal to --to")
+			}
+			if btChartHeight < 3 {
+				return exitcode.New(exitcode.Validation, "--chart-height must be at least 3")
+			}
+
+			data, err := os.ReadFile(btStrategy)
+			if err != nil {
+				return exitcode.Wrap(exitcode.Validation, "read strategy file", err)
+			}
+			strategy, err := backtest.ParseStrategy(data)
+			if err != nil {
+				return exitcode.Wrap(exitcode.Validation, "invalid strategy", err)
+			}
+			if strategy.Name == "" {
+				strategy.Name = strings.TrimSuffix(filepath.Base(btStrategy), filepath.Ext(btStrategy))
+			}
+
+			store := newCandleStore()
+			token, ok := store.lookupToken(instrument)
+			if !ok {
+				return exitcode.New(exitcode.Validation, fmt.Sprintf("no cached candles for %s; fetch them with `zerodha quote historical --instrument %s --interval %s --from ... --to ...`", instrument, strings.ToUpper(instrument), interval))
+			}
+			candles := store.load(token, interval, btContinuous, istWallClock(from), istWallClock(to))
+			if len(candles) == 0 {
+				return exitcode.New(exitcode.Validation, fmt.Sprintf("no cached %s candles for %s between %s and %s; fetch them with `zerodha quote historical`", interval, instrument, btFrom, btTo))
+			}
+
+			report, err := backtest.Run(strategy, indicatorCandles(candles))
+			if err != nil {
+				return exitcode.Wrap(exitcode.Validation, "run backtest", err)
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(report)
+			}
+			return printBacktestReport(cmd, printer, report, btChartHeight, btNoChart)
+		},
+	}
+	backtestCmd.Flags().StringVar(&btStrategy, "strategy", "", "Strategy YAML file")
+	backtestCmd.Flags().StringVar(&btInstrument, "instrument", "", "Instrument as EXCHANGE:SYMBOL or instrument token")
+	backtestCmd.Flags().StringVar(&btInterval, "interval", "", "Candle interval of the cached data (minute, 3minute, ..., 60minute, day)")
+	backtestCmd.Flags().StringVar(&btFrom, "from", "", "Start timestamp (YYYY-MM-DD, YYYY-MM-DD HH:MM:SS, or RFC3339)")
+	backtestCmd.Flags().StringVar(&btTo, "to", "", "End timestamp (YYYY-MM-DD, YYYY-MM-DD HH:MM:SS, or RFC3339)")
+	backtestCmd.Flags().BoolVar(&btContinuous, "continuous", false, "Use candles fetched with --continuous")
+	backtestCmd.Flags().IntVar(&btChartHeight, "chart-height", defaultBacktestChartHeight, "Rows in the equity chart")
+	backtestCmd.Flags().BoolVar(&btNoChart, "no-chart", false, "Skip the equity chart")
+	return backtestCmd
+}
+
+func printBacktestReport(cmd *cobra.Command, printer output.Printer, report *backtest.Report, height int, noChart bool) error {
+	out := cmd.OutOrStdout()
+	first, last := report.Equity[0].Time, report.Equity[len(report.Equity)-1].Time
+	if err := printer.KV([][2]string{
+		{"strategy", report.Strategy},
+		{"period", first.Format(time.DateTime) + " to " + last.Format(time.DateTime)},
+		{"bars", intToString(report.Bars)},
+		{"trades", intToString(len(report.Trades))},
+		{"win_rate", formatFloat(report.WinRate) + "%"},
+		{"gross_pnl", formatFloat(report.GrossPnL)},
+		{"charges", formatFloat(report.Charges)},
+		{"net_pnl", formatFloat(report.NetPnL)},
+		{"return", formatFloat(report.ReturnPercent) + "%"},
+		{"max_drawdown", formatFloat(report.MaxDrawdown) + " (" + formatFloat(report.MaxDrawdownPct) + "%)"},
+		{"final_equity", formatFloat(report.FinalEquity)},
+	}); err != nil {
+		return err
+	}
+
+	if !noChart {
+		xs, ys := backtestChartSeries(report)
+		if _, err := fmt.Fprintf(out, "\nEquity P&L by bar\n"); err != nil {
+			return err
+		}
+		if err := printer.Chart(xs, ys, height); err != nil {
+			return err
+		}
+	}
+
+	if len(report.Trades) == 0 {
+		_, err := fmt.Fprintln(out, "\nNo trades.")
+		return err
+	}
+	if _, err := fmt.Fprintln(out); err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(report.Trades))
+	for _, trade := range report.Trades {
+		rows = append(rows, []string{
+			trade.EntryTime.Format(time.DateTime),
+			trade.Side,
+			intToString(trade.Quantity),
+			formatFloat(trade.EntryPrice),
+			trade.ExitTime.Format(time.DateTime),
+			formatFloat(trade.ExitPrice),
+			trade.ExitReason,
+			formatFloat(trade.Charges),
+			formatFloat(trade.NetPnL),
+		})
+	}
+	return printer.Table([]string{"ENTRY_TIME", "SIDE", "QTY", "ENTRY", "EXIT_TIME", "EXIT", "REASON", "CHARGES", "NET_PNL"}, rows)
+}
+
+// backtestChartSeries samples the equity curve down to a terminal-friendly
+// width, always keeping the last bar.
+func backtestChartSeries(report *backtest.Report) ([]float64, []float64) {
+	step := max(1, (len(report.Equity)+backtestChartPoints-1)/backtestChartPoints)
+	var xs, ys []float64
+	for i := 0; i < len(report.Equity); i += step {
+		xs = append(xs, float64(i+1))
+		ys = append(ys, report.Equity[i].Equity-report.StartingCapital)
+	}
+	if lastIndex := len(report.Equity) - 1; lastIndex%step != 0 {
+		xs = append(xs, float64(lastIndex+1))
+		ys = append(ys, report.Equity[lastIndex].Equity-report.StartingCapital)
+	}
+	return xs, ys
+}
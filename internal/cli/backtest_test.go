@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/backtest"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/paths"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+func TestCandleStoreMergesAndFilters(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("HOME", cacheHome)
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, istLocation)
+	store := newCandleStore()
+	if err := store.save(408065, "day", false, "NSE:INFY", []kiteconnect.HistoricalData{
+		testCandle(day, 100, 101, 99, 100, 10, 5),
+		testCandle(day.AddDate(0, 0, 1), 100, 102, 99, 101, 10, 0),
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// A later fetch overlaps one bar and replaces it, keeping the earlier OI.
+	if err := store.save(408065, "day", false, "", []kiteconnect.HistoricalData{
+		testCandle(day.UTC(), 100, 103, 99, 102, 20, 0),
+		testCandle(day.AddDate(0, 0, 2), 101, 104, 100, 103, 10, 0),
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	if token, ok := store.lookupToken("nse:infy"); !ok || token != 408065 {
+		t.Fatalf("expected NSE:INFY to resolve to 408065, got %d %v", token, ok)
+	}
+	if token, ok := store.lookupToken("12345"); !ok || token != 12345 {
+		t.Fatalf("expected numeric instrument to be used as token, got %d %v", token, ok)
+	}
+
+	from, _ := parseHistoricalTime("2026-10-01", "--from")
+	to, _ := parseHistoricalTime("2026-10-02", "--to")
+	candles := store.load(408065, "day", false, istWallClock(from), istWallClock(to))
+	if len(candles) != 2 {
+		t.Fatalf("expected 2 candles in range, got %+v", candles)
+	}
+	if candles[0].Close != 102 || candles[0].Volume != 20 || candles[0].OI != 5 {
+		t.Fatalf("expected merged first candle, got %+v", candles[0])
+	}
+	if len(store.load(408065, "15minute", false, istWallClock(from), istWallClock(to))) != 0 {
+		t.Fatalf("expected intervals to be stored separately")
+	}
+}
+
+func TestQuoteHistoricalWarnsWhenCandlesCannotBeStored(t *testing.T) {
+	_, configPath := newFakeKiteConfig(t)
+	cacheDir := filepath.Join(os.Getenv("XDG_CACHE_HOME"), paths.AppName)
+	if err := os.MkdirAll(cacheDir, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "candles"), nil, 0o600); err != nil {
+		t.Fatalf("block candle store: %v", err)
+	}
+
+	stdout, stderr, err := executeCLICommand(t, configPath, "quote", "historical", "--instrument", "NSE:INFY", "--interval", "day", "--from", "2026-10-12", "--to", "2026-10-16")
+	if err != nil {
+		t.Fatalf("quote historical: %v", err)
+	}
+	if !strings.Contains(stdout, "2026-10-12") {
+		t.Fatalf("expected candles to still be printed, got:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "warning: could not store candles") {
+		t.Fatalf("expected a store warning, got %q", stderr)
+	}
+}
+
+func TestBacktestCommandRunsOffline(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("HOME", cacheHome)
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, istLocation)
+	closes := []float64{95, 101, 104, 110, 96, 100.5, 92, 93}
+	var candles []kiteconnect.HistoricalData
+	for i, price := range closes {
+		candles = append(candles, testCandle(day.AddDate(0, 0, i), price, price+3, price-3, price, 100, 0))
+	}
+	if err := newCandleStore().save(408065, "day", false, "NSE:INFY", candles); err != nil {
+		t.Fatalf("seed candle store: %v", err)
+	}
+
+	dir := t.TempDir()
+	strategyPath := filepath.Join(dir, "breakout.yaml")
+	strategy := "entry: [\"close crosses_above 100\"]\nexit: [\"close < 100\"]\nquantity: 1\ncharges: none\n"
+	if err := os.WriteFile(strategyPath, []byte(strategy), 0o600); err != nil {
+		t.Fatalf("write strategy: %v", err)
+	}
+	configPath := filepath.Join(dir, "config.json")
+
+	args := []string{"backtest", "--strategy", strategyPath, "--instrument", "NSE:INFY", "--interval", "day", "--from", "2026-10-01", "--to", "2026-10-31"}
+	stdout, _, err := executeCLICommand(t, configPath, append(args, "--json")...)
+	if err != nil {
+		t.Fatalf("backtest: %v", err)
+	}
+	var report backtest.Report
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("decode report: %v\n%s", err, stdout)
+	}
+	if report.Strategy != "breakout" || report.Bars != len(closes) || len(report.Trades) != 2 || len(report.Equity) != len(closes) {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	// Candles open at their close: long at 104 after the first cross, out at
+	// 100.5 after the close below 100, then long at 92 and out at 93.
+	if report.Trades[0].EntryPrice != 104 || report.Trades[0].ExitPrice != 100.5 || report.Trades[1].ExitPrice != 93 || report.Trades[1].ExitReason != backtest.ExitSignal {
+		t.Fatalf("unexpected trades: %+v", report.Trades)
+	}
+
+	stdout, _, err = executeCLICommand(t, configPath, args...)
+	if err != nil {
+		t.Fatalf("backtest table: %v", err)
+	}
+	for _, want := range []string{"win_rate", "max_drawdown", "Equity P&L by bar", "ENTRY_TIME", "signal"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, stdout)
+		}
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "backtest", "--strategy", strategyPath, "--instrument", "NSE:TCS", "--interval", "day", "--from", "2026-10-01", "--to", "2026-10-31"); err == nil || !strings.Contains(err.Error(), "quote historical") {
+		t.Fatalf("expected a hint to fetch candles, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/cache"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/paths"
+	"github.com/spf13/cobra"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+	"github.com/zerodha/gokiteconnect/v4/models"
+)
+
+// candleStore keeps every historical candle fetched by quote historical and
+// quote indicators, merged per instrument and interval, so backtests can run
+// offline.
+type candleStore struct {
+	store *cache.FSStore
+	err   error
+}
+
+const candleIndexKey = "candles:index"
+
+// storedCandle keeps the timestamp as a string since models.Time does not
+// round-trip through JSON.
+type storedCandle struct {
+	Date   string  `json:"t"`
+	Open   float64 `json:"o"`
+	High   float64 `json:"h"`
+	Low    float64 `json:"l"`
+	Close  float64 `json:"c"`
+	Volume int     `json:"v"`
+	OI     int     `json:"oi,omitempty"`
+}
+
+func newCandleStore() *candleStore {
+	cacheDir, err := paths.DefaultCacheDir()
+	if err != nil {
+		return &candleStore{err: err}
+	}
+	return &candleStore{store: cache.NewFSStore(filepath.Join(cacheDir, "candles"))}
+}
+
+func candleStoreKey(token int, interval string, continuous bool) string {
+	key := "candles:" + strconv.Itoa(token) + ":" + interval
+	if continuous {
+		key += ":continuous"
+	}
+	return key
+}
+
+func (s *candleStore) read(key string) map[string]storedCandle {
+	candles := make(map[string]storedCandle)
+	if s.store == nil {
+		return candles
+	}
+	data, err := s.store.Get(key)
+	if err != nil {
+		return candles
+	}
+	var stored []storedCandle
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return candles
+	}
+	for _, candle := range stored {
+		candles[candle.Date] = candle
+	}
+	return candles
+}
+
+// save merges candles into the store, newer fetches replacing older bars.
+// instrument is the EXCHANGE:SYMBOL key when known, recorded so backtests can
+// find the token offline.
+func (s *candleStore) save(token int, interval string, continuous bool, instrument string, candles []kiteconnect.HistoricalData) error {
+	if s.store == nil {
+		return s.err
+	}
+	if len(candles) == 0 {
+		return nil
+	}
+	key := candleStoreKey(token, interval, continuous)
+	merged := s.read(key)
+	for _, candle := range candles {
+		date := candle.Date.Time.In(istLocation).Format(time.RFC3339)
+		// Fetches without --oi report zero OI; keep what an earlier fetch saw.
+		if candle.OI == 0 {
+			candle.OI = merged[date].OI
+		}
+		merged[date] = storedCandle{
+			Date:   date,
+			Open:   candle.Open,
+			High:   candle.High,
+			Low:    candle.Low,
+			Close:  candle.Close,
+			Volume: candle.Volume,
+			OI:     candle.OI,
+		}
+	}
+	stored := make([]storedCandle, 0, len(merged))
+	for _, date := range slices.Sorted(maps.Keys(merged)) {
+		stored = append(stored, merged[date])
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Put(key, data); err != nil {
+		return err
+	}
+
+	if instrument == "" {
+		return nil
+	}
+	index := s.index()
+	if index[instrument] == token {
+		return nil
+	}
+	index[instrument] = token
+	data, err = json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(candleIndexKey, data)
+}
+
+// storeCandles saves fetched candles for backtests and paper replay. Failing
+// to store them does not fail the command, but is reported so a later "no
+// stored candles" has an explanation.
+func storeCandles(cmd *cobra.Command, token int, interval string, continuous bool, instrument string, candles []kiteconnect.HistoricalData) {
+	if err := newCandleStore().save(token, interval, continuous, instrument, candles); err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: could not store candles for backtests: %v\n", err)
+	}
+}
+
+// load returns stored candles with from <= date <= to, oldest first.
+func (s *candleStore) load(token int, interval string, continuous bool, from, to time.Time) []kiteconnect.HistoricalData {
+	stored := s.read(candleStoreKey(token, interval, continuous))
+	var candles []kiteconnect.HistoricalData
+	for _, date := range slices.Sorted(maps.Keys(stored)) {
+		at, err := time.Parse(time.RFC3339, date)
+		if err != nil || at.Before(from) || at.After(to) {
+			continue
+		}
+		candle := stored[date]
+		candles = append(candles, kiteconnect.HistoricalData{
+			Date:   models.Time{Time: at},
+			Open:   candle.Open,
+			High:   candle.High,
+			Low:    candle.Low,
+			Close:  candle.Close,
+			Volume: candle.Volume,
+			OI:     candle.OI,
+		})
+	}
+	return candles
+}
+
+func (s *candleStore) index() map[string]int {
+	index := make(map[string]int)
+	if s.store == nil {
+		return index
+	}
+	if data, err := s.store.Get(candleIndexKey); err == nil {
+		_ = json.Unmarshal(data, &index)
+	}
+	return index
+}
+
+// lookupToken resolves an instrument token or a stored EXCHANGE:SYMBOL key.
+func (s *candleStore) lookupToken(instrument string) (int, bool) {
+	value := strings.TrimSpace(instrument)
+	if token, err := strconv.Atoi(value); err == nil && token > 0 {
+		return token, true
+	}
+	token, ok := s.index()[strings.ToUpper(value)]
+	return token, ok
+}
+
+// istWallClock reinterprets a parsed --from/--to value in IST, which is how
+// the historical API reads the timestamps it is sent.
+func istWallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, istLocation)
+}
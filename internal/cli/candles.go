@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/parquet"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+	"github.com/zerodha/gokiteconnect/v4/models"
+)
+
+const (
+	resampleWeek  = "week"
+	resampleMonth = "month"
+	resampleDay   = "day"
+)
+
+// defaultSessionStart anchors intraday buckets at the NSE/BSE open, so 2h
+// candles are 09:15-11:15, 11:15-13:15 and so on within each day.
+const defaultSessionStart = "09:15"
+
+// candleResample is a parsed --resample value: a fixed intraday width, or a
+// calendar period (day, week, month) when width is zero.
+type candleResample struct {
+	width  time.Duration
+	period string
+}
+
+var historicalIntervals = map[string]time.Duration{
+	"minute":   time.Minute,
+	"3minute":  3 * time.Minute,
+	"5minute":  5 * time.Minute,
+	"10minute": 10 * time.Minute,
+	"15minute": 15 * time.Minute,
+	"30minute": 30 * time.Minute,
+	"60minute": time.Hour,
+	"day":      24 * time.Hour,
+}
+
+func parseCandleResample(raw, interval string) (candleResample, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	source, known := historicalIntervals[interval]
+
+	var target candleResample
+	switch value {
+	case resampleDay, "1d", "d":
+		target.period = resampleDay
+	case resampleWeek, "1w", "w":
+		target.period = resampleWeek
+	case resampleMonth, "1mo", "mo":
+		target.period = resampleMonth
+	default:
+		width, err := time.ParseDuration(value)
+		if err != nil || width <= 0 || width%time.Minute != 0 {
+			return target, exitcode.New(exitcode.Validation, "--resample must be a whole-minute duration (e.g. 2h, 45m), day, week or month")
+		}
+		if width >= 24*time.Hour {
+			return target, exitcode.New(exitcode.Validation, "--resample durations must be under a day; use day, week or month")
+		}
+		if known && (source == 24*time.Hour || width <= source || width%source != 0) {
+			return target, exitcode.New(exitcode.Validation, fmt.Sprintf("--resample %s must be a larger multiple of the %s interval", value, interval))
+		}
+		target.width = width
+		return target, nil
+	}
+	if known && target.period == resampleDay && source == 24*time.Hour {
+		return target, exitcode.New(exitcode.Validation, "--resample day needs an intraday --interval")
+	}
+	return target, nil
+}
+
+// bucket returns the IST start of the candle's bucket.
+func (r candleResample) bucket(t time.Time, sessionStart time.Duration) time.Time {
+	t = t.In(istLocation)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, istLocation)
+	switch r.period {
+	case resampleDay:
+		return day
+	case resampleWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case resampleMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, istLocation)
+	}
+	anchor := day.Add(sessionStart)
+	offset := t.Sub(anchor)
+	n := offset / r.width
+	if offset < 0 && offset%r.width != 0 {
+		n--
+	}
+	return anchor.Add(n * r.width)
+}
+
+// resampleCandles aggregates candles into buckets: first open, highest high,
+// lowest low, last close, summed volume and last open interest. Intraday
+// buckets never cross a trading day.
+func resampleCandles(candles []kiteconnect.HistoricalData, r candleResample, sessionStart time.Duration) []kiteconnect.HistoricalData {
+	var out []kiteconnect.HistoricalData
+	var current time.Time
+	for _, candle := range candles {
+		start := r.bucket(candle.Date.Time, sessionStart)
+		if len(out) == 0 || !start.Equal(current) {
+			current = start
+			candle.Date = models.Time{Time: start}
+			out = append(out, candle)
+			continue
+		}
+		agg := &out[len(out)-1]
+		agg.High = max(agg.High, candle.High)
+		agg.Low = min(agg.Low, candle.Low)
+		agg.Close = candle.Close
+		agg.Volume += candle.Volume
+		agg.OI = candle.OI
+	}
+	return out
+}
+
+func parseSessionStart(raw string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(raw))
+	if err != nil {
+		return 0, exitcode.New(exitcode.Validation, "--session-start must be HH:MM")
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func candleExportFormat(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv", ".parquet":
+		return ext[1:], nil
+	default:
+		return "", exitcode.New(exitcode.Validation, "--export file must end in .csv or .parquet")
+	}
+}
+
+func exportCandles(path, format string, candles []kiteconnect.HistoricalData) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return exitcode.Wrap(exitcode.Validation, "create export file", err)
+	}
+	if format == "parquet" {
+		err = writeCandlesParquet(f, candles)
+	} else {
+		err = writeCandlesCSV(f, candles)
+	}
+	if err != nil {
+		_ = f.Close()
+		return exitcode.Wrap(exitcode.Internal, "write export file", err)
+	}
+	return f.Close()
+}
+
+func writeCandlesCSV(out io.Writer, candles []kiteconnect.HistoricalData) error {
+	writer := csv.NewWriter(out)
+	if err := writer.Write([]string{"date", "open", "high", "low", "close", "volume", "oi"}); err != nil {
+		return err
+	}
+	for _, candle := range candles {
+		record := []string{
+			candle.Date.Time.In(istLocation).Format(time.RFC3339),
+			strconv.FormatFloat(candle.Open, 'f', -1, 64),
+			strconv.FormatFloat(candle.High, 'f', -1, 64),
+			strconv.FormatFloat(candle.Low, 'f', -1, 64),
+			strconv.FormatFloat(candle.Close, 'f', -1, 64),
+			strconv.Itoa(candle.Volume),
+			strconv.Itoa(candle.OI),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeCandlesParquet(out io.Writer, candles []kiteconnect.HistoricalData) error {
+	n := len(candles)
+	dates := make([]int64, n)
+	opens, highs, lows, closes := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	volumes, ois := make([]int64, n), make([]int64, n)
+	for i, candle := range candles {
+		dates[i] = candle.Date.Time.UnixMilli()
+		opens[i], highs[i], lows[i], closes[i] = candle.Open, candle.High, candle.Low, candle.Close
+		volumes[i], ois[i] = int64(candle.Volume), int64(candle.OI)
+	}
+	return parquet.Write(out, []parquet.Column{
+		{Name: "date", Type: parquet.TimestampMillis, Int64s: dates},
+		{Name: "open", Type: parquet.Double, Doubles: opens},
+		{Name: "high", Type: parquet.Double, Doubles: highs},
+		{Name: "low", Type: parquet.Double, Doubles: lows},
+		{Name: "close", Type: parquet.Double, Doubles: closes},
+		{Name: "volume", Type: parquet.Int64, Int64s: volumes},
+		{Name: "oi", Type: parquet.Int64, Int64s: ois},
+	})
+}
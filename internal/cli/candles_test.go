@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+	"github.com/zerodha/gokiteconnect/v4/models"
+)
+
+func testCandle(at time.Time, open, high, low, close float64, volume, oi int) kiteconnect.HistoricalData {
+	return kiteconnect.HistoricalData{
+		Date:   models.Time{Time: at},
+		Open:   open,
+		High:   high,
+		Low:    low,
+		Close:  close,
+		Volume: volume,
+		OI:     oi,
+	}
+}
+
+func TestResampleCandlesIntradayRespectsSessions(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, istLocation)
+	at := func(d int, hhmm string) time.Time {
+		clock, _ := time.Parse("15:04", hhmm)
+		return day.AddDate(0, 0, d).Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute)
+	}
+	candles := []kiteconnect.HistoricalData{
+		testCandle(at(0, "09:15"), 100, 105, 99, 104, 10, 500),
+		testCandle(at(0, "10:15"), 104, 110, 103, 108, 20, 520),
+		testCandle(at(0, "11:15"), 108, 109, 101, 102, 5, 510),
+		testCandle(at(0, "15:15"), 102, 103, 100, 101, 7, 505),
+		// Same wall-clock bucket on the next day must not merge into the last one.
+		testCandle(at(1, "09:15"), 90, 95, 89, 94, 3, 400),
+	}
+
+	resample, err := parseCandleResample("2h", "60minute")
+	if err != nil {
+		t.Fatalf("parse resample: %v", err)
+	}
+	got := resampleCandles(candles, resample, 9*time.Hour+15*time.Minute)
+	if len(got) != 4 {
+		t.Fatalf("expected 4 buckets, got %d: %+v", len(got), got)
+	}
+
+	first := got[0]
+	if !first.Date.Time.Equal(at(0, "09:15")) || first.Open != 100 || first.High != 110 || first.Low != 99 || first.Close != 108 || first.Volume != 30 || first.OI != 520 {
+		t.Fatalf("unexpected first bucket: %+v", first)
+	}
+	if !got[1].Date.Time.Equal(at(0, "11:15")) || !got[2].Date.Time.Equal(at(0, "15:15")) || !got[3].Date.Time.Equal(at(1, "09:15")) {
+		t.Fatalf("unexpected bucket starts: %v %v %v", got[1].Date.Time, got[2].Date.Time, got[3].Date.Time)
+	}
+}
+
+func TestResampleCandlesWeekAndMonth(t *testing.T) {
+	// Kite daily candles are stamped at midnight IST; parse them in UTC to
+	// check bucketing happens in IST rather than the candle's zone.
+	var candles []kiteconnect.HistoricalData
+	for i, date := range []string{"2026-09-28", "2026-09-30", "2026-10-01", "2026-10-05"} {
+		d, _ := time.ParseInLocation("2006-01-02", date, istLocation)
+		candles = append(candles, testCandle(d.UTC(), float64(100+i), float64(110+i), float64(90+i), float64(105+i), 10, 0))
+	}
+
+	week, err := parseCandleResample("week", "day")
+	if err != nil {
+		t.Fatalf("parse week: %v", err)
+	}
+	weeks := resampleCandles(candles, week, 0)
+	if len(weeks) != 2 || weeks[0].Date.Time.Format("2006-01-02") != "2026-09-28" || weeks[0].Close != 107 || weeks[0].Volume != 30 {
+		t.Fatalf("unexpected weekly candles: %+v", weeks)
+	}
+	if weeks[1].Date.Time.Format("2006-01-02") != "2026-10-05" {
+		t.Fatalf("expected second week to start 2026-10-05, got %v", weeks[1].Date.Time)
+	}
+
+	month, err := parseCandleResample("month", "day")
+	if err != nil {
+		t.Fatalf("parse month: %v", err)
+	}
+	months := resampleCandles(candles, month, 0)
+	if len(months) != 2 || months[0].Open != 100 || months[0].Close != 106 || months[1].Open != 102 || months[1].High != 113 {
+		t.Fatalf("unexpected monthly candles: %+v", months)
+	}
+}
+
+func TestParseCandleResampleValidation(t *testing.T) {
+	tests := []struct {
+		raw, interval string
+	}{
+		{"2h", "day"},
+		{"45m", "60minute"},
+		{"30m", "30minute"},
+		{"90s", "minute"},
+		{"24h", "minute"},
+		{"day", "day"},
+		{"fortnight", "day"},
+	}
+	for _, tc := range tests {
+		if _, err := parseCandleResample(tc.raw, tc.interval); err == nil {
+			t.Fatalf("expected error for --resample %s on %s", tc.raw, tc.interval)
+		}
+	}
+	if _, err := parseCandleResample("75m", "15minute"); err != nil {
+		t.Fatalf("expected 75m on 15minute to be valid: %v", err)
+	}
+}
+
+func TestWriteCandlesCSV(t *testing.T) {
+	at := time.Date(2026, 10, 16, 9, 15, 0, 0, istLocation)
+	var out bytes.Buffer
+	if err := writeCandlesCSV(&out, []kiteconnect.HistoricalData{testCandle(at, 100, 105.5, 99.25, 104, 10, 7)}); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	want := "date,open,high,low,close,volume,oi\n2026-10-16T09:15:00+05:30,100,105.5,99.25,104,10,7\n"
+	if out.String() != want {
+		t.Fatalf("expected %q, got %q", want, out.String())
+	}
+
+	if _, err := candleExportFormat("candles.xlsx"); err == nil || !strings.Contains(err.Error(), ".parquet") {
+		t.Fatalf("expected unsupported extension error, got %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	passphraseEnvVar    = "ZERODHA_PASSPHRASE"
+	newPassphraseEnvVar = "ZERODHA_NEW_PASSPHRASE"
+	keyFileEnvVar       = "ZERODHA_KEY_FILE"
+)
+
+func newConfigEncryptCmd(opts *rootOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt API secrets and tokens in the config file with a passphrase",
+		Long: strings.Join([]string{
+			"Profile api_secret, access_token and refresh_token are encrypted with AES-GCM under a scrypt-derived key.",
+			"The passphrase comes from --key-file (or ZERODHA_KEY_FILE), ZERODHA_PASSPHRASE, or an interactive prompt, in that order.",
+		}, " "),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			if ctx.store.Encrypted() {
+				return exitcode.New(exitcode.Validation, "config is already encrypted; use `zerodha config rekey` to change the passphrase")
+			}
+			passphrase, err := newConfigPassphrase(opts.keyFile, passphraseEnvVar)
+			if err != nil {
+				return err
+			}
+			if err := ctx.store.EnableEncryption(passphrase); err != nil {
+				return exitcode.Wrap(exitcode.Config, "enable config encryption", err)
+			}
+			if err := ctx.save(); err != nil {
+				return err
+			}
+			return printEncryptionStatus(ctx, cmd)
+		},
+	}
+}
+
+func newConfigDecryptCmd(opts *rootOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "decrypt",
+		Short: "Store secrets in the config file as plaintext again",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			if !ctx.store.Encrypted() {
+				return exitcode.New(exitcode.Validation, "config is not encrypted")
+			}
+			ctx.store.DisableEncryption()
+			if err := ctx.save(); err != nil {
+				return err
+			}
+			return printEncryptionStatus(ctx, cmd)
+		},
+	}
+}
+
+func newConfigRekeyCmd(opts *rootOptions) *cobra.Command {
+	var newKeyFile string
+	cmd := &cobra.Command{
+		Use:   "rekey",
+		Short: "Re-encrypt the config file under a new passphrase",
+		Long:  "The current passphrase is read as for any command; the new one comes from --new-key-file, ZERODHA_NEW_PASSPHRASE, or a prompt.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			if !ctx.store.Encrypted() {
+				return exitcode.New(exitcode.Validation, "config is not encrypted; use `zerodha config encrypt`")
+			}
+			passphrase, err := newConfigPassphrase(newKeyFile, newPassphraseEnvVar)
+			if err != nil {
+				return err
+			}
+			if err := ctx.store.EnableEncryption(passphrase); err != nil {
+				return exitcode.Wrap(exitcode.Config, "rekey config", err)
+			}
+			if err := ctx.save(); err != nil {
+				return err
+			}
+			return printEncryptionStatus(ctx, cmd)
+		},
+	}
+	cmd.Flags().StringVar(&newKeyFile, "new-key-file", "", "File holding the new passphrase")
+	return cmd
+}
+
+func printEncryptionStatus(ctx *commandContext, cmd *cobra.Command) error {
+	printer := ctx.printer(cmd.OutOrStdout())
+	if printer.IsJSON() {
+		return printer.JSON(map[string]any{
+			"status":      "ok",
+			"encrypted":   ctx.store.Encrypted(),
+			"config_path": ctx.store.Path(),
+		})
+	}
+	return printer.KV([][2]string{
+		{"status", "ok"},
+		{"encrypted", fmt.Sprintf("%t", ctx.store.Encrypted())},
+		{"config_path", ctx.store.Path()},
+	})
+}
+
+// configPassphrase unlocks an encrypted config: --key-file (or
+// ZERODHA_KEY_FILE), then ZERODHA_PASSPHRASE, then a terminal prompt.
+func configPassphrase(keyFile string) config.PassphraseFunc {
+	return func() ([]byte, error) {
+		if path := strings.TrimSpace(keyFile); path != "" {
+			return readKeyFile(path)
+		}
+		if value := os.Getenv(passphraseEnvVar); value != "" {
+			return []byte(value), nil
+		}
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return nil, fmt.Errorf("%w; set %s, %s or pass --key-file", config.ErrPassphraseRequired, passphraseEnvVar, keyFileEnvVar)
+		}
+		return promptPassphrase("Config passphrase: ")
+	}
+}
+
+// newConfigPassphrase reads a passphrase to encrypt with. A prompt asks twice
+// so a typo cannot lock the config.
+func newConfigPassphrase(keyFile, envVar string) ([]byte, error) {
+	if path := strings.TrimSpace(keyFile); path != "" {
+		return readKeyFile(path)
+	}
+	if value := os.Getenv(envVar); value != "" {
+		return []byte(value), nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, exitcode.New(exitcode.Validation, fmt.Sprintf("no new passphrase; set %s or pass a key file", envVar))
+	}
+	first, err := promptPassphrase("New passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	second, err := promptPassphrase("Repeat passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(first, second) {
+		return nil, exitcode.New(exitcode.Validation, "passphrases do not match")
+	}
+	return first, nil
+}
+
+func readKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, exitcode.Wrap(exitcode.Config, "read key file", err)
+	}
+	data = bytes.TrimRight(data, "\r\n")
+	if len(data) == 0 {
+		return nil, exitcode.New(exitcode.Config, "key file is empty")
+	}
+	return data, nil
+}
+
+func promptPassphrase(prompt string) ([]byte, error) {
+	if _, err := fmt.Fprint(os.Stderr, prompt); err != nil {
+		return nil, err
+	}
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, exitcode.Wrap(exitcode.Internal, "read passphrase", err)
+	}
+	if len(passphrase) == 0 {
+		return nil, exitcode.New(exitcode.Validation, "passphrase cannot be empty")
+	}
+	return passphrase, nil
+}
+
+func isConfigLocked(err error) bool {
+	return errors.Is(err, config.ErrWrongPassphrase) || errors.Is(err, config.ErrPassphraseRequired)
+}
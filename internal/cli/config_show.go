@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultKiteBaseURL = "https://api.kite.trade"
+
+type configSetting struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source,omitempty"`
+}
+
+func newConfigShowCmd(opts *rootOptions) *cobra.Command {
+	var effective bool
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show config settings with secrets redacted",
+		Long:  "With --effective, prints the settings commands would actually use after flags, ZERODHA_* environment variables and credential sources are applied, and where each came from.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+
+			var settings []configSetting
+			if effective {
+				settings, err = effectiveSettings(ctx)
+				if err != nil {
+					return err
+				}
+			} else {
+				settings = storedSettings(ctx)
+			}
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(map[string]any{
+					"config_path": ctx.store.Path(),
+					"effective":   effective,
+					"settings":    settings,
+				})
+			}
+			rows := make([][]string, 0, len(settings))
+			for _, setting := range settings {
+				rows = append(rows, []string{setting.Name, dashIfEmpty(setting.Value), setting.Source})
+			}
+			if effective {
+				return printer.Table([]string{"SETTING", "VALUE", "SOURCE"}, rows)
+			}
+			for i := range rows {
+				rows[i] = rows[i][:2]
+			}
+			return printer.Table([]string{"SETTING", "VALUE"}, rows)
+		},
+	}
+	cmd.Flags().BoolVar(&effective, "effective", false, "Show resolved settings and where each came from")
+	return cmd
+}
+
+func storedSettings(ctx *commandContext) []configSetting {
+	settings := []configSetting{
+		{Name: "active_profile", Value: ctx.cfg.ActiveProfile},
+		{Name: "profiles", Value: strings.Join(ctx.profileNames(), ",")},
+		{Name: "risk_free_rate", Value: riskFreeRateValue(ctx.cfg.RiskFreeRate)},
+		{Name: "encrypted", Value: fmt.Sprintf("%t", ctx.store.Encrypted())},
+	}
+	for _, name := range ctx.profileNames() {
+		profile := ctx.cfg.Profiles[name]
+		prefix := "profiles." + name + "."
+		settings = append(settings, configSetting{Name: prefix + "api_key", Value: profile.APIKey})
+		for _, field := range credentialFieldNames[1:] {
+			settings = append(settings, configSetting{Name: prefix + field, Value: redactSecret(*credentialField(&profile, field))})
+		}
+		settings = append(settings, configSetting{Name: prefix + "base_url", Value: profile.BaseURL})
+	}
+	return settings
+}
+
+func effectiveSettings(ctx *commandContext) ([]configSetting, error) {
+	opts := ctx.opts
+	output := "table"
+	if opts.outputJSON {
+		output = "json"
+	}
+	settings := []configSetting{
+		{Name: "config", Value: ctx.store.Path(), Source: opts.origins["config"]},
+		{Name: "output", Value: output, Source: opts.origins["json"]},
+		{Name: "debug", Value: fmt.Sprintf("%t", opts.debug), Source: opts.origins["debug"]},
+		{Name: "paper", Value: fmt.Sprintf("%t", opts.paper), Source: opts.origins["paper"]},
+		{Name: "key_file", Value: opts.keyFile, Source: opts.origins["key-file"]},
+		{Name: "encrypted", Value: fmt.Sprintf("%t", ctx.store.Encrypted()), Source: "config"},
+	}
+
+	rate, rateSource := defaultRiskFreeRate, "default"
+	if ctx.cfg.RiskFreeRate != nil {
+		rate, rateSource = *ctx.cfg.RiskFreeRate, "config"
+	}
+	settings = append(settings, configSetting{Name: "risk_free_rate", Value: formatFloat(rate), Source: rateSource})
+
+	name, profile, err := ctx.resolveProfile(false)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return append(settings, configSetting{Name: "profile", Source: "unset"}), nil
+	}
+	profileSource := opts.origins["profile"]
+	if profileSource != "flag" && profileSource != "env" {
+		switch {
+		case strings.TrimSpace(ctx.cfg.ActiveProfile) != "":
+			profileSource = "config"
+		case len(ctx.cfg.Profiles) == 0:
+			profileSource = "env"
+		default:
+			profileSource = "default"
+		}
+	}
+	settings = append(settings, configSetting{Name: "profile", Value: name, Source: profileSource})
+
+	stored := ctx.cfg.Profiles[name]
+	origins := ctx.credentialOrigins[name]
+	for _, field := range credentialFieldNames {
+		value := *credentialField(profile, field)
+		source := origins[field]
+		switch {
+		case source != "":
+		case value != "":
+			source = "config"
+		default:
+			source = "unset"
+		}
+		if strings.HasPrefix(source, credentialCmdPrefix) {
+			source = "cmd"
+		}
+		if field != "api_key" {
+			value = redactSecret(value)
+		}
+		settings = append(settings, configSetting{Name: field, Value: value, Source: source})
+	}
+
+	baseURLSource := "default"
+	switch {
+	case strings.TrimSpace(os.Getenv(kiteBaseURLEnvVar)) != "":
+		baseURLSource = "env"
+	case strings.TrimSpace(stored.BaseURL) != "":
+		baseURLSource = "config"
+	}
+	baseURL := kiteBaseURL(*profile)
+	if baseURL == "" {
+		baseURL = defaultKiteBaseURL
+	}
+	settings = append(settings, configSetting{Name: "kite_base_url", Value: baseURL, Source: baseURLSource})
+	return settings, nil
+}
+
+func riskFreeRateValue(rate *float64) string {
+	if rate == nil {
+		return ""
+	}
+	return formatFloat(*rate)
+}
+
+// redactSecret keeps only the last four characters so two values can still
+// be told apart.
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) <= 8 {
+		return "****"
+	}
+	return "****" + value[len(value)-4:]
+}
@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+)
+
+const (
+	credentialEnvPrefix = "env:"
+	credentialCmdPrefix = "cmd:"
+)
+
+var credentialFieldNames = []string{"api_key", "api_secret", "access_token", "refresh_token"}
+
+func credentialField(profile *config.Profile, field string) *string {
+	switch field {
+	case "api_key":
+		return &profile.APIKey
+	case "api_secret":
+		return &profile.APISecret
+	case "access_token":
+		return &profile.AccessToken
+	case "refresh_token":
+		return &profile.RefreshToken
+	}
+	return nil
+}
+
+// resolveCredentials overlays values from the profile's credential backends
+// and the ZERODHA_* credential variables. They are remembered so setProfile
+// can tell which ones changed.
+func (c *commandContext) resolveCredentials(name string, profile *config.Profile) error {
+	values, ok := c.credentials[name]
+	if !ok {
+		values = make(map[string]string)
+		origins := make(map[string]string)
+		if profile.Credentials != nil {
+			fetched, fetchedOrigins, err := fetchCredentials(name, *profile.Credentials)
+			if err != nil {
+				return exitcode.Wrap(exitcode.Config, fmt.Sprintf("read credentials for profile %q", name), err)
+			}
+			values, origins = fetched, fetchedOrigins
+		}
+		for _, field := range credentialFieldNames {
+			env := credentialEnvVars[field]
+			if value := strings.TrimSpace(os.Getenv(env)); value != "" {
+				values[field] = value
+				origins[field] = credentialEnvPrefix + env
+			}
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		if c.credentials == nil {
+			c.credentials = make(map[string]map[string]string)
+			c.credentialOrigins = make(map[string]map[string]string)
+		}
+		c.credentials[name] = values
+		c.credentialOrigins[name] = origins
+	}
+	for field, value := range values {
+		*credentialField(profile, field) = value
+	}
+	return nil
+}
+
+// detachCredentials keeps backend values out of the config file. Unchanged
+// values are dropped; changed ones go to the helper when the profile has one
+// and to the file otherwise.
+func (c *commandContext) detachCredentials(name string, profile config.Profile) config.Profile {
+	stored := c.cfg.Profiles[name]
+	resolved := c.credentials[name]
+	hasHelper := profile.Credentials != nil && strings.TrimSpace(profile.Credentials.Helper) != ""
+	for _, field := range credentialFieldNames {
+		value := credentialField(&profile, field)
+		fileValue := *credentialField(&stored, field)
+		previous, fromBackend := resolved[field]
+		if !fromBackend {
+			previous = fileValue
+		}
+		if *value == previous {
+			*value = fileValue
+			continue
+		}
+		if !hasHelper {
+			continue
+		}
+
+		if c.pendingCredentials == nil {
+			c.pendingCredentials = make(map[string]map[string]string)
+		}
+		if c.pendingCredentials[name] == nil {
+			c.pendingCredentials[name] = make(map[string]string)
+		}
+		c.pendingCredentials[name][field] = *value
+		if resolved != nil {
+			resolved[field] = *value
+		}
+		*value = fileValue
+	}
+	return profile
+}
+
+// flushCredentials hands changed secrets to each profile's helper: new values
+// with `store`, cleared ones with `erase`.
+func (c *commandContext) flushCredentials() error {
+	for name, values := range c.pendingCredentials {
+		profile, ok := c.cfg.Profiles[name]
+		if !ok || profile.Credentials == nil || strings.TrimSpace(profile.Credentials.Helper) == "" {
+			delete(c.pendingCredentials, name)
+			continue
+		}
+		stored := make(map[string]string)
+		erased := make(map[string]string)
+		for field, value := range values {
+			if value == "" {
+				erased[field] = ""
+			} else {
+				stored[field] = value
+			}
+		}
+		for action, fields := range map[string]map[string]string{"store": stored, "erase": erased} {
+			if len(fields) == 0 {
+				continue
+			}
+			if _, err := runCredentialHelper(profile.Credentials.Helper, action, name, fields); err != nil {
+				return exitcode.Wrap(exitcode.Config, fmt.Sprintf("save credentials for profile %q", name), err)
+			}
+		}
+		delete(c.pendingCredentials, name)
+	}
+	return nil
+}
+
+func fetchCredentials(name string, creds config.Credentials) (map[string]string, map[string]string, error) {
+	values := make(map[string]string)
+	origins := make(map[string]string)
+	if strings.TrimSpace(creds.Helper) != "" {
+		got, err := runCredentialHelper(creds.Helper, "get", name, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, field := range credentialFieldNames {
+			if value := got[field]; value != "" {
+				values[field] = value
+				origins[field] = "helper"
+			}
+		}
+	}
+	for _, field := range credentialFieldNames {
+		spec, ok := creds.Sources[field]
+		if !ok {
+			continue
+		}
+		value, err := readCredentialSource(spec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", field, err)
+		}
+		if value != "" {
+			values[field] = value
+			origins[field] = spec
+		}
+	}
+	return values, origins, nil
+}
+
+// readCredentialSource returns "" for an unset environment variable so the
+// config file value still applies. A command's first output line is used, as
+// `pass show` prints the password first.
+func readCredentialSource(spec string) (string, error) {
+	switch {
+	case strings.HasPrefix(spec, credentialEnvPrefix):
+		return strings.TrimSpace(os.Getenv(strings.TrimPrefix(spec, credentialEnvPrefix))), nil
+	case strings.HasPrefix(spec, credentialCmdPrefix):
+		cmd := shellCommand(strings.TrimPrefix(spec, credentialCmdPrefix))
+		cmd.Stdin = os.Stdin
+		cmd.Stderr = os.Stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("run credential command: %w", err)
+		}
+		line, _, _ := strings.Cut(string(out), "\n")
+		return strings.TrimSpace(line), nil
+	}
+	return "", fmt.Errorf("unsupported credential source %q", spec)
+}
+
+// runCredentialHelper speaks a git credential-helper style protocol: the
+// helper gets key=value lines on stdin, starting with profile=<name>, and
+// answers `get` with key=value lines on stdout.
+func runCredentialHelper(helper, action, name string, values map[string]string) (map[string]string, error) {
+	var input strings.Builder
+	fmt.Fprintf(&input, "profile=%s\n", name)
+	for _, field := range credentialFieldNames {
+		if value, ok := values[field]; ok {
+			fmt.Fprintf(&input, "%s=%s\n", field, value)
+		}
+	}
+	input.WriteString("\n")
+
+	cmd := shellCommand(helper + " " + action)
+	cmd.Stdin = strings.NewReader(input.String())
+	cmd.Stderr = os.Stderr
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("credential helper %s: %w", action, err)
+	}
+
+	result := make(map[string]string)
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimRight(scanner.Text(), "\r"), "=")
+		if ok {
+			result[strings.TrimSpace(key)] = value
+		}
+	}
+	return result, scanner.Err()
+}
+
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+func parseCredentialSource(field, raw string) (string, error) {
+	spec := strings.TrimSpace(raw)
+	switch {
+	case spec == "" || spec == "file":
+		return "", nil
+	case strings.HasPrefix(spec, credentialEnvPrefix):
+		if strings.TrimSpace(strings.TrimPrefix(spec, credentialEnvPrefix)) == "" {
+			return "", exitcode.New(exitcode.Validation, fmt.Sprintf("%s source needs a variable name after env:", field))
+		}
+	case strings.HasPrefix(spec, credentialCmdPrefix):
+		if strings.TrimSpace(strings.TrimPrefix(spec, credentialCmdPrefix)) == "" {
+			return "", exitcode.New(exitcode.Validation, fmt.Sprintf("%s source needs a command after cmd:", field))
+		}
+	default:
+		return "", exitcode.New(exitcode.Validation, fmt.Sprintf("%s source must be file, env:NAME or cmd:COMMAND", field))
+	}
+	return spec, nil
+}
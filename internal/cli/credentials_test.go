@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/kitefake"
+)
+
+// fileCredentialHelper is a credential helper that keeps values as
+// key=value lines in $HELPER_STORE.
+const fileCredentialHelper = `#!/bin/sh
+case "$1" in
+get) cat "$HELPER_STORE" ;;
+store|erase)
+	while IFS= read -r line && [ -n "$line" ]; do
+		key=${line%%=*}
+		[ "$key" = profile ] && continue
+		grep -v "^$key=" "$HELPER_STORE" > "$HELPER_STORE.tmp"
+		mv "$HELPER_STORE.tmp" "$HELPER_STORE"
+		if [ "$1" = store ]; then echo "$line" >> "$HELPER_STORE"; fi
+	done ;;
+esac
+`
+
+func TestCredentialsFromEnvAndCommand(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+	cfg := loadTestConfig(t, configPath)
+	profile := cfg.Profiles["default"]
+	profile.APISecret, profile.AccessToken = "", ""
+	cfg.Profiles["default"] = profile
+	saveTestConfig(t, configPath, cfg)
+
+	t.Setenv("TEST_KITE_ACCESS_TOKEN", kitefake.AccessToken)
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "set-credentials", "default",
+		"--access-token-from", "env:TEST_KITE_ACCESS_TOKEN",
+		"--api-secret-from", "cmd:printf '%s\\nnotes\\n' "+kitefake.APISecret,
+	); err != nil {
+		t.Fatalf("set-credentials: %v", err)
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "profile", "show"); err != nil {
+		t.Fatalf("expected the env access token to be used: %v", err)
+	}
+
+	// Without a helper, a refreshed token lands in the config file.
+	srv.ExpireAccessToken()
+	t.Setenv("TEST_KITE_ACCESS_TOKEN", "")
+	if _, _, err := executeCLICommand(t, configPath, "orders", "list"); exitcode.Code(err) != exitcode.Auth {
+		t.Fatalf("expected missing access token to fail auth, got %v", err)
+	}
+	t.Setenv("TEST_KITE_ACCESS_TOKEN", "expired_token")
+	if _, _, err := executeCLICommand(t, configPath, "orders", "list"); err != nil {
+		t.Fatalf("expected refresh with the command secret: %v", err)
+	}
+	saved := loadTestConfig(t, configPath).Profiles["default"]
+	if saved.AccessToken != srv.AccessToken() {
+		t.Fatalf("expected refreshed token in the file, got %+v", saved)
+	}
+	if saved.APISecret != "" {
+		t.Fatalf("expected the command secret to stay out of the file, got %q", saved.APISecret)
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "set-credentials", "default", "--api-key-from", "vault:kite"); exitcode.Code(err) != exitcode.Validation {
+		t.Fatalf("expected an unknown source to be rejected, got %v", err)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "set-credentials", "default", "--reset"); err != nil {
+		t.Fatalf("reset credentials: %v", err)
+	}
+	if creds := loadTestConfig(t, configPath).Profiles["default"].Credentials; creds != nil {
+		t.Fatalf("expected credentials to be cleared, got %+v", creds)
+	}
+}
+
+func TestCredentialHelperReceivesRefreshedTokens(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helper script needs sh")
+	}
+	srv, configPath := newFakeKiteConfig(t)
+
+	dir := t.TempDir()
+	helperPath := filepath.Join(dir, "helper")
+	storePath := filepath.Join(dir, "store")
+	if err := os.WriteFile(helperPath, []byte(fileCredentialHelper), 0o700); err != nil {
+		t.Fatalf("write helper: %v", err)
+	}
+	secrets := strings.Join([]string{
+		"api_secret=" + kitefake.APISecret,
+		"access_token=" + kitefake.AccessToken,
+		"refresh_token=" + kitefake.RefreshToken,
+	}, "\n") + "\n"
+	if err := os.WriteFile(storePath, []byte(secrets), 0o600); err != nil {
+		t.Fatalf("write helper store: %v", err)
+	}
+	t.Setenv("HELPER_STORE", storePath)
+
+	cfg := loadTestConfig(t, configPath)
+	profile := cfg.Profiles["default"]
+	profile.APISecret, profile.AccessToken, profile.RefreshToken = "", "", ""
+	profile.Credentials = &config.Credentials{Helper: helperPath}
+	cfg.Profiles["default"] = profile
+	saveTestConfig(t, configPath, cfg)
+
+	srv.ExpireAccessToken()
+	if _, _, err := executeCLICommand(t, configPath, "positions"); err != nil {
+		t.Fatalf("positions: %v", err)
+	}
+	if srv.Renewals() != 1 {
+		t.Fatalf("expected one renewal, got %d", srv.Renewals())
+	}
+
+	saved := loadTestConfig(t, configPath).Profiles["default"]
+	if saved.AccessToken != "" || saved.RefreshToken != "" || saved.APISecret != "" || saved.LastLoginAt.IsZero() {
+		t.Fatalf("expected secrets to stay out of the config file, got %+v", saved)
+	}
+	stored, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("read helper store: %v", err)
+	}
+	if !strings.Contains(string(stored), "access_token="+srv.AccessToken()) {
+		t.Fatalf("expected the refreshed token in the helper store, got:\n%s", stored)
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "auth", "logout"); err != nil {
+		t.Fatalf("auth logout: %v", err)
+	}
+	stored, err = os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("read helper store: %v", err)
+	}
+	if strings.Contains(string(stored), "access_token=") || !strings.Contains(string(stored), "api_secret=") {
+		t.Fatalf("expected logout to erase only the tokens, got:\n%s", stored)
+	}
+}
@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/kitefake"
+)
+
+// newFakeKiteConfig starts a fake Kite server and writes a config whose
+// active profile points at it with a valid session.
+func newFakeKiteConfig(t *testing.T) (*kitefake.Server, string) {
+	t.Helper()
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("HOME", cacheHome)
+
+	srv := kitefake.New()
+	t.Cleanup(srv.Close)
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := config.Default()
+	cfg.ActiveProfile = "default"
+	cfg.Profiles["default"] = config.Profile{
+		APIKey:       kitefake.APIKey,
+		APISecret:    kitefake.APISecret,
+		AccessToken:  kitefake.AccessToken,
+		RefreshToken: kitefake.RefreshToken,
+		BaseURL:      srv.URL,
+	}
+	saveTestConfig(t, configPath, cfg)
+	return srv, configPath
+}
+
+func TestCommandsAgainstFakeKite(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "profile show", args: []string{"profile", "show"}, want: []string{"AB1234", "Fake Trader"}},
+		{name: "margins", args: []string{"margins"}, want: []string{"equity", "98500.50"}},
+		{name: "quote get", args: []string{"quote", "get", "NSE:INFY", "NSE:TCS"}, want: []string{"NSE:INFY", "1500.00", "NSE:TCS", "3200.00"}},
+		{name: "quote ltp", args: []string{"quote", "ltp", "NSE:INFY"}, want: []string{"NSE:INFY", "1500.00"}},
+		{name: "quote ohlc", args: []string{"quote", "ohlc", "NSE:TCS"}, want: []string{"3215.00", "3218.40"}},
+		{name: "quote depth", args: []string{"quote", "depth", "NSE:INFY"}, want: []string{"1499.50", "1500.50"}},
+		{name: "quote historical by symbol", args: []string{"quote", "historical", "--instrument", "NSE:INFY", "--interval", "day", "--from", "2026-10-12", "--to", "2026-10-16"}, want: []string{"2026-10-12", "1468.30", "1500.00"}},
+		{name: "orders list", args: []string{"orders", "list"}, want: []string{"251016000000101", "INFY", "COMPLETE", "251016000000102", "OPEN"}},
+		{name: "orders show", args: []string{"orders", "show", "--order-id", "251016000000102"}, want: []string{"OPEN", "5.00"}},
+		{name: "orders trades", args: []string{"orders", "trades"}, want: []string{"50012345", "1490.25"}},
+		{name: "order trades", args: []string{"orders", "trades", "--order-id", "251016000000101"}, want: []string{"50012345"}},
+		{name: "positions", args: []string{"positions"}, want: []string{"INFY", "NIFTY26OCTFUT", "-75", "4500.00"}},
+		{name: "holdings", args: []string{"holdings"}, want: []string{"TCS", "600.00"}},
+		{name: "gtt list", args: []string{"gtt", "list"}, want: []string{"112233", "TCS", "active"}},
+		{name: "gtt show", args: []string{"gtt", "show", "--trigger-id", "112233"}, want: []string{"112233", "3000"}},
+		{name: "mf orders", args: []string{"mf", "orders", "list"}, want: []string{"INF109K01Z48", "COMPLETE"}},
+		{name: "mf sips", args: []string{"mf", "sips", "list"}, want: []string{"892741486820670", "ACTIVE"}},
+		{name: "mf holdings", args: []string{"mf", "holdings"}, want: []string{"1234567/89", "5120.35"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, configPath := newFakeKiteConfig(t)
+			stdout, _, err := executeCLICommand(t, configPath, tc.args...)
+			if err != nil {
+				t.Fatalf("%s: %v", strings.Join(tc.args, " "), err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(stdout, want) {
+					t.Fatalf("expected output to contain %q, got:\n%s", want, stdout)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderLifecycleAgainstFakeKite(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+
+	stdout, _, err := executeCLICommand(t, configPath, "order", "place", "--exchange", "NSE", "--symbol", "INFY", "--txn", "BUY", "--type", "LIMIT", "--price", "1450", "--product", "CNC", "--qty", "3", "--tag", "e2e", "--json")
+	if err != nil {
+		t.Fatalf("order place: %v", err)
+	}
+	var placed map[string]string
+	if err := json.Unmarshal([]byte(stdout), &placed); err != nil {
+		t.Fatalf("decode place response: %v\n%s", err, stdout)
+	}
+	orderID := placed["order_id"]
+	req, ok := srv.LastRequest("POST", "/orders/regular")
+	if !ok || req.Form.Get("tradingsymbol") != "INFY" || req.Form.Get("price") != "1450" || req.Form.Get("tag") != "e2e" {
+		t.Fatalf("unexpected place request: %+v", req)
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "order", "modify", "--order-id", orderID, "--exchange", "NSE", "--symbol", "INFY", "--txn", "BUY", "--type", "LIMIT", "--price", "1455", "--qty", "3"); err != nil {
+		t.Fatalf("order modify: %v", err)
+	}
+	if req, ok := srv.LastRequest("PUT", "/orders/regular/"+orderID); !ok || req.Form.Get("price") != "1455" {
+		t.Fatalf("unexpected modify request: %+v", req)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "order", "cancel", "--order-id", orderID); err != nil {
+		t.Fatalf("order cancel: %v", err)
+	}
+	stdout, _, err = executeCLICommand(t, configPath, "orders", "show", "--order-id", orderID)
+	if err != nil {
+		t.Fatalf("orders show: %v", err)
+	}
+	if !strings.Contains(stdout, "CANCELLED") {
+		t.Fatalf("expected cancelled order, got:\n%s", stdout)
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "order", "cancel", "--order-id", orderID); exitcode.Code(err) != exitcode.Validation {
+		t.Fatalf("expected a second cancel to fail with a validation code, got %v", err)
+	}
+}
+
+func TestTokenExpiryAgainstFakeKite(t *testing.T) {
+	tests := []struct {
+		name         string
+		refreshToken string
+		wantCode     int
+		wantRenewals int
+	}{
+		{name: "refreshes and retries", refreshToken: kitefake.RefreshToken, wantRenewals: 1},
+		{name: "no refresh token", refreshToken: "", wantCode: exitcode.Auth},
+		{name: "refresh rejected", refreshToken: "stale_refresh_token", wantCode: exitcode.Auth},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, configPath := newFakeKiteConfig(t)
+			cfg := loadTestConfig(t, configPath)
+			profile := cfg.Profiles["default"]
+			profile.RefreshToken = tc.refreshToken
+			cfg.Profiles["default"] = profile
+			saveTestConfig(t, configPath, cfg)
+			srv.ExpireAccessToken()
+
+			_, _, err := executeCLICommand(t, configPath, "orders", "list")
+			if code := exitcode.Code(err); code != tc.wantCode {
+				t.Fatalf("expected exit code %d, got %d (%v)", tc.wantCode, code, err)
+			}
+			if srv.Renewals() != tc.wantRenewals {
+				t.Fatalf("expected %d renewals, got %d", tc.wantRenewals, srv.Renewals())
+			}
+			if tc.wantRenewals == 0 {
+				return
+			}
+
+			saved := loadTestConfig(t, configPath).Profiles["default"]
+			if saved.AccessToken != srv.AccessToken() || saved.RefreshToken == kitefake.RefreshToken || saved.LastLoginAt.IsZero() {
+				t.Fatalf("expected renewed tokens to be saved, got %+v", saved)
+			}
+			// The saved token works without another renewal.
+			if _, _, err := executeCLICommand(t, configPath, "positions"); err != nil || srv.Renewals() != 1 {
+				t.Fatalf("expected saved token to be reused, err=%v renewals=%d", err, srv.Renewals())
+			}
+		})
+	}
+}
+
+func TestAuthLoginAgainstFakeKite(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+	cfg := loadTestConfig(t, configPath)
+	profile := cfg.Profiles["default"]
+	profile.AccessToken, profile.RefreshToken = "", ""
+	cfg.Profiles["default"] = profile
+	saveTestConfig(t, configPath, cfg)
+
+	if _, _, err := executeCLICommand(t, configPath, "auth", "login", "--request-token", "bad_token"); exitcode.Code(err) != exitcode.Auth {
+		t.Fatalf("expected an auth failure for a bad request token, got %v", err)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "auth", "login", "--request-token", kitefake.RequestToken); err != nil {
+		t.Fatalf("auth login: %v", err)
+	}
+	saved := loadTestConfig(t, configPath).Profiles["default"]
+	if saved.AccessToken != srv.AccessToken() || saved.RefreshToken != kitefake.RefreshToken {
+		t.Fatalf("expected session tokens to be saved, got %+v", saved)
+	}
+}
+
+func TestKiteBaseURLFromEnvironment(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+	cfg := loadTestConfig(t, configPath)
+	profile := cfg.Profiles["default"]
+	profile.BaseURL = "http://127.0.0.1:1"
+	cfg.Profiles["default"] = profile
+	saveTestConfig(t, configPath, cfg)
+
+	t.Setenv(kiteBaseURLEnvVar, srv.URL+"/")
+	if _, _, err := executeCLICommand(t, configPath, "profile", "show"); err != nil {
+		t.Fatalf("expected env base URL to override the profile: %v", err)
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "set-base-url", "default", "--base-url", "ftp://example.com"); err == nil {
+		t.Fatalf("expected non-http base URL to be rejected")
+	}
+	if _, _, err := executeCLICommand(t, configPath, "config", "profile", "set-base-url", "default", "--reset"); err != nil {
+		t.Fatalf("reset base url: %v", err)
+	}
+	if got := loadTestConfig(t, configPath).Profiles["default"].BaseURL; got != "" {
+		t.Fatalf("expected base URL to be cleared, got %q", got)
+	}
+}
+
+func TestPaperModeAgainstFakeKite(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+
+	if _, _, err := executeCLICommand(t, configPath, "order", "place", "--paper", "--exchange", "NSE", "--symbol", "INFY", "--txn", "BUY", "--type", "MARKET", "--product", "MIS", "--qty", "2"); err != nil {
+		t.Fatalf("paper order place: %v", err)
+	}
+	if _, ok := srv.LastRequest("POST", "/orders/regular"); ok {
+		t.Fatalf("expected paper order not to reach the broker")
+	}
+
+	stdout, _, err := executeCLICommand(t, configPath, "positions", "--paper")
+	if err != nil {
+		t.Fatalf("paper positions: %v", err)
+	}
+	// Bought at the 1500.50 ask, marked at the 1500 LTP.
+	for _, want := range []string{"INFY", "MIS", "1500.50", "-1.00"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected paper positions to contain %q, got:\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "NIFTY26OCTFUT") {
+		t.Fatalf("expected live positions to be hidden in paper mode, got:\n%s", stdout)
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+)
+
+// Environment variables for global settings. A flag wins over its variable,
+// and a variable wins over the config file.
+const (
+	profileEnvVar      = "ZERODHA_PROFILE"
+	configEnvVar       = "ZERODHA_CONFIG"
+	outputEnvVar       = "ZERODHA_OUTPUT"
+	debugEnvVar        = "ZERODHA_DEBUG"
+	paperEnvVar        = "ZERODHA_PAPER"
+	apiKeyEnvVar       = "ZERODHA_API_KEY"
+	apiSecretEnvVar    = "ZERODHA_API_SECRET"
+	accessTokenEnvVar  = "ZERODHA_ACCESS_TOKEN"
+	refreshTokenEnvVar = "ZERODHA_REFRESH_TOKEN"
+)
+
+// envProfileName is used when only environment credentials are available.
+const envProfileName = "default"
+
+var flagEnvVars = []struct {
+	flag string
+	env  string
+}{
+	{"profile", profileEnvVar},
+	{"config", configEnvVar},
+	{"json", outputEnvVar},
+	{"debug", debugEnvVar},
+	{"paper", paperEnvVar},
+	{"key-file", keyFileEnvVar},
+}
+
+var credentialEnvVars = map[string]string{
+	"api_key":       apiKeyEnvVar,
+	"api_secret":    apiSecretEnvVar,
+	"access_token":  accessTokenEnvVar,
+	"refresh_token": refreshTokenEnvVar,
+}
+
+// applyEnvOverrides fills global flags that were not passed from their
+// environment variables and records where each value came from.
+func applyEnvOverrides(flags *pflag.FlagSet, opts *rootOptions) error {
+	opts.origins = make(map[string]string, len(flagEnvVars))
+	for _, binding := range flagEnvVars {
+		if flags.Lookup(binding.flag) == nil {
+			continue
+		}
+		if flags.Changed(binding.flag) {
+			opts.origins[binding.flag] = "flag"
+			continue
+		}
+		value := strings.TrimSpace(os.Getenv(binding.env))
+		if value == "" {
+			opts.origins[binding.flag] = "default"
+			continue
+		}
+		if binding.flag == "json" {
+			switch strings.ToLower(value) {
+			case "json":
+				value = "true"
+			case "table", "text":
+				value = "false"
+			default:
+				return exitcode.New(exitcode.Validation, fmt.Sprintf("%s must be json or table", outputEnvVar))
+			}
+		}
+		if err := flags.Set(binding.flag, value); err != nil {
+			return exitcode.Wrap(exitcode.Validation, "invalid "+binding.env, err)
+		}
+		opts.origins[binding.flag] = "env"
+	}
+	return nil
+}
+
+func credentialEnvSet() bool {
+	for _, env := range credentialEnvVars {
+		if strings.TrimSpace(os.Getenv(env)) != "" {
+			return true
+		}
+	}
+	return false
+}
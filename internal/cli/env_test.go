@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/kitefake"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/updater"
+)
+
+func TestGlobalSettingsFromEnvironment(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := config.Default()
+	cfg.ActiveProfile = "main"
+	cfg.Profiles["main"] = config.Profile{APIKey: "main_key", APISecret: "main_secret"}
+	cfg.Profiles["family"] = config.Profile{APIKey: "family_key", APISecret: "family_secret"}
+	saveTestConfig(t, configPath, cfg)
+
+	t.Setenv(profileEnvVar, "family")
+	t.Setenv(outputEnvVar, "json")
+	stdout, _, err := executeCLICommand(t, configPath, "config", "show", "--effective")
+	if err != nil {
+		t.Fatalf("config show: %v", err)
+	}
+	var shown struct {
+		Settings []configSetting `json:"settings"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &shown); err != nil {
+		t.Fatalf("expected ZERODHA_OUTPUT=json to select JSON: %v\n%s", err, stdout)
+	}
+	settings := make(map[string]configSetting, len(shown.Settings))
+	for _, setting := range shown.Settings {
+		settings[setting.Name] = setting
+	}
+	if got := settings["profile"]; got.Value != "family" || got.Source != "env" {
+		t.Fatalf("expected profile from env, got %+v", got)
+	}
+	if got := settings["api_key"]; got.Value != "family_key" || got.Source != "config" {
+		t.Fatalf("expected api key from config, got %+v", got)
+	}
+	if got := settings["api_secret"]; got.Value != "****cret" || strings.Contains(stdout, "family_secret") {
+		t.Fatalf("expected api secret to be redacted, got %+v", got)
+	}
+
+	// A flag wins over the environment.
+	stdout, _, err = executeCLICommand(t, configPath, "config", "show", "--effective", "--profile", "main")
+	if err != nil || !strings.Contains(stdout, `"main"`) || !strings.Contains(stdout, `"flag"`) {
+		t.Fatalf("expected --profile to override ZERODHA_PROFILE, err=%v out=%s", err, stdout)
+	}
+
+	t.Setenv(outputEnvVar, "yaml")
+	if _, _, err := executeCLICommand(t, configPath, "config", "show"); exitcode.Code(err) != exitcode.Validation {
+		t.Fatalf("expected an invalid ZERODHA_OUTPUT to be rejected, got %v", err)
+	}
+	t.Setenv(outputEnvVar, "")
+	t.Setenv(debugEnvVar, "maybe")
+	if _, _, err := executeCLICommand(t, configPath, "config", "show"); exitcode.Code(err) != exitcode.Validation {
+		t.Fatalf("expected an invalid ZERODHA_DEBUG to be rejected, got %v", err)
+	}
+}
+
+func TestConfigPathFromEnvironment(t *testing.T) {
+	t.Setenv(updater.DisableEnvVar, "1")
+	configPath := filepath.Join(t.TempDir(), "env-config.json")
+	cfg := config.Default()
+	cfg.Profiles["ci"] = config.Profile{APIKey: "ci_key"}
+	saveTestConfig(t, configPath, cfg)
+	t.Setenv(configEnvVar, configPath)
+
+	cmd := newRootCmd()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"config", "profile", "list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("config profile list: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "ci") {
+		t.Fatalf("expected profiles from ZERODHA_CONFIG, got:\n%s", stdout.String())
+	}
+}
+
+func TestCredentialsFromEnvironmentWithoutProfile(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+	saveTestConfig(t, configPath, config.Default())
+
+	t.Setenv(apiKeyEnvVar, kitefake.APIKey)
+	t.Setenv(apiSecretEnvVar, kitefake.APISecret)
+	t.Setenv(accessTokenEnvVar, kitefake.AccessToken)
+	t.Setenv(refreshTokenEnvVar, kitefake.RefreshToken)
+	t.Setenv(kiteBaseURLEnvVar, srv.URL)
+	stdout, _, err := executeCLICommand(t, configPath, "profile", "show")
+	if err != nil || !strings.Contains(stdout, "AB1234") {
+		t.Fatalf("expected env credentials to work without a profile, err=%v out=%s", err, stdout)
+	}
+
+	stdout, _, err = executeCLICommand(t, configPath, "config", "show", "--effective")
+	if err != nil {
+		t.Fatalf("config show: %v", err)
+	}
+	for _, want := range []string{"env:" + apiKeyEnvVar, "env:" + accessTokenEnvVar, "****"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected %q in effective settings, got:\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, kitefake.AccessToken) {
+		t.Fatalf("expected access token to be redacted, got:\n%s", stdout)
+	}
+
+	// A refreshed token is saved, but env values are not copied to the file.
+	srv.ExpireAccessToken()
+	if _, _, err := executeCLICommand(t, configPath, "positions"); err != nil {
+		t.Fatalf("positions: %v", err)
+	}
+	saved := loadTestConfig(t, configPath).Profiles[envProfileName]
+	if saved.AccessToken != srv.AccessToken() || saved.APISecret != "" || saved.APIKey != "" {
+		t.Fatalf("expected only the refreshed token in the file, got %+v", saved)
+	}
+}
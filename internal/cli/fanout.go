@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+)
+
+// fanOutAnnotation marks the read commands that accept --all-profiles and
+// --profiles.
+const fanOutAnnotation = "fan-out"
+
+type profileResult[T any] struct {
+	profile string
+	value   T
+	err     error
+}
+
+func allowFanOut(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[fanOutAnnotation] = "true"
+	return cmd
+}
+
+func (o *rootOptions) fanOut() bool {
+	return o.allProfiles || len(o.profiles) > 0
+}
+
+func validateFanOutFlags(cmd *cobra.Command, opts *rootOptions) error {
+	if !opts.fanOut() {
+		return nil
+	}
+	if cmd.Annotations[fanOutAnnotation] == "" {
+		return exitcode.New(exitcode.Validation, "--all-profiles and --profiles only work with holdings, positions, margins, orders list, mf holdings, gtt list and portfolio consolidated")
+	}
+	if opts.allProfiles && len(opts.profiles) > 0 {
+		return exitcode.New(exitcode.Validation, "--all-profiles cannot be combined with --profiles")
+	}
+	if opts.origins["profile"] == "flag" {
+		return exitcode.New(exitcode.Validation, "--profile cannot be combined with --all-profiles or --profiles")
+	}
+	if credentialEnvSet() {
+		return exitcode.New(exitcode.Validation, "ZERODHA_API_KEY and the other credential variables would apply to every profile; unset them to use --all-profiles or --profiles")
+	}
+	return nil
+}
+
+// fanOutProfileNames lists the profiles picked by --all-profiles or
+// --profiles. Without either it falls back to the selected profile, then to
+// every profile; only commands that always fan out rely on that.
+func (c *commandContext) fanOutProfileNames() ([]string, error) {
+	if !c.opts.fanOut() {
+		if name := strings.TrimSpace(c.opts.profile); name != "" {
+			if _, ok := c.cfg.Profiles[name]; !ok {
+				return nil, exitcode.New(exitcode.Config, fmt.Sprintf("profile %q not found", name))
+			}
+			return []string{name}, nil
+		}
+	}
+	if c.opts.allProfiles || !c.opts.fanOut() {
+		names := c.profileNames()
+		if len(names) == 0 {
+			return nil, exitcode.New(exitcode.Config, "no profiles configured; add one with `zerodha config profile add`")
+		}
+		return names, nil
+	}
+	names := make([]string, 0, len(c.opts.profiles))
+	seen := make(map[string]bool, len(c.opts.profiles))
+	for _, name := range c.opts.profiles {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := c.cfg.Profiles[name]; !ok {
+			return nil, exitcode.New(exitcode.Config, fmt.Sprintf("profile %q not found", name))
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, exitcode.New(exitcode.Validation, "--profiles needs at least one profile name")
+	}
+	return names, nil
+}
+
+// forProfile returns a context for one profile of a fan-out. It shares the
+// store, whose lock serializes token refreshes, and copies everything else.
+func (c *commandContext) forProfile(name string) *commandContext {
+	opts := *c.opts
+	opts.profile = name
+	return &commandContext{
+		opts:  &opts,
+		store: c.store,
+		cfg:   c.cfg.Clone(),
+		base:  c.base.Clone(),
+	}
+}
+
+// fanOutProfiles runs fetch for every selected profile concurrently. A
+// profile's failure is kept in its result rather than stopping the others.
+func fanOutProfiles[T any](
+	ctx *commandContext,
+	fetch func(ctx *commandContext, name string, profile *config.Profile) (T, error),
+) ([]profileResult[T], error) {
+	names, err := ctx.fanOutProfileNames()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]profileResult[T], len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Go(func() {
+			results[i].profile = name
+			profileCtx := ctx.forProfile(name)
+			_, profile, err := profileCtx.resolveProfile(true)
+			if err == nil {
+				err = ensureAccessToken(profile)
+			}
+			if err == nil {
+				results[i].value, err = fetch(profileCtx, name, profile)
+			}
+			results[i].err = err
+		})
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// printFanOut renders results as one table with a leading PROFILE column, or
+// as a JSON object keyed by profile where a failed profile maps to its error.
+func printFanOut[T any](ctx *commandContext, cmd *cobra.Command, results []profileResult[T], headers []string, rows func(T) [][]string) error {
+	printer := ctx.printer(cmd.OutOrStdout())
+	if printer.IsJSON() {
+		out := make(map[string]any, len(results))
+		for _, result := range results {
+			if result.err != nil {
+				out[result.profile] = profileErrorJSON(result.err)
+				continue
+			}
+			out[result.profile] = result.value
+		}
+		if err := printer.JSON(out); err != nil {
+			return err
+		}
+		return fanOutError(results)
+	}
+
+	var table [][]string
+	for _, result := range results {
+		if result.err != nil {
+			if _, err := fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", result.profile, result.err); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, row := range rows(result.value) {
+			table = append(table, append([]string{result.profile}, row...))
+		}
+	}
+	if err := printer.Table(append([]string{"PROFILE"}, headers...), table); err != nil {
+		return err
+	}
+	return fanOutError(results)
+}
+
+func profileErrorJSON(err error) map[string]any {
+	return map[string]any{
+		"error":     err.Error(),
+		"exit_code": exitcode.Code(err),
+	}
+}
+
+// fanOutError summarizes failed profiles, exiting with the first failure's code.
+func fanOutError[T any](results []profileResult[T]) error {
+	var failed []string
+	code := exitcode.Success
+	for _, result := range results {
+		if result.err == nil {
+			continue
+		}
+		if code == exitcode.Success {
+			code = exitcode.Code(result.err)
+		}
+		failed = append(failed, result.profile)
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return exitcode.New(code, fmt.Sprintf("%d of %d profiles failed: %s", len(failed), len(results), strings.Join(failed, ", ")))
+}
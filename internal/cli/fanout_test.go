@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/kitefake"
+)
+
+// newFanOutConfig writes profiles "family" and "main", each backed by its own
+// fake Kite server, plus "stale" which has never logged in.
+func newFanOutConfig(t *testing.T) (map[string]*kitefake.Server, string) {
+	t.Helper()
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("HOME", cacheHome)
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := config.Default()
+	cfg.ActiveProfile = "main"
+	servers := make(map[string]*kitefake.Server)
+	for _, name := range []string{"family", "main"} {
+		srv := kitefake.New()
+		t.Cleanup(srv.Close)
+		servers[name] = srv
+		cfg.Profiles[name] = config.Profile{
+			APIKey:       kitefake.APIKey,
+			APISecret:    kitefake.APISecret,
+			AccessToken:  kitefake.AccessToken,
+			RefreshToken: kitefake.RefreshToken,
+			BaseURL:      srv.URL,
+		}
+	}
+	cfg.Profiles["stale"] = config.Profile{APIKey: kitefake.APIKey, APISecret: kitefake.APISecret}
+	saveTestConfig(t, configPath, cfg)
+	return servers, configPath
+}
+
+func TestFanOutJSONKeyedByProfileWithErrors(t *testing.T) {
+	_, configPath := newFanOutConfig(t)
+
+	stdout, _, err := executeCLICommand(t, configPath, "holdings", "--all-profiles", "--json")
+	if exitcode.Code(err) != exitcode.Auth || !strings.Contains(err.Error(), "1 of 3 profiles failed: stale") {
+		t.Fatalf("expected the stale profile to fail the command, got %v", err)
+	}
+
+	var out map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout)
+	}
+	if len(out) != 3 {
+		t.Fatalf("expected one entry per profile, got %s", stdout)
+	}
+	for _, name := range []string{"family", "main"} {
+		var holdings []map[string]any
+		if err := json.Unmarshal(out[name], &holdings); err != nil || len(holdings) == 0 {
+			t.Fatalf("expected holdings for %s, got %s (%v)", name, out[name], err)
+		}
+	}
+	var stale struct {
+		Error    string `json:"error"`
+		ExitCode int    `json:"exit_code"`
+	}
+	if err := json.Unmarshal(out["stale"], &stale); err != nil || !strings.Contains(stale.Error, "no access token") || stale.ExitCode != exitcode.Auth {
+		t.Fatalf("expected the stale profile's error, got %s (%v)", out["stale"], err)
+	}
+}
+
+func TestFanOutTablePrefixesProfileColumn(t *testing.T) {
+	_, configPath := newFanOutConfig(t)
+
+	for _, args := range [][]string{
+		{"positions"},
+		{"margins"},
+		{"orders", "list"},
+		{"mf", "holdings"},
+		{"gtt", "list"},
+	} {
+		stdout, _, err := executeCLICommand(t, configPath, append(args, "--profiles", "main,family")...)
+		if err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if !strings.HasPrefix(lines[0], "PROFILE") {
+			t.Fatalf("%v: expected a PROFILE column, got %q", args, lines[0])
+		}
+		if !strings.Contains(stdout, "\nfamily ") || !strings.Contains(stdout, "\nmain ") || strings.Contains(stdout, "stale") {
+			t.Fatalf("%v: expected rows for main and family only, got:\n%s", args, stdout)
+		}
+	}
+}
+
+func TestFanOutRefreshesEachProfileOnce(t *testing.T) {
+	servers, configPath := newFanOutConfig(t)
+	for _, srv := range servers {
+		srv.ExpireAccessToken()
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "orders", "list", "--profiles", "main,family"); err != nil {
+		t.Fatalf("orders list: %v", err)
+	}
+	saved := loadTestConfig(t, configPath)
+	for name, srv := range servers {
+		if srv.Renewals() != 1 {
+			t.Fatalf("expected one renewal for %s, got %d", name, srv.Renewals())
+		}
+		if saved.Profiles[name].AccessToken != srv.AccessToken() {
+			t.Fatalf("expected the renewed token for %s to be saved, got %+v", name, saved.Profiles[name])
+		}
+	}
+}
+
+func TestFanOutFlagValidation(t *testing.T) {
+	_, configPath := newFanOutConfig(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		errMatch string
+	}{
+		{
+			name:     "unsupported command",
+			args:     []string{"profile", "show", "--all-profiles"},
+			errMatch: "only work with holdings",
+		},
+		{
+			name:     "both selectors",
+			args:     []string{"holdings", "--all-profiles", "--profiles", "main"},
+			errMatch: "--all-profiles cannot be combined with --profiles",
+		},
+		{
+			name:     "explicit profile",
+			args:     []string{"holdings", "--profile", "main", "--all-profiles"},
+			errMatch: "--profile cannot be combined",
+		},
+		{
+			name:     "unknown profile",
+			args:     []string{"holdings", "--profiles", "main,nobody"},
+			errMatch: `profile "nobody" not found`,
+		},
+		{
+			name:     "greeks",
+			args:     []string{"positions", "--greeks", "--all-profiles"},
+			errMatch: "--greeks cannot be combined",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := executeCLICommand(t, configPath, tc.args...)
+			if err == nil || !strings.Contains(err.Error(), tc.errMatch) {
+				t.Fatalf("expected error containing %q, got %v", tc.errMatch, err)
+			}
+		})
+	}
+
+	t.Setenv(apiKeyEnvVar, "env_key")
+	if _, _, err := executeCLICommand(t, configPath, "holdings", "--all-profiles"); exitcode.Code(err) != exitcode.Validation {
+		t.Fatalf("expected credential env vars to block fan-out, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
 	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
 	"github.com/spf13/cobra"
 	kiteconnect "github.com/zerodha/gokiteconnect/v4"
@@ -57,7 +58,7 @@ func newGttCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.GTTResponse, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.GTTResponse, error) {
 				return client.PlaceGTT(params)
 			})
 			if err != nil {
@@ -107,7 +108,7 @@ func newGttCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.GTTResponse, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.GTTResponse, error) {
 				return client.ModifyGTT(triggerID, params)
 			})
 			if err != nil {
@@ -131,7 +132,7 @@ func newGttCmd(opts *rootOptions) *cobra.Command {
 	modifyCmd.Flags().IntVar(&modifyTriggerID, "trigger-id", 0, "Trigger ID")
 
 	var listLimit int
-	listCmd := &cobra.Command{
+	listCmd := allowFanOut(&cobra.Command{
 		Use:   "list",
 		Short: "List all GTT triggers",
 		RunE: func(cmd *cobra.Command, _ []string) error {
@@ -143,6 +144,15 @@ func newGttCmd(opts *rootOptions) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if opts.fanOut() {
+				results, err := fanOutProfiles(ctx, func(ctx *commandContext, name string, profile *config.Profile) (kiteconnect.GTTs, error) {
+					return fetchGTTs(ctx, name, profile, listLimit)
+				})
+				if err != nil {
+					return err
+				}
+				return printFanOut(ctx, cmd, results, gttHeaders, gttRows)
+			}
 			profileName, profile, err := ctx.resolveProfile(true)
 			if err != nil {
 				return err
@@ -151,43 +161,23 @@ func newGttCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			gtts, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.GTTs, error) {
-				return client.GetGTTs()
-			})
+			gtts, err := fetchGTTs(ctx, profileName, profile, listLimit)
 			if err != nil {
 				return err
 			}
-			gtts = applyLimit(gtts, listLimit)
 
 			printer := ctx.printer(cmd.OutOrStdout())
 			if printer.IsJSON() {
 				return printer.JSON(gtts)
 			}
 
-			rows := make([][]string, 0, len(gtts))
-			for _, gtt := range gtts {
-				rows = append(rows, []string{
-					intToString(gtt.ID),
-					string(gtt.Type),
-					gtt.Status,
-					gtt.Condition.Tradingsymbol,
-					gtt.Condition.Exchange,
-					formatFloat(gtt.Condition.LastPrice),
-					formatFloatSlice(gtt.Condition.TriggerValues),
-					formatModelTime(gtt.CreatedAt.Time),
-					formatModelTime(gtt.UpdatedAt.Time),
-					formatModelTime(gtt.ExpiresAt.Time),
-				})
-			}
+			rows := gttRows(gtts)
 			if len(rows) == 0 {
 				rows = append(rows, []string{"0", "-", "-", "-", "-", "0.00", "-", "-", "-", "-"})
 			}
-			return printer.Table(
-				[]string{"TRIGGER_ID", "TYPE", "STATUS", "SYMBOL", "EXCHANGE", "LAST_PRICE", "TRIGGER_VALUES", "CREATED_AT", "UPDATED_AT", "EXPIRES_AT"},
-				rows,
-			)
+			return printer.Table(gttHeaders, rows)
 		},
-	}
+	})
 	listCmd.Flags().IntVar(&listLimit, "limit", 0, "Limit number of rows (0 = no limit)")
 
 	var showTriggerID int
@@ -212,7 +202,7 @@ func newGttCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			gtt, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.GTT, error) {
+			gtt, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.GTT, error) {
 				return client.GetGTT(triggerID)
 			})
 			if err != nil {
@@ -286,7 +276,7 @@ func newGttCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.GTTResponse, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.GTTResponse, error) {
 				return client.DeleteGTT(triggerID)
 			})
 			if err != nil {
@@ -312,6 +302,37 @@ func newGttCmd(opts *rootOptions) *cobra.Command {
 	return gttCmd
 }
 
+var gttHeaders = []string{"TRIGGER_ID", "TYPE", "STATUS", "SYMBOL", "EXCHANGE", "LAST_PRICE", "TRIGGER_VALUES", "CREATED_AT", "UPDATED_AT", "EXPIRES_AT"}
+
+func fetchGTTs(ctx *commandContext, profileName string, profile *config.Profile, limit int) (kiteconnect.GTTs, error) {
+	gtts, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.GTTs, error) {
+		return client.GetGTTs()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applyLimit(gtts, limit), nil
+}
+
+func gttRows(gtts kiteconnect.GTTs) [][]string {
+	rows := make([][]string, 0, len(gtts))
+	for _, gtt := range gtts {
+		rows = append(rows, []string{
+			intToString(gtt.ID),
+			string(gtt.Type),
+			gtt.Status,
+			gtt.Condition.Tradingsymbol,
+			gtt.Condition.Exchange,
+			formatFloat(gtt.Condition.LastPrice),
+			formatFloatSlice(gtt.Condition.TriggerValues),
+			formatModelTime(gtt.CreatedAt.Time),
+			formatModelTime(gtt.UpdatedAt.Time),
+			formatModelTime(gtt.ExpiresAt.Time),
+		})
+	}
+	return rows
+}
+
 func bindGTTFlags(cmd *cobra.Command, flags *gttFlags) {
 	cmd.Flags().StringVar(&flags.exchange, "exchange", "", "Exchange (NSE/BSE/NFO/...)")
 	cmd.Flags().StringVar(&flags.symbol, "symbol", "", "Trading symbol")
@@ -1,11 +1,9 @@
 package cli
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"net/url"
 	"os"
 	"sort"
@@ -25,6 +23,13 @@ type commandContext struct {
 	opts  *rootOptions
 	store *config.FileStore
 	cfg   config.Config
+	// base is the config as last read from disk; save merges the changes
+	// made since then onto whatever is on disk now.
+	base config.Config
+
+	credentials        map[string]map[string]string
+	credentialOrigins  map[string]map[string]string
+	pendingCredentials map[string]map[string]string
 }
 
 func newCommandContext(opts *rootOptions) (*commandContext, error) {
@@ -38,7 +43,11 @@ func newCommandContext(opts *rootOptions) (*commandContext, error) {
 	}
 
 	store := config.NewFileStore(configPath)
+	store.SetPassphraseFunc(configPassphrase(opts.keyFile))
 	cfg, err := store.Load()
+	if isConfigLocked(err) {
+		return nil, exitcode.Wrap(exitcode.Auth, "unlock config", err)
+	}
 	if err != nil {
 		return nil, exitcode.Wrap(exitcode.Config, "load config", err)
 	}
@@ -50,13 +59,44 @@ func newCommandContext(opts *rootOptions) (*commandContext, error) {
 		opts:  opts,
 		store: store,
 		cfg:   cfg,
+		base:  cfg.Clone(),
 	}, nil
 }
 
 func (c *commandContext) save() error {
-	if err := c.store.Save(c.cfg); err != nil {
+	return c.saveWith(c.store.Update)
+}
+
+// saveWith merges and saves through update, which is the store's own Update
+// or, under the config lock, the locked store's.
+func (c *commandContext) saveWith(update func(func(*config.Config) error) (config.Config, error)) error {
+	if err := c.flushCredentials(); err != nil {
+		return err
+	}
+	base, ours := c.base, c.cfg
+	cfg, err := update(func(latest *config.Config) error {
+		*latest = config.Merge(base, ours, *latest)
+		return nil
+	})
+	if isConfigLocked(err) {
+		return exitcode.Wrap(exitcode.Auth, "unlock config to save", err)
+	}
+	if err != nil {
 		return exitcode.Wrap(exitcode.Config, "save config", err)
 	}
+	c.cfg, c.base = cfg, cfg.Clone()
+	return nil
+}
+
+// reload merges changes other processes saved since the config was read. It
+// runs under the config lock.
+func (c *commandContext) reload(locked config.LockedStore) error {
+	latest, err := locked.Read()
+	if err != nil {
+		return exitcode.Wrap(exitcode.Config, "reload config", err)
+	}
+	c.cfg = config.Merge(c.base, c.cfg, latest)
+	c.base = latest.Clone()
 	return nil
 }
 
@@ -74,6 +114,9 @@ func (c *commandContext) resolveProfile(require bool) (string, *config.Profile,
 			name = n
 		}
 	}
+	if name == "" && credentialEnvSet() {
+		name = envProfileName
+	}
 	if name == "" {
 		if require {
 			return "", nil, exitcode.New(exitcode.Config, "no profile selected; set one with `zerodha config profile use <name>` or pass --profile")
@@ -82,9 +125,12 @@ func (c *commandContext) resolveProfile(require bool) (string, *config.Profile,
 	}
 
 	profile, ok := c.cfg.Profiles[name]
-	if !ok {
+	if !ok && !credentialEnvSet() {
 		return "", nil, exitcode.New(exitcode.Config, fmt.Sprintf("profile %q not found", name))
 	}
+	if err := c.resolveCredentials(name, &profile); err != nil {
+		return "", nil, err
+	}
 
 	return name, &profile, nil
 }
@@ -93,41 +139,67 @@ func (c *commandContext) setProfile(name string, profile config.Profile) {
 	if c.cfg.Profiles == nil {
 		c.cfg.Profiles = make(map[string]config.Profile)
 	}
+	if _, resolved := c.credentials[name]; resolved || profile.Credentials != nil {
+		profile = c.detachCredentials(name, profile)
+	}
 	c.cfg.Profiles[name] = profile
 }
 
 func (c *commandContext) deleteProfile(name string) {
 	delete(c.cfg.Profiles, name)
+	delete(c.pendingCredentials, name)
 }
 
 func (c *commandContext) profileNames() []string {
-	names := make([]string, 0, len(c.cfg.Profiles))
-	for name := range c.cfg.Profiles {
+	return sortedProfileNames(c.cfg.Profiles)
+}
+
+func sortedProfileNames(profiles map[string]config.Profile) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
 		names = append(names, name)
 	}
 	sort.Strings(names)
 	return names
 }
 
+// kiteBaseURLEnvVar points every profile at another Kite API host, such as a
+// local fake server in tests. It takes precedence over a profile's base URL.
+const kiteBaseURLEnvVar = "ZERODHA_KITE_BASE_URL"
+
 func newKiteClient(profile config.Profile, debug bool) *kiteconnect.Client {
 	client := kiteconnect.New(profile.APIKey)
 	client.SetDebug(debug)
+	if baseURL := kiteBaseURL(profile); baseURL != "" {
+		client.SetBaseURI(baseURL)
+	}
 	if profile.AccessToken != "" {
 		client.SetAccessToken(profile.AccessToken)
 	}
 	return client
 }
 
+func kiteBaseURL(profile config.Profile) string {
+	if value := strings.TrimSpace(os.Getenv(kiteBaseURLEnvVar)); value != "" {
+		return strings.TrimRight(value, "/")
+	}
+	return strings.TrimRight(strings.TrimSpace(profile.BaseURL), "/")
+}
+
 func callWithAuthRetry[T any](
 	ctx *commandContext,
 	profileName string,
 	profile *config.Profile,
-	fn func(*kiteconnect.Client) (T, error),
+	fn func(kiteAPI) (T, error),
 ) (T, error) {
 	var zero T
 
 	client := newKiteClient(*profile, ctx.opts.debug)
-	value, err := fn(client)
+	api, err := ctx.brokerFor(profileName, client)
+	if err != nil {
+		return zero, err
+	}
+	value, err := fn(api)
 	if err == nil {
 		return value, nil
 	}
@@ -136,35 +208,89 @@ func callWithAuthRetry[T any](
 		return zero, wrapKiteError("kite api call failed", err)
 	}
 
-	if profile.RefreshToken == "" || profile.APISecret == "" {
-		return zero, exitcode.Wrap(exitcode.Auth, "access token expired and refresh token is unavailable; run `zerodha auth login`", err)
+	if err := ctx.refreshAccessToken(profileName, profile, client); err != nil {
+		return zero, err
 	}
 
-	renewed, renewErr := client.RenewAccessToken(profile.RefreshToken, profile.APISecret)
-	if renewErr != nil {
-		return zero, exitcode.Wrap(exitcode.Auth, "failed to refresh access token; run `zerodha auth login`", renewErr)
+	client.SetAccessToken(profile.AccessToken)
+	// A paper broker starts over from disk, so an order the failed attempt
+	// already added in memory is not placed twice.
+	if api, err = ctx.brokerFor(profileName, client); err != nil {
+		return zero, err
 	}
-	if renewed.AccessToken == "" {
-		return zero, exitcode.New(exitcode.Auth, "token refresh returned empty access token")
+	value, err = fn(api)
+	if err != nil {
+		return zero, wrapKiteError("kite api call failed after token refresh", err)
 	}
 
-	profile.AccessToken = renewed.AccessToken
-	if renewed.RefreshToken != "" {
-		profile.RefreshToken = renewed.RefreshToken
+	return value, nil
+}
+
+// brokerFor returns client, or with --paper a paper broker in front of it
+// loaded from the saved paper account.
+func (c *commandContext) brokerFor(profileName string, client *kiteconnect.Client) (kiteAPI, error) {
+	if !c.opts.paper {
+		return client, nil
 	}
-	profile.LastLoginAt = time.Now().UTC()
-	ctx.setProfile(profileName, *profile)
-	if err := ctx.save(); err != nil {
-		return zero, err
+	return newPaperBroker(profileName, client)
+}
+
+// refreshAccessToken renews the session under the config lock. If another
+// process renewed it while we waited for the lock, that token is reused
+// instead of calling RenewAccessToken again.
+func (c *commandContext) refreshAccessToken(name string, profile *config.Profile, client *kiteconnect.Client) error {
+	staleToken := profile.AccessToken
+	var refreshErr error
+	lockErr := c.store.WithLock(func(locked config.LockedStore) error {
+		refreshErr = c.renewLocked(locked, name, profile, staleToken, client)
+		return refreshErr
+	})
+	if refreshErr != nil {
+		return refreshErr
+	}
+	if lockErr != nil {
+		return exitcode.Wrap(exitcode.Config, "lock config for token refresh", lockErr)
 	}
+	return nil
+}
 
-	client.SetAccessToken(profile.AccessToken)
-	value, err = fn(client)
+func (c *commandContext) renewLocked(locked config.LockedStore, name string, profile *config.Profile, staleToken string, client *kiteconnect.Client) error {
+	if err := c.reload(locked); err != nil {
+		return err
+	}
+	latest := c.cfg.Profiles[name]
+	delete(c.credentials, name)
+	delete(c.credentialOrigins, name)
+	if err := c.resolveCredentials(name, &latest); err != nil {
+		return err
+	}
+	if latest.AccessToken != "" && latest.AccessToken != staleToken {
+		*profile = latest
+		return nil
+	}
+
+	if latest.RefreshToken == "" || latest.APISecret == "" {
+		return exitcode.New(exitcode.Auth, "access token expired and refresh token is unavailable; run `zerodha auth login`")
+	}
+	renewed, err := client.RenewAccessToken(latest.RefreshToken, latest.APISecret)
 	if err != nil {
-		return zero, wrapKiteError("kite api call failed after token refresh", err)
+		return exitcode.Wrap(exitcode.Auth, "failed to refresh access token; run `zerodha auth login`", err)
+	}
+	if renewed.AccessToken == "" {
+		return exitcode.New(exitcode.Auth, "token refresh returned empty access token")
 	}
 
-	return value, nil
+	latest.AccessToken = renewed.AccessToken
+	if renewed.RefreshToken != "" {
+		latest.RefreshToken = renewed.RefreshToken
+	}
+	latest.LastLoginAt = time.Now().UTC()
+	c.setProfile(name, latest)
+	if err := c.saveWith(locked.Update); err != nil {
+		return err
+	}
+	*profile = latest
+	return nil
 }
 
 func isTokenError(err error) bool {
@@ -249,55 +375,6 @@ func extractRequestToken(input string) string {
 	return input
 }
 
-func captureRequestToken(port int, timeout time.Duration) (string, error) {
-	tokenCh := make(chan string, 1)
-	errCh := make(chan error, 1)
-
-	mux := http.NewServeMux()
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		token := strings.TrimSpace(r.URL.Query().Get("request_token"))
-		if token == "" {
-			http.Error(w, "missing request_token", http.StatusBadRequest)
-			return
-		}
-		_, _ = fmt.Fprintln(w, "Login complete. You can return to the terminal.")
-		select {
-		case tokenCh <- token:
-		default:
-		}
-	})
-
-	server := &http.Server{
-		Addr:              fmt.Sprintf("127.0.0.1:%d", port),
-		Handler:           mux,
-		ReadHeaderTimeout: 5 * time.Second,
-	}
-
-	go func() {
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			errCh <- err
-		}
-	}()
-
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-
-	defer func() {
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer shutdownCancel()
-		_ = server.Shutdown(shutdownCtx)
-	}()
-
-	select {
-	case token := <-tokenCh:
-		return token, nil
-	case err := <-errCh:
-		return "", err
-	case <-ctx.Done():
-		return "", errors.New("timed out waiting for request_token callback")
-	}
-}
-
 func firstRemainingProfile(names []string, excluded string) string {
 	for _, name := range names {
 		if name != excluded {
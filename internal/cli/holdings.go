@@ -4,6 +4,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
 	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
 	"github.com/spf13/cobra"
 	kiteconnect "github.com/zerodha/gokiteconnect/v4"
@@ -11,7 +12,7 @@ import (
 
 func newHoldingsCmd(opts *rootOptions) *cobra.Command {
 	var holdingsLimit int
-	holdingsCmd := &cobra.Command{
+	holdingsCmd := allowFanOut(&cobra.Command{
 		Use:   "holdings",
 		Short: "List current holdings",
 		RunE: func(cmd *cobra.Command, _ []string) error {
@@ -23,6 +24,15 @@ func newHoldingsCmd(opts *rootOptions) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if opts.fanOut() {
+				results, err := fanOutProfiles(ctx, func(ctx *commandContext, name string, profile *config.Profile) (kiteconnect.Holdings, error) {
+					return fetchHoldings(ctx, name, profile, holdingsLimit)
+				})
+				if err != nil {
+					return err
+				}
+				return printFanOut(ctx, cmd, results, holdingHeaders, holdingRows)
+			}
 			profileName, profile, err := ctx.resolveProfile(true)
 			if err != nil {
 				return err
@@ -31,39 +41,24 @@ func newHoldingsCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			holdings, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.Holdings, error) {
-				return client.GetHoldings()
-			})
+			holdings, err := fetchHoldings(ctx, profileName, profile, holdingsLimit)
 			if err != nil {
 				return err
 			}
-			holdings = applyLimit(holdings, holdingsLimit)
 
 			printer := ctx.printer(cmd.OutOrStdout())
 			if printer.IsJSON() {
 				return printer.JSON(holdings)
 			}
 
-			rows := make([][]string, 0, len(holdings))
-			for _, holding := range holdings {
-				rows = append(rows, []string{
-					holding.Tradingsymbol,
-					holding.Exchange,
-					intToString(holding.Quantity),
-					formatFloat(holding.AveragePrice),
-					formatFloat(holding.LastPrice),
-					formatFloat(holding.PnL),
-					formatFloat(holding.DayChangePercentage),
-				})
-			}
+			rows := holdingRows(holdings)
 			if len(rows) == 0 {
 				rows = append(rows, []string{"-", "-", "0", "0.00", "0.00", "0.00", "0.00"})
 			}
 
-			return printer.Table([]string{"SYMBOL", "EXCHANGE", "QTY", "AVG_PRICE", "LTP", "PNL", "DAY_CHANGE_%"},
-				rows)
+			return printer.Table(holdingHeaders, rows)
 		},
-	}
+	})
 	holdingsCmd.Flags().IntVar(&holdingsLimit, "limit", 0, "Limit number of rows (0 = no limit)")
 
 	var auctionsLimit int
@@ -87,7 +82,7 @@ func newHoldingsCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			instruments, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) ([]kiteconnect.AuctionInstrument, error) {
+			instruments, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) ([]kiteconnect.AuctionInstrument, error) {
 				return client.GetAuctionInstruments()
 			})
 			if err != nil {
@@ -152,7 +147,7 @@ func newHoldingsCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.HoldingsAuthResp, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.HoldingsAuthResp, error) {
 				return client.InitiateHoldingsAuth(params)
 			})
 			if err != nil {
@@ -180,6 +175,34 @@ func newHoldingsCmd(opts *rootOptions) *cobra.Command {
 	return holdingsCmd
 }
 
+var holdingHeaders = []string{"SYMBOL", "EXCHANGE", "QTY", "AVG_PRICE", "LTP", "PNL", "DAY_CHANGE_%"}
+
+func fetchHoldings(ctx *commandContext, profileName string, profile *config.Profile, limit int) (kiteconnect.Holdings, error) {
+	holdings, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Holdings, error) {
+		return client.GetHoldings()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applyLimit(holdings, limit), nil
+}
+
+func holdingRows(holdings kiteconnect.Holdings) [][]string {
+	rows := make([][]string, 0, len(holdings))
+	for _, holding := range holdings {
+		rows = append(rows, []string{
+			holding.Tradingsymbol,
+			holding.Exchange,
+			intToString(holding.Quantity),
+			formatFloat(holding.AveragePrice),
+			formatFloat(holding.LastPrice),
+			formatFloat(holding.PnL),
+			formatFloat(holding.DayChangePercentage),
+		})
+	}
+	return rows
+}
+
 func holdingAuthParamsFromFlags(authType, transferType, execDate string, isins []string, quantities []float64) (kiteconnect.HoldingAuthParams, error) {
 	params := kiteconnect.HoldingAuthParams{
 		Type:         strings.ToLower(strings.TrimSpace(authType)),
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/cache"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/paths"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+	"github.com/zerodha/gokiteconnect/v4/models"
+)
+
+var istLocation = time.FixedZone("IST", 5*60*60+30*60)
+
+// cachedInstrument mirrors kiteconnect.Instrument for the on-disk cache.
+// models.Time cannot round-trip a zero expiry through JSON, so dates are
+// stored as plain strings.
+type cachedInstrument struct {
+	InstrumentToken int     `json:"instrument_token"`
+	ExchangeToken   int     `json:"exchange_token"`
+	Tradingsymbol   string  `json:"tradingsymbol"`
+	Name            string  `json:"name"`
+	LastPrice       float64 `json:"last_price"`
+	Expiry          string  `json:"expiry,omitempty"`
+	StrikePrice     float64 `json:"strike"`
+	TickSize        float64 `json:"tick_size"`
+	LotSize         float64 `json:"lot_size"`
+	InstrumentType  string  `json:"instrument_type"`
+	Segment         string  `json:"segment"`
+	Exchange        string  `json:"exchange"`
+}
+
+func instrumentCacheStore() *cache.FSStore {
+	cacheDir, err := paths.DefaultCacheDir()
+	if err != nil {
+		return nil
+	}
+	return cache.NewFSStore(filepath.Join(cacheDir, "instruments"))
+}
+
+// instrumentCacheKey changes once per IST trading day, which is how often
+// Kite regenerates the instrument dump.
+func instrumentCacheKey(exchange string, now time.Time) string {
+	return "instruments:" + exchange + ":" + now.In(istLocation).Format("2006-01-02")
+}
+
+func encodeInstrumentCache(instruments kiteconnect.Instruments) ([]byte, error) {
+	records := make([]cachedInstrument, 0, len(instruments))
+	for _, instrument := range instruments {
+		expiry := ""
+		if !instrument.Expiry.Time.IsZero() {
+			expiry = instrument.Expiry.Time.Format("2006-01-02")
+		}
+		records = append(records, cachedInstrument{
+			InstrumentToken: instrument.InstrumentToken,
+			ExchangeToken:   instrument.ExchangeToken,
+			Tradingsymbol:   instrument.Tradingsymbol,
+			Name:            instrument.Name,
+			LastPrice:       instrument.LastPrice,
+			Expiry:          expiry,
+			StrikePrice:     instrument.StrikePrice,
+			TickSize:        instrument.TickSize,
+			LotSize:         instrument.LotSize,
+			InstrumentType:  instrument.InstrumentType,
+			Segment:         instrument.Segment,
+			Exchange:        instrument.Exchange,
+		})
+	}
+	return json.Marshal(records)
+}
+
+func decodeInstrumentCache(data []byte) (kiteconnect.Instruments, error) {
+	var records []cachedInstrument
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+
+	instruments := make(kiteconnect.Instruments, 0, len(records))
+	for _, record := range records {
+		var expiry models.Time
+		if record.Expiry != "" {
+			t, err := time.ParseInLocation("2006-01-02", record.Expiry, istLocation)
+			if err != nil {
+				return nil, err
+			}
+			expiry = models.Time{Time: t}
+		}
+		instruments = append(instruments, kiteconnect.Instrument{
+			InstrumentToken: record.InstrumentToken,
+			ExchangeToken:   record.ExchangeToken,
+			Tradingsymbol:   record.Tradingsymbol,
+			Name:            record.Name,
+			LastPrice:       record.LastPrice,
+			Expiry:          expiry,
+			StrikePrice:     record.StrikePrice,
+			TickSize:        record.TickSize,
+			LotSize:         record.LotSize,
+			InstrumentType:  record.InstrumentType,
+			Segment:         record.Segment,
+			Exchange:        record.Exchange,
+		})
+	}
+	return instruments, nil
+}
+
+// loadExchangeInstruments returns the instrument master for one exchange,
+// served from the local cache when today's dump has already been fetched.
+func loadExchangeInstruments(
+	ctx *commandContext,
+	profileName string,
+	profile *config.Profile,
+	exchange string,
+) (kiteconnect.Instruments, error) {
+	store := instrumentCacheStore()
+	key := instrumentCacheKey(exchange, time.Now())
+	if store != nil {
+		if data, err := store.Get(key); err == nil {
+			if instruments, err := decodeInstrumentCache(data); err == nil {
+				return instruments, nil
+			}
+		}
+	}
+
+	instruments, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Instruments, error) {
+		return client.GetInstrumentsByExchange(exchange)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if store != nil {
+		if data, err := encodeInstrumentCache(instruments); err == nil {
+			_ = store.Put(key, data)
+		}
+	}
+	return instruments, nil
+}
+
+type instrumentIndex struct {
+	byKey   map[string]kiteconnect.Instrument
+	byToken map[int]kiteconnect.Instrument
+}
+
+func newInstrumentIndex(instruments kiteconnect.Instruments) *instrumentIndex {
+	index := &instrumentIndex{
+		byKey:   make(map[string]kiteconnect.Instrument, len(instruments)),
+		byToken: make(map[int]kiteconnect.Instrument, len(instruments)),
+	}
+	for _, instrument := range instruments {
+		index.byKey[instrumentKey(instrument)] = instrument
+		index.byToken[instrument.InstrumentToken] = instrument
+	}
+	return index
+}
+
+func (i *instrumentIndex) lookup(key string) (kiteconnect.Instrument, bool) {
+	instrument, ok := i.byKey[strings.ToUpper(strings.TrimSpace(key))]
+	return instrument, ok
+}
+
+func (i *instrumentIndex) token(token int) (kiteconnect.Instrument, bool) {
+	instrument, ok := i.byToken[token]
+	return instrument, ok
+}
+
+func instrumentKey(instrument kiteconnect.Instrument) string {
+	return strings.ToUpper(instrument.Exchange + ":" + instrument.Tradingsymbol)
+}
+
+func splitInstrumentKey(key string) (string, string, error) {
+	exchange, symbol, ok := strings.Cut(strings.TrimSpace(key), ":")
+	exchange = normalizeUpper(exchange)
+	symbol = strings.TrimSpace(symbol)
+	if !ok || exchange == "" || symbol == "" {
+		return "", "", exitcode.New(exitcode.Validation, fmt.Sprintf("invalid instrument %q; use EXCHANGE:SYMBOL", key))
+	}
+	return exchange, symbol, nil
+}
+
+// resolveInstruments maps EXCHANGE:SYMBOL keys to instrument master rows,
+// loading each referenced exchange once.
+func resolveInstruments(
+	ctx *commandContext,
+	profileName string,
+	profile *config.Profile,
+	keys []string,
+) ([]kiteconnect.Instrument, *instrumentIndex, error) {
+	exchanges := make(map[string]struct{})
+	for _, key := range keys {
+		exchange, _, err := splitInstrumentKey(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		exchanges[exchange] = struct{}{}
+	}
+
+	names := make([]string, 0, len(exchanges))
+	for exchange := range exchanges {
+		names = append(names, exchange)
+	}
+	sort.Strings(names)
+
+	var all kiteconnect.Instruments
+	for _, exchange := range names {
+		instruments, err := loadExchangeInstruments(ctx, profileName, profile, exchange)
+		if err != nil {
+			return nil, nil, err
+		}
+		all = append(all, instruments...)
+	}
+
+	index := newInstrumentIndex(all)
+	resolved := make([]kiteconnect.Instrument, 0, len(keys))
+	for _, key := range keys {
+		instrument, ok := index.lookup(key)
+		if !ok {
+			return nil, nil, exitcode.New(exitcode.Validation, fmt.Sprintf("instrument %q not found in instrument master", key))
+		}
+		resolved = append(resolved, instrument)
+	}
+	return resolved, index, nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+	"github.com/zerodha/gokiteconnect/v4/models"
+)
+
+// seedInstrumentCache points the user cache at a temp dir and stores today's
+// instrument master for exchange, so commands resolve symbols offline.
+func seedInstrumentCache(t *testing.T, exchange string, instruments kiteconnect.Instruments) {
+	t.Helper()
+
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("HOME", cacheHome)
+
+	data, err := encodeInstrumentCache(instruments)
+	if err != nil {
+		t.Fatalf("encode instruments: %v", err)
+	}
+	if err := instrumentCacheStore().Put(instrumentCacheKey(exchange, time.Now()), data); err != nil {
+		t.Fatalf("seed instrument cache: %v", err)
+	}
+}
+
+func TestInstrumentCacheRoundTripPreservesExpiry(t *testing.T) {
+	expiry := time.Date(2026, 3, 26, 0, 0, 0, 0, istLocation)
+	instruments := kiteconnect.Instruments{
+		{InstrumentToken: 408065, Tradingsymbol: "INFY", Exchange: "NSE", InstrumentType: "EQ"},
+		{
+			InstrumentToken: 12345,
+			Tradingsymbol:   "NIFTY26MAR22000CE",
+			Name:            "NIFTY",
+			Exchange:        "NFO",
+			InstrumentType:  "CE",
+			StrikePrice:     22000,
+			LotSize:         75,
+			Expiry:          models.Time{Time: expiry},
+		},
+	}
+
+	data, err := encodeInstrumentCache(instruments)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	decoded, err := decodeInstrumentCache(data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("expected 2 instruments, got %d", len(decoded))
+	}
+	if !decoded[0].Expiry.Time.IsZero() {
+		t.Fatalf("expected zero expiry for equity, got %s", decoded[0].Expiry.Time)
+	}
+	if !decoded[1].Expiry.Time.Equal(expiry) {
+		t.Fatalf("expected expiry %s, got %s", expiry, decoded[1].Expiry.Time)
+	}
+	if decoded[1].StrikePrice != 22000 || decoded[1].LotSize != 75 {
+		t.Fatalf("unexpected option fields: %+v", decoded[1])
+	}
+}
+
+func TestInstrumentIndexLookupIsCaseInsensitive(t *testing.T) {
+	index := newInstrumentIndex(kiteconnect.Instruments{
+		{InstrumentToken: 256265, Tradingsymbol: "NIFTY 50", Exchange: "NSE"},
+	})
+
+	instrument, ok := index.lookup("nse:nifty 50")
+	if !ok || instrument.InstrumentToken != 256265 {
+		t.Fatalf("expected NIFTY 50 lookup to resolve, got %+v (ok=%v)", instrument, ok)
+	}
+	if _, ok := index.token(256265); !ok {
+		t.Fatalf("expected token lookup to resolve")
+	}
+	if _, _, err := splitInstrumentKey("INFY"); err == nil {
+		t.Fatalf("expected error for key without exchange")
+	}
+}
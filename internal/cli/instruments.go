@@ -54,11 +54,11 @@ func newInstrumentsCmd(opts *rootOptions) *cobra.Command {
 
 			var instruments kiteconnect.Instruments
 			if exchangeValue == "" {
-				instruments, err = callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.Instruments, error) {
+				instruments, err = callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Instruments, error) {
 					return client.GetInstruments()
 				})
 			} else {
-				instruments, err = callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.Instruments, error) {
+				instruments, err = callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Instruments, error) {
 					return client.GetInstrumentsByExchange(exchangeValue)
 				})
 			}
@@ -139,7 +139,7 @@ func newInstrumentsCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			instruments, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFInstruments, error) {
+			instruments, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFInstruments, error) {
 				return client.GetMFInstruments()
 			})
 			if err != nil {
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"time"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+// kiteAPI is the subset of *kiteconnect.Client that commands call through
+// callWithAuthRetry. It lets --paper swap in the simulated broker.
+type kiteAPI interface {
+	GetUserProfile() (kiteconnect.UserProfile, error)
+	GetFullUserProfile() (kiteconnect.FullUserProfile, error)
+	InvalidateRefreshToken(refreshToken string) (bool, error)
+
+	GetQuote(instruments ...string) (kiteconnect.Quote, error)
+	GetLTP(instruments ...string) (kiteconnect.QuoteLTP, error)
+	GetOHLC(instruments ...string) (kiteconnect.QuoteOHLC, error)
+	GetHistoricalData(instrumentToken int, interval string, fromDate time.Time, toDate time.Time, continuous bool, oi bool) ([]kiteconnect.HistoricalData, error)
+	GetInstruments() (kiteconnect.Instruments, error)
+	GetInstrumentsByExchange(exchange string) (kiteconnect.Instruments, error)
+
+	PlaceOrder(variety string, orderParams kiteconnect.OrderParams) (kiteconnect.OrderResponse, error)
+	ModifyOrder(variety string, orderID string, orderParams kiteconnect.OrderParams) (kiteconnect.OrderResponse, error)
+	CancelOrder(variety string, orderID string, parentOrderID *string) (kiteconnect.OrderResponse, error)
+	ExitOrder(variety string, orderID string, parentOrderID *string) (kiteconnect.OrderResponse, error)
+	GetOrders() (kiteconnect.Orders, error)
+	GetOrderHistory(orderID string) ([]kiteconnect.Order, error)
+	GetOrderTrades(orderID string) ([]kiteconnect.Trade, error)
+	GetTrades() (kiteconnect.Trades, error)
+
+	GetPositions() (kiteconnect.Positions, error)
+	ConvertPosition(positionParams kiteconnect.ConvertPositionParams) (bool, error)
+	GetHoldings() (kiteconnect.Holdings, error)
+	GetAuctionInstruments() ([]kiteconnect.AuctionInstrument, error)
+	InitiateHoldingsAuth(params kiteconnect.HoldingAuthParams) (kiteconnect.HoldingsAuthResp, error)
+
+	GetUserMargins() (kiteconnect.AllMargins, error)
+	GetUserSegmentMargins(segment string) (kiteconnect.Margins, error)
+	GetOrderMargins(params kiteconnect.GetMarginParams) ([]kiteconnect.OrderMargins, error)
+	GetBasketMargins(params kiteconnect.GetBasketParams) (kiteconnect.BasketMargins, error)
+	GetOrderCharges(params kiteconnect.GetChargesParams) ([]kiteconnect.OrderCharges, error)
+
+	GetGTTs() (kiteconnect.GTTs, error)
+	GetGTT(triggerID int) (kiteconnect.GTT, error)
+	PlaceGTT(o kiteconnect.GTTParams) (kiteconnect.GTTResponse, error)
+	ModifyGTT(triggerID int, o kiteconnect.GTTParams) (kiteconnect.GTTResponse, error)
+	DeleteGTT(triggerID int) (kiteconnect.GTTResponse, error)
+
+	GetMFInstruments() (kiteconnect.MFInstruments, error)
+	GetMFOrders() (kiteconnect.MFOrders, error)
+	GetMFOrdersByDate(fromDate, toDate string) (kiteconnect.MFOrders, error)
+	GetMFOrderInfo(orderID string) (kiteconnect.MFOrder, error)
+	PlaceMFOrder(orderParams kiteconnect.MFOrderParams) (kiteconnect.MFOrderResponse, error)
+	CancelMFOrder(orderID string) (kiteconnect.MFOrderResponse, error)
+	GetMFSIPs() (kiteconnect.MFSIPs, error)
+	GetMFSIPInfo(sipID string) (kiteconnect.MFSIP, error)
+	PlaceMFSIP(sipParams kiteconnect.MFSIPParams) (kiteconnect.MFSIPResponse, error)
+	ModifyMFSIP(sipID string, sipParams kiteconnect.MFSIPModifyParams) (kiteconnect.MFSIPResponse, error)
+	CancelMFSIP(sipID string) (kiteconnect.MFSIPResponse, error)
+	GetMFHoldings() (kiteconnect.MFHoldings, error)
+	GetMFHoldingInfo(isin string) (kiteconnect.MFHoldingBreakdown, error)
+	GetMFAllottedISINs() (kiteconnect.MFAllottedISINs, error)
+}
+
+var _ kiteAPI = (*kiteconnect.Client)(nil)
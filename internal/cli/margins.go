@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
 	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
 	"github.com/spf13/cobra"
 	kiteconnect "github.com/zerodha/gokiteconnect/v4"
@@ -12,7 +13,7 @@ import (
 func newMarginsCmd(opts *rootOptions) *cobra.Command {
 	var segment string
 
-	marginsCmd := &cobra.Command{
+	marginsCmd := allowFanOut(&cobra.Command{
 		Use:   "margins",
 		Short: "Margin summaries and calculators",
 		RunE: func(cmd *cobra.Command, _ []string) error {
@@ -20,6 +21,34 @@ func newMarginsCmd(opts *rootOptions) *cobra.Command {
 			if err != nil {
 				return err
 			}
+
+			segmentValue := strings.ToLower(strings.TrimSpace(segment))
+			allSegments := segmentValue == "" || segmentValue == "all"
+			if opts.fanOut() {
+				if allSegments {
+					results, err := fanOutProfiles(ctx, func(ctx *commandContext, name string, profile *config.Profile) (kiteconnect.AllMargins, error) {
+						return callWithAuthRetry(ctx, name, profile, func(client kiteAPI) (kiteconnect.AllMargins, error) {
+							return client.GetUserMargins()
+						})
+					})
+					if err != nil {
+						return err
+					}
+					return printFanOut(ctx, cmd, results, marginHeaders, allMarginRows)
+				}
+				results, err := fanOutProfiles(ctx, func(ctx *commandContext, name string, profile *config.Profile) (kiteconnect.Margins, error) {
+					return callWithAuthRetry(ctx, name, profile, func(client kiteAPI) (kiteconnect.Margins, error) {
+						return client.GetUserSegmentMargins(segmentValue)
+					})
+				})
+				if err != nil {
+					return err
+				}
+				return printFanOut(ctx, cmd, results, marginHeaders, func(margin kiteconnect.Margins) [][]string {
+					return [][]string{segmentMarginRow(segmentValue, margin)}
+				})
+			}
+
 			profileName, profile, err := ctx.resolveProfile(true)
 			if err != nil {
 				return err
@@ -28,9 +57,8 @@ func newMarginsCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			segmentValue := strings.ToLower(strings.TrimSpace(segment))
-			if segmentValue == "" || segmentValue == "all" {
-				allMargins, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.AllMargins, error) {
+			if allSegments {
+				allMargins, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.AllMargins, error) {
 					return client.GetUserMargins()
 				})
 				if err != nil {
@@ -41,15 +69,10 @@ func newMarginsCmd(opts *rootOptions) *cobra.Command {
 				if printer.IsJSON() {
 					return printer.JSON(allMargins)
 				}
-
-				rows := [][]string{
-					{"equity", formatFloat(allMargins.Equity.Net), formatFloat(allMargins.Equity.Available.Cash), formatFloat(allMargins.Equity.Used.Debits)},
-					{"commodity", formatFloat(allMargins.Commodity.Net), formatFloat(allMargins.Commodity.Available.Cash), formatFloat(allMargins.Commodity.Used.Debits)},
-				}
-				return printer.Table([]string{"SEGMENT", "NET", "AVAILABLE_CASH", "USED_DEBITS"}, rows)
+				return printer.Table(marginHeaders, allMarginRows(allMargins))
 			}
 
-			margin, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.Margins, error) {
+			margin, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Margins, error) {
 				return client.GetUserSegmentMargins(segmentValue)
 			})
 			if err != nil {
@@ -60,12 +83,9 @@ func newMarginsCmd(opts *rootOptions) *cobra.Command {
 			if printer.IsJSON() {
 				return printer.JSON(margin)
 			}
-			rows := [][]string{
-				{segmentValue, formatFloat(margin.Net), formatFloat(margin.Available.Cash), formatFloat(margin.Used.Debits)},
-			}
-			return printer.Table([]string{"SEGMENT", "NET", "AVAILABLE_CASH", "USED_DEBITS"}, rows)
+			return printer.Table(marginHeaders, [][]string{segmentMarginRow(segmentValue, margin)})
 		},
-	}
+	})
 	marginsCmd.Flags().StringVar(&segment, "segment", "all", "Margin segment: all/equity/commodity")
 
 	var (
@@ -93,7 +113,7 @@ func newMarginsCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			margins, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) ([]kiteconnect.OrderMargins, error) {
+			margins, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) ([]kiteconnect.OrderMargins, error) {
 				return client.GetOrderMargins(kiteconnect.GetMarginParams{
 					OrderParams: []kiteconnect.OrderMarginParam{param},
 					Compact:     orderCompact,
@@ -154,7 +174,7 @@ func newMarginsCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			margins, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.BasketMargins, error) {
+			margins, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.BasketMargins, error) {
 				return client.GetBasketMargins(kiteconnect.GetBasketParams{
 					OrderParams:       []kiteconnect.OrderMarginParam{param},
 					Compact:           basketCompact,
@@ -225,7 +245,7 @@ func newMarginsCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			charges, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) ([]kiteconnect.OrderCharges, error) {
+			charges, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) ([]kiteconnect.OrderCharges, error) {
 				return client.GetOrderCharges(kiteconnect.GetChargesParams{
 					OrderParams: []kiteconnect.OrderChargesParam{param},
 				})
@@ -282,6 +302,19 @@ type marginChargesFlags struct {
 	averagePrice float64
 }
 
+var marginHeaders = []string{"SEGMENT", "NET", "AVAILABLE_CASH", "USED_DEBITS"}
+
+func allMarginRows(margins kiteconnect.AllMargins) [][]string {
+	return [][]string{
+		segmentMarginRow("equity", margins.Equity),
+		segmentMarginRow("commodity", margins.Commodity),
+	}
+}
+
+func segmentMarginRow(segment string, margin kiteconnect.Margins) []string {
+	return []string{segment, formatFloat(margin.Net), formatFloat(margin.Available.Cash), formatFloat(margin.Used.Debits)}
+}
+
 func bindMarginOrderFlags(cmd *cobra.Command, flags *marginOrderFlags) {
 	cmd.Flags().StringVar(&flags.exchange, "exchange", "", "Exchange (NSE/BSE/NFO/...)")
 	cmd.Flags().StringVar(&flags.symbol, "symbol", "", "Trading symbol")
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
 	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
 	"github.com/spf13/cobra"
 	kiteconnect "github.com/zerodha/gokiteconnect/v4"
@@ -73,7 +74,7 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFOrderResponse, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFOrderResponse, error) {
 				return client.PlaceMFOrder(params)
 			})
 			if err != nil {
@@ -138,11 +139,11 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 
 			var orders kiteconnect.MFOrders
 			if from == "" {
-				orders, err = callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFOrders, error) {
+				orders, err = callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFOrders, error) {
 					return client.GetMFOrders()
 				})
 			} else {
-				orders, err = callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFOrders, error) {
+				orders, err = callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFOrders, error) {
 					return client.GetMFOrdersByDate(from, to)
 				})
 			}
@@ -200,7 +201,7 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			order, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFOrder, error) {
+			order, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFOrder, error) {
 				return client.GetMFOrderInfo(orderID)
 			})
 			if err != nil {
@@ -247,7 +248,7 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFOrderResponse, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFOrderResponse, error) {
 				return client.CancelMFOrder(orderID)
 			})
 			if err != nil {
@@ -298,7 +299,7 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFSIPResponse, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFSIPResponse, error) {
 				return client.PlaceMFSIP(params)
 			})
 			if err != nil {
@@ -363,7 +364,7 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFSIPResponse, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFSIPResponse, error) {
 				return client.ModifyMFSIP(sipID, params)
 			})
 			if err != nil {
@@ -420,7 +421,7 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFSIPResponse, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFSIPResponse, error) {
 				return client.CancelMFSIP(sipID)
 			})
 			if err != nil {
@@ -470,7 +471,7 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			sips, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFSIPs, error) {
+			sips, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFSIPs, error) {
 				return client.GetMFSIPs()
 			})
 			if err != nil {
@@ -526,7 +527,7 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			sip, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFSIP, error) {
+			sip, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFSIP, error) {
 				return client.GetMFSIPInfo(sipID)
 			})
 			if err != nil {
@@ -554,7 +555,7 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 	sipsCmd.AddCommand(sipPlaceCmd, sipModifyCmd, sipCancelCmd, sipListCmd, sipShowCmd)
 
 	var holdingsLimit int
-	holdingsCmd := &cobra.Command{
+	holdingsCmd := allowFanOut(&cobra.Command{
 		Use:   "holdings",
 		Short: "List mutual fund holdings",
 		RunE: func(cmd *cobra.Command, _ []string) error {
@@ -566,6 +567,15 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if opts.fanOut() {
+				results, err := fanOutProfiles(ctx, func(ctx *commandContext, name string, profile *config.Profile) (kiteconnect.MFHoldings, error) {
+					return fetchMFHoldings(ctx, name, profile, holdingsLimit)
+				})
+				if err != nil {
+					return err
+				}
+				return printFanOut(ctx, cmd, results, mfHoldingHeaders, mfHoldingRows)
+			}
 			profileName, profile, err := ctx.resolveProfile(true)
 			if err != nil {
 				return err
@@ -574,38 +584,23 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			holdings, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFHoldings, error) {
-				return client.GetMFHoldings()
-			})
+			holdings, err := fetchMFHoldings(ctx, profileName, profile, holdingsLimit)
 			if err != nil {
 				return err
 			}
-			holdings = applyLimit(holdings, holdingsLimit)
 
 			printer := ctx.printer(cmd.OutOrStdout())
 			if printer.IsJSON() {
 				return printer.JSON(holdings)
 			}
 
-			rows := make([][]string, 0, len(holdings))
-			for _, holding := range holdings {
-				rows = append(rows, []string{
-					holding.Tradingsymbol,
-					holding.Fund,
-					holding.Folio,
-					formatFloat(holding.Quantity),
-					formatFloat(holding.AveragePrice),
-					formatFloat(holding.LastPrice),
-					formatFloat(holding.Pnl),
-					holding.LastPriceDate,
-				})
-			}
+			rows := mfHoldingRows(holdings)
 			if len(rows) == 0 {
 				rows = append(rows, []string{"-", "-", "-", "0.00", "0.00", "0.00", "0.00", "-"})
 			}
-			return printer.Table([]string{"SYMBOL", "FUND", "FOLIO", "QTY", "AVG_PRICE", "LAST_PRICE", "PNL", "LAST_PRICE_DATE"}, rows)
+			return printer.Table(mfHoldingHeaders, rows)
 		},
-	}
+	})
 	holdingsCmd.Flags().IntVar(&holdingsLimit, "limit", 0, "Limit number of rows (0 = no limit)")
 
 	var holdingISIN string
@@ -634,7 +629,7 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			breakdown, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFHoldingBreakdown, error) {
+			breakdown, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFHoldingBreakdown, error) {
 				return client.GetMFHoldingInfo(isin)
 			})
 			if err != nil {
@@ -689,7 +684,7 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			isins, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.MFAllottedISINs, error) {
+			isins, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFAllottedISINs, error) {
 				return client.GetMFAllottedISINs()
 			})
 			if err != nil {
@@ -720,6 +715,35 @@ func newMFCmd(opts *rootOptions) *cobra.Command {
 	return mfCmd
 }
 
+var mfHoldingHeaders = []string{"SYMBOL", "FUND", "FOLIO", "QTY", "AVG_PRICE", "LAST_PRICE", "PNL", "LAST_PRICE_DATE"}
+
+func fetchMFHoldings(ctx *commandContext, profileName string, profile *config.Profile, limit int) (kiteconnect.MFHoldings, error) {
+	holdings, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.MFHoldings, error) {
+		return client.GetMFHoldings()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applyLimit(holdings, limit), nil
+}
+
+func mfHoldingRows(holdings kiteconnect.MFHoldings) [][]string {
+	rows := make([][]string, 0, len(holdings))
+	for _, holding := range holdings {
+		rows = append(rows, []string{
+			holding.Tradingsymbol,
+			holding.Fund,
+			holding.Folio,
+			formatFloat(holding.Quantity),
+			formatFloat(holding.AveragePrice),
+			formatFloat(holding.LastPrice),
+			formatFloat(holding.Pnl),
+			holding.LastPriceDate,
+		})
+	}
+	return rows
+}
+
 func mfOrderParamsFromFlags(flags mfOrderFlags) (kiteconnect.MFOrderParams, error) {
 	params := kiteconnect.MFOrderParams{
 		Tradingsymbol:   strings.TrimSpace(flags.symbol),
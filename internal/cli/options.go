@@ -0,0 +1,756 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/cache"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/paths"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/pricing"
+	"github.com/spf13/cobra"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+const (
+	optionExpiryNearest  = "nearest"
+	defaultOptionStrikes = 10
+)
+
+// indexSpotInstruments maps F&O index underlyings to the instrument whose
+// LTP is used as spot. Stock underlyings default to NSE:<UNDERLYING>.
+var indexSpotInstruments = map[string]string{
+	"NIFTY":      "NSE:NIFTY 50",
+	"BANKNIFTY":  "NSE:NIFTY BANK",
+	"FINNIFTY":   "NSE:NIFTY FIN SERVICE",
+	"MIDCPNIFTY": "NSE:NIFTY MID SELECT",
+	"NIFTYNXT50": "NSE:NIFTY NEXT 50",
+	"SENSEX":     "BSE:SENSEX",
+	"BANKEX":     "BSE:BANKEX",
+}
+
+func newOptionsCmd(opts *rootOptions) *cobra.Command {
+	optionsCmd := &cobra.Command{
+		Use:   "options",
+		Short: "Option chain and derivatives analytics",
+	}
+
+	optionsCmd.AddCommand(newOptionsChainCmd(opts), newOptionsGreeksCmd(opts), newOptionsStrategyCmd(opts))
+	return optionsCmd
+}
+
+func newOptionsChainCmd(opts *rootOptions) *cobra.Command {
+	var (
+		chainExpiry   string
+		chainStrikes  int
+		chainExchange string
+		chainGreeks   bool
+		chainFlags    greeksFlags
+	)
+	chainCmd := &cobra.Command{
+		Use:   "chain <UNDERLYING>",
+		Short: "Show the option chain around ATM for an underlying",
+		Long: strings.Join([]string{
+			"Selects CE/PE contracts for the underlying and expiry from the instrument master and fetches their quotes.",
+			"OI_CHG compares current OI with the last OI this command recorded on a previous day, and is blank until such a snapshot exists.",
+			"ATM and --greeks use the spot LTP, or the nearest futures LTP with --underlying futures, which prices with Black-76 instead of Black-Scholes.",
+		}, " "),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			underlying := normalizeUpper(args[0])
+			if underlying == "" {
+				return exitcode.New(exitcode.Validation, "underlying cannot be empty")
+			}
+			if chainStrikes <= 0 {
+				return exitcode.New(exitcode.Validation, "--strikes must be > 0")
+			}
+			exchange := normalizeUpper(chainExchange)
+			if exchange == "" {
+				return exitcode.New(exitcode.Validation, "--exchange cannot be empty")
+			}
+			if err := validateGreeksFlags(chainFlags); err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			profileName, profile, err := ctx.resolveProfile(true)
+			if err != nil {
+				return err
+			}
+			if err := ensureAccessToken(profile); err != nil {
+				return err
+			}
+
+			instruments, err := loadExchangeInstruments(ctx, profileName, profile, exchange)
+			if err != nil {
+				return err
+			}
+			contracts := optionContracts(instruments, underlying)
+			if len(contracts) == 0 {
+				return exitcode.New(exitcode.Validation, fmt.Sprintf("no %s option contracts found for %s", exchange, underlying))
+			}
+			now := time.Now()
+			expiry, err := selectOptionExpiry(contracts, chainExpiry, now)
+			if err != nil {
+				return err
+			}
+			strikes := optionStrikes(contracts, expiry)
+
+			underlyingPrices, err := resolveUnderlyingPrices(ctx, profileName, profile, chainFlags, contracts[:1], newInstrumentIndex(instruments), now)
+			if err != nil {
+				return err
+			}
+			spot := underlyingPrices[underlying]
+
+			atm := nearestStrikeIndex(strikes, spot)
+			window := strikes[max(0, atm-chainStrikes):min(len(strikes), atm+chainStrikes+1)]
+
+			keys := make([]string, 0, 2*len(window))
+			for _, strike := range window {
+				for _, contract := range []*kiteconnect.Instrument{strike.call, strike.put} {
+					if contract != nil {
+						keys = append(keys, instrumentKey(*contract))
+					}
+				}
+			}
+			quotes, err := fetchQuotesBatched(ctx, profileName, profile, keys)
+			if err != nil {
+				return err
+			}
+
+			snapshots := newOISnapshotStore(exchange)
+			baseline := snapshots.baseline(now)
+			chain := optionChain{
+				Underlying: underlying,
+				Exchange:   exchange,
+				Expiry:     expiry,
+				Spot:       spot,
+				ATMStrike:  strikes[atm].strike,
+				Strikes:    make([]optionChainStrike, 0, len(window)),
+			}
+			rate := ctx.riskFreeRate(cmd, chainFlags)
+			model := chainFlags.model()
+			current := make(map[string]float64, len(keys))
+			for _, strike := range window {
+				row := optionChainStrike{Strike: strike.strike}
+				row.Call = optionChainLegFromQuote(strike.call, quotes, baseline, current)
+				row.Put = optionChainLegFromQuote(strike.put, quotes, baseline, current)
+				if chainGreeks {
+					row.Call = withGreeks(row.Call, strike.call, model, spot, rate, now)
+					row.Put = withGreeks(row.Put, strike.put, model, spot, rate, now)
+				}
+				chain.Strikes = append(chain.Strikes, row)
+			}
+			snapshots.record(now, current)
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(chain)
+			}
+
+			if err := printer.KV([][2]string{
+				{"underlying", chain.Underlying},
+				{"expiry", chain.Expiry},
+				{"spot", formatFloat(chain.Spot)},
+				{"atm_strike", formatFloat(chain.ATMStrike)},
+			}); err != nil {
+				return err
+			}
+			return printer.Table(optionChainHeaders(chainGreeks), optionChainRows(chain, chainGreeks))
+		},
+	}
+	chainCmd.Flags().StringVar(&chainExpiry, "expiry", optionExpiryNearest, "Expiry date (YYYY-MM-DD) or nearest")
+	chainCmd.Flags().IntVar(&chainStrikes, "strikes", defaultOptionStrikes, "Number of strikes to show on each side of ATM")
+	chainCmd.Flags().StringVar(&chainExchange, "exchange", "NFO", "Derivatives exchange (NFO, BFO)")
+	chainCmd.Flags().BoolVar(&chainGreeks, "greeks", false, "Add implied volatility and delta columns (full Greeks in JSON)")
+	bindGreeksFlags(chainCmd, &chainFlags)
+	return chainCmd
+}
+
+type optionChain struct {
+	Underlying string              `json:"underlying"`
+	Exchange   string              `json:"exchange"`
+	Expiry     string              `json:"expiry"`
+	Spot       float64             `json:"spot"`
+	ATMStrike  float64             `json:"atm_strike"`
+	Strikes    []optionChainStrike `json:"strikes"`
+}
+
+type optionChainStrike struct {
+	Strike float64         `json:"strike"`
+	Call   *optionChainLeg `json:"call,omitempty"`
+	Put    *optionChainLeg `json:"put,omitempty"`
+}
+
+type optionChainLeg struct {
+	Tradingsymbol   string           `json:"tradingsymbol"`
+	InstrumentToken int              `json:"instrument_token"`
+	LotSize         float64          `json:"lot_size"`
+	LastPrice       float64          `json:"last_price"`
+	OI              float64          `json:"oi"`
+	OIChange        *float64         `json:"oi_change,omitempty"`
+	Volume          int              `json:"volume"`
+	Bid             float64          `json:"bid"`
+	Ask             float64          `json:"ask"`
+	Greeks          *optionAnalytics `json:"greeks,omitempty"`
+}
+
+type optionStrike struct {
+	strike float64
+	call   *kiteconnect.Instrument
+	put    *kiteconnect.Instrument
+}
+
+func defaultSpotInstrument(underlying string) string {
+	if key, ok := indexSpotInstruments[underlying]; ok {
+		return key
+	}
+	return "NSE:" + underlying
+}
+
+func optionContracts(instruments kiteconnect.Instruments, underlying string) kiteconnect.Instruments {
+	var contracts kiteconnect.Instruments
+	for _, instrument := range instruments {
+		if !strings.EqualFold(instrument.Name, underlying) {
+			continue
+		}
+		if instrument.InstrumentType != "CE" && instrument.InstrumentType != "PE" {
+			continue
+		}
+		if instrument.Expiry.Time.IsZero() {
+			continue
+		}
+		contracts = append(contracts, instrument)
+	}
+	return contracts
+}
+
+func optionExpiryDate(instrument kiteconnect.Instrument) string {
+	return instrument.Expiry.Time.Format("2006-01-02")
+}
+
+// selectOptionExpiry resolves --expiry to one of the contract expiries.
+// "nearest" picks the earliest expiry on or after today in IST.
+func selectOptionExpiry(contracts kiteconnect.Instruments, raw string, now time.Time) (string, error) {
+	expiries := make([]string, 0)
+	for _, contract := range contracts {
+		expiry := optionExpiryDate(contract)
+		if !slices.Contains(expiries, expiry) {
+			expiries = append(expiries, expiry)
+		}
+	}
+	slices.Sort(expiries)
+
+	value := strings.ToLower(strings.TrimSpace(raw))
+	if value == "" || value == optionExpiryNearest {
+		today := now.In(istLocation).Format("2006-01-02")
+		for _, expiry := range expiries {
+			if expiry >= today {
+				return expiry, nil
+			}
+		}
+		return "", exitcode.New(exitcode.Validation, "no unexpired option contracts found")
+	}
+
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		return "", exitcode.New(exitcode.Validation, "invalid --expiry; use YYYY-MM-DD or nearest")
+	}
+	if !slices.Contains(expiries, value) {
+		return "", exitcode.New(exitcode.Validation, fmt.Sprintf("no contracts expire on %s; available: %s", value, strings.Join(expiries, ", ")))
+	}
+	return value, nil
+}
+
+// optionStrikes pairs CE and PE contracts for one expiry, sorted by strike.
+func optionStrikes(contracts kiteconnect.Instruments, expiry string) []optionStrike {
+	byStrike := make(map[float64]*optionStrike)
+	for i := range contracts {
+		contract := &contracts[i]
+		if optionExpiryDate(*contract) != expiry {
+			continue
+		}
+		strike, ok := byStrike[contract.StrikePrice]
+		if !ok {
+			strike = &optionStrike{strike: contract.StrikePrice}
+			byStrike[contract.StrikePrice] = strike
+		}
+		if contract.InstrumentType == "CE" {
+			strike.call = contract
+		} else {
+			strike.put = contract
+		}
+	}
+
+	strikes := make([]optionStrike, 0, len(byStrike))
+	for _, strike := range byStrike {
+		strikes = append(strikes, *strike)
+	}
+	slices.SortFunc(strikes, func(a, b optionStrike) int {
+		switch {
+		case a.strike < b.strike:
+			return -1
+		case a.strike > b.strike:
+			return 1
+		}
+		return 0
+	})
+	return strikes
+}
+
+func nearestStrikeIndex(strikes []optionStrike, spot float64) int {
+	best := 0
+	for i, strike := range strikes {
+		if math.Abs(strike.strike-spot) < math.Abs(strikes[best].strike-spot) {
+			best = i
+		}
+	}
+	return best
+}
+
+func optionChainLegFromQuote(
+	contract *kiteconnect.Instrument,
+	quotes kiteconnect.Quote,
+	baseline map[string]float64,
+	current map[string]float64,
+) *optionChainLeg {
+	if contract == nil {
+		return nil
+	}
+
+	leg := &optionChainLeg{
+		Tradingsymbol:   contract.Tradingsymbol,
+		InstrumentToken: contract.InstrumentToken,
+		LotSize:         contract.LotSize,
+	}
+	q, ok := quotes[instrumentKey(*contract)]
+	if !ok {
+		return leg
+	}
+	leg.LastPrice = q.LastPrice
+	leg.OI = q.OI
+	leg.Volume = q.Volume
+	leg.Bid = q.Depth.Buy[0].Price
+	leg.Ask = q.Depth.Sell[0].Price
+
+	token := strconv.Itoa(contract.InstrumentToken)
+	current[token] = q.OI
+	if previous, ok := baseline[token]; ok {
+		leg.OIChange = new(q.OI - previous)
+	}
+	return leg
+}
+
+func optionChainHeaders(greeks bool) []string {
+	call := []string{"CE_OI", "CE_OI_CHG", "CE_VOLUME", "CE_BID", "CE_ASK", "CE_LTP"}
+	put := []string{"PE_LTP", "PE_BID", "PE_ASK", "PE_VOLUME", "PE_OI_CHG", "PE_OI"}
+	if greeks {
+		call = append([]string{"CE_IV", "CE_DELTA"}, call...)
+		put = append(put, "PE_DELTA", "PE_IV")
+	}
+	headers := append(call, "STRIKE")
+	return append(headers, put...)
+}
+
+func optionChainRows(chain optionChain, greeks bool) [][]string {
+	rows := make([][]string, 0, len(chain.Strikes))
+	for _, strike := range chain.Strikes {
+		label := formatFloat(strike.Strike)
+		if strike.Strike == chain.ATMStrike {
+			label += " *"
+		}
+
+		call := optionChainLegCells(strike.Call)
+		put := optionChainLegCells(strike.Put)
+		var row []string
+		if greeks {
+			iv, delta := optionGreeksCells(strike.Call)
+			row = append(row, iv, delta)
+		}
+		row = append(row, call[0], call[1], call[2], call[3], call[4], call[5], label)
+		row = append(row, put[5], put[3], put[4], put[2], put[1], put[0])
+		if greeks {
+			iv, delta := optionGreeksCells(strike.Put)
+			row = append(row, delta, iv)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// withGreeks attaches analytics to a chain leg; legs whose price has no
+// implied volatility (no trades, or outside no-arbitrage bounds) are left bare.
+func withGreeks(leg *optionChainLeg, contract *kiteconnect.Instrument, model pricing.Model, underlying, rate float64, now time.Time) *optionChainLeg {
+	if leg == nil || contract == nil || leg.LastPrice <= 0 {
+		return leg
+	}
+	leg.Greeks, _ = analyzeOption(*contract, leg.LastPrice, model, underlying, rate, now)
+	return leg
+}
+
+func optionGreeksCells(leg *optionChainLeg) (string, string) {
+	if leg == nil || leg.Greeks == nil {
+		return "-", "-"
+	}
+	return formatPercent(leg.Greeks.IV), fmt.Sprintf("%.3f", leg.Greeks.Delta)
+}
+
+// optionChainLegCells returns OI, OI change, volume, bid, ask and LTP.
+func optionChainLegCells(leg *optionChainLeg) []string {
+	if leg == nil {
+		return []string{"-", "-", "-", "-", "-", "-"}
+	}
+	change := "-"
+	if leg.OIChange != nil {
+		change = fmt.Sprintf("%+.0f", *leg.OIChange)
+	}
+	return []string{
+		fmt.Sprintf("%.0f", leg.OI),
+		change,
+		intToString(leg.Volume),
+		formatFloat(leg.Bid),
+		formatFloat(leg.Ask),
+		formatFloat(leg.LastPrice),
+	}
+}
+
+// oiSnapshotStore keeps the last OI seen per instrument token for each IST
+// day, since the quote API does not report the previous session's OI.
+type oiSnapshotStore struct {
+	store    *cache.FSStore
+	exchange string
+}
+
+const oiSnapshotLookbackDays = 7
+
+func newOISnapshotStore(exchange string) *oiSnapshotStore {
+	cacheDir, err := paths.DefaultCacheDir()
+	if err != nil {
+		return &oiSnapshotStore{exchange: exchange}
+	}
+	return &oiSnapshotStore{
+		store:    cache.NewFSStore(filepath.Join(cacheDir, "options")),
+		exchange: exchange,
+	}
+}
+
+func (s *oiSnapshotStore) key(day time.Time) string {
+	return "oi:" + s.exchange + ":" + day.In(istLocation).Format("2006-01-02")
+}
+
+func (s *oiSnapshotStore) load(day time.Time) map[string]float64 {
+	if s.store == nil {
+		return nil
+	}
+	data, err := s.store.Get(s.key(day))
+	if err != nil {
+		return nil
+	}
+	var snapshot map[string]float64
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil
+	}
+	return snapshot
+}
+
+// baseline returns the most recent snapshot from a day before now.
+func (s *oiSnapshotStore) baseline(now time.Time) map[string]float64 {
+	for days := 1; days <= oiSnapshotLookbackDays; days++ {
+		if snapshot := s.load(now.AddDate(0, 0, -days)); snapshot != nil {
+			return snapshot
+		}
+	}
+	return nil
+}
+
+func (s *oiSnapshotStore) record(now time.Time, values map[string]float64) {
+	if s.store == nil || len(values) == 0 {
+		return
+	}
+	snapshot := s.load(now)
+	if snapshot == nil {
+		snapshot = make(map[string]float64, len(values))
+	}
+	maps.Copy(snapshot, values)
+	if data, err := json.Marshal(snapshot); err == nil {
+		_ = s.store.Put(s.key(now), data)
+	}
+}
+
+const (
+	defaultRiskFreeRate = 0.065
+
+	underlyingSpot    = "spot"
+	underlyingFutures = "futures"
+)
+
+type greeksFlags struct {
+	underlying     string
+	spotInstrument string
+	riskFreeRate   float64
+}
+
+func bindGreeksFlags(cmd *cobra.Command, flags *greeksFlags) {
+	cmd.Flags().StringVar(&flags.underlying, "underlying", underlyingSpot, "Underlying price source (spot, futures)")
+	cmd.Flags().StringVar(&flags.spotInstrument, "spot-instrument", "", "EXCHANGE:SYMBOL used as spot price (defaults per underlying)")
+	cmd.Flags().Float64Var(&flags.riskFreeRate, "risk-free-rate", defaultRiskFreeRate, "Annual risk-free rate as a decimal (defaults to the configured rate, else 0.065)")
+}
+
+func validateGreeksFlags(flags greeksFlags) error {
+	switch strings.ToLower(strings.TrimSpace(flags.underlying)) {
+	case underlyingSpot, underlyingFutures:
+	default:
+		return exitcode.New(exitcode.Validation, "invalid --underlying; use spot or futures")
+	}
+	if flags.riskFreeRate < 0 || flags.riskFreeRate >= 1 {
+		return exitcode.New(exitcode.Validation, "--risk-free-rate must be a decimal between 0 and 1, e.g. 0.065")
+	}
+	return nil
+}
+
+// model prices options off a futures LTP with Black-76, since the futures
+// price already includes the cost of carry.
+func (f greeksFlags) model() pricing.Model {
+	if strings.EqualFold(strings.TrimSpace(f.underlying), underlyingFutures) {
+		return pricing.Black76
+	}
+	return pricing.BlackScholes
+}
+
+// riskFreeRate prefers an explicit --risk-free-rate, then the config value.
+func (c *commandContext) riskFreeRate(cmd *cobra.Command, flags greeksFlags) float64 {
+	if cmd.Flags().Changed("risk-free-rate") {
+		return flags.riskFreeRate
+	}
+	if c.cfg.RiskFreeRate != nil {
+		return *c.cfg.RiskFreeRate
+	}
+	return defaultRiskFreeRate
+}
+
+// resolveUnderlyingPrices returns the underlying LTP for each contract's
+// underlying name, taken from the spot instrument or the nearest futures
+// contract found in index.
+func resolveUnderlyingPrices(
+	ctx *commandContext,
+	profileName string,
+	profile *config.Profile,
+	flags greeksFlags,
+	contracts []kiteconnect.Instrument,
+	index *instrumentIndex,
+	now time.Time,
+) (map[string]float64, error) {
+	keys := make(map[string]string)
+	for _, contract := range contracts {
+		name := strings.ToUpper(contract.Name)
+		if _, ok := keys[name]; ok {
+			continue
+		}
+		switch {
+		case strings.EqualFold(flags.underlying, underlyingFutures):
+			future, ok := nearestFuture(index, contract.Exchange, name, now)
+			if !ok {
+				return nil, exitcode.New(exitcode.Validation, fmt.Sprintf("no unexpired %s futures contract found for %s", contract.Exchange, name))
+			}
+			keys[name] = instrumentKey(future)
+		case strings.TrimSpace(flags.spotInstrument) != "":
+			keys[name] = strings.TrimSpace(flags.spotInstrument)
+		default:
+			keys[name] = defaultSpotInstrument(name)
+		}
+	}
+	if len(keys) == 0 {
+		return map[string]float64{}, nil
+	}
+
+	requested := make([]string, 0, len(keys))
+	for _, name := range sortedKeys(keys) {
+		if !slices.Contains(requested, keys[name]) {
+			requested = append(requested, keys[name])
+		}
+	}
+	ltp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.QuoteLTP, error) {
+		return client.GetLTP(requested...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]float64, len(keys))
+	for name, key := range keys {
+		quote, ok := ltp[key]
+		if !ok {
+			return nil, exitcode.New(exitcode.API, fmt.Sprintf("no LTP returned for underlying instrument %q; pass --spot-instrument", key))
+		}
+		prices[name] = quote.LastPrice
+	}
+	return prices, nil
+}
+
+func nearestFuture(index *instrumentIndex, exchange, name string, now time.Time) (kiteconnect.Instrument, bool) {
+	today := now.In(istLocation).Format("2006-01-02")
+	var (
+		best  kiteconnect.Instrument
+		found bool
+	)
+	for _, instrument := range index.byToken {
+		if instrument.InstrumentType != "FUT" || instrument.Exchange != exchange || !strings.EqualFold(instrument.Name, name) {
+			continue
+		}
+		expiry := optionExpiryDate(instrument)
+		if expiry < today {
+			continue
+		}
+		if !found || expiry < optionExpiryDate(best) {
+			best, found = instrument, true
+		}
+	}
+	return best, found
+}
+
+// expiryClose is the 15:30 IST market close on the contract's expiry date.
+func expiryClose(contract kiteconnect.Instrument) time.Time {
+	expiry := contract.Expiry.Time
+	return time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 15, 30, 0, 0, istLocation)
+}
+
+func yearsToExpiry(contract kiteconnect.Instrument, now time.Time) float64 {
+	years := expiryClose(contract).Sub(now).Hours() / 24 / 365
+	return max(years, 0)
+}
+
+type optionAnalytics struct {
+	Model           pricing.Model `json:"model"`
+	UnderlyingPrice float64       `json:"underlying_price"`
+	DaysToExpiry    float64       `json:"days_to_expiry"`
+	RiskFreeRate    float64       `json:"risk_free_rate"`
+	IV              float64       `json:"iv"`
+	pricing.Greeks
+}
+
+func analyzeOption(contract kiteconnect.Instrument, price float64, model pricing.Model, underlying, rate float64, now time.Time) (*optionAnalytics, error) {
+	kind, err := pricing.ParseOptionType(contract.InstrumentType)
+	if err != nil {
+		return nil, err
+	}
+	years := yearsToExpiry(contract, now)
+	iv, err := model.ImpliedVolatility(kind, price, underlying, contract.StrikePrice, years, rate)
+	if err != nil {
+		return nil, err
+	}
+	return &optionAnalytics{
+		Model:           model,
+		UnderlyingPrice: underlying,
+		DaysToExpiry:    years * 365,
+		RiskFreeRate:    rate,
+		IV:              iv,
+		Greeks:          model.Greeks(kind, underlying, contract.StrikePrice, years, rate, iv),
+	}, nil
+}
+
+func formatPercent(v float64) string {
+	return fmt.Sprintf("%.2f%%", v*100)
+}
+
+func newOptionsGreeksCmd(opts *rootOptions) *cobra.Command {
+	var flags greeksFlags
+	greeksCmd := &cobra.Command{
+		Use:   "greeks <EXCHANGE:SYMBOL>",
+		Short: "Compute implied volatility and Greeks for an option contract",
+		Long: strings.Join([]string{
+			"Uses the option LTP, the underlying spot LTP, time to the 15:30 IST expiry close and the risk-free rate with Black-Scholes.",
+			"With --underlying futures the nearest futures LTP is used with Black-76, which discounts the payoff but adds no drift.",
+			"Theta is per calendar day and vega per 1% change in volatility.",
+		}, " "),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateGreeksFlags(flags); err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			profileName, profile, err := ctx.resolveProfile(true)
+			if err != nil {
+				return err
+			}
+			if err := ensureAccessToken(profile); err != nil {
+				return err
+			}
+
+			resolved, index, err := resolveInstruments(ctx, profileName, profile, args)
+			if err != nil {
+				return err
+			}
+			contract := resolved[0]
+			if contract.InstrumentType != "CE" && contract.InstrumentType != "PE" {
+				return exitcode.New(exitcode.Validation, fmt.Sprintf("%s is not an option contract", args[0]))
+			}
+
+			now := time.Now()
+			underlying, err := resolveUnderlyingPrices(ctx, profileName, profile, flags, resolved, index, now)
+			if err != nil {
+				return err
+			}
+			key := instrumentKey(contract)
+			ltp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.QuoteLTP, error) {
+				return client.GetLTP(key)
+			})
+			if err != nil {
+				return err
+			}
+			price, ok := ltp[key]
+			if !ok {
+				return exitcode.New(exitcode.API, fmt.Sprintf("no LTP returned for %s", key))
+			}
+
+			rate := ctx.riskFreeRate(cmd, flags)
+			analytics, err := analyzeOption(contract, price.LastPrice, flags.model(), underlying[strings.ToUpper(contract.Name)], rate, now)
+			if err != nil {
+				return exitcode.Wrap(exitcode.Validation, fmt.Sprintf("compute greeks for %s", key), err)
+			}
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(map[string]any{
+					"instrument":      key,
+					"instrument_type": contract.InstrumentType,
+					"strike":          contract.StrikePrice,
+					"expiry":          optionExpiryDate(contract),
+					"last_price":      price.LastPrice,
+					"greeks":          analytics,
+				})
+			}
+			return printer.KV([][2]string{
+				{"instrument", key},
+				{"type", contract.InstrumentType},
+				{"strike", formatFloat(contract.StrikePrice)},
+				{"expiry", optionExpiryDate(contract)},
+				{"model", string(analytics.Model)},
+				{"days_to_expiry", formatFloat(analytics.DaysToExpiry)},
+				{"ltp", formatFloat(price.LastPrice)},
+				{"underlying_price", formatFloat(analytics.UnderlyingPrice)},
+				{"risk_free_rate", formatPercent(analytics.RiskFreeRate)},
+				{"iv", formatPercent(analytics.IV)},
+				{"delta", fmt.Sprintf("%.4f", analytics.Delta)},
+				{"gamma", fmt.Sprintf("%.6f", analytics.Gamma)},
+				{"theta", fmt.Sprintf("%.4f", analytics.Theta)},
+				{"vega", fmt.Sprintf("%.4f", analytics.Vega)},
+			})
+		},
+	}
+	bindGreeksFlags(greeksCmd, &flags)
+	return greeksCmd
+}
@@ -0,0 +1,469 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/output"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/pricing"
+	"github.com/spf13/cobra"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+// strategyLegSpec places a leg offset widths away from ATM; lots is the
+// per-lot multiplier and is negative for sells.
+type strategyLegSpec struct {
+	kind   string
+	offset int
+	lots   int
+}
+
+var optionStrategies = map[string][]strategyLegSpec{
+	"straddle":         {{"CE", 0, 1}, {"PE", 0, 1}},
+	"strangle":         {{"CE", 1, 1}, {"PE", -1, 1}},
+	"bull-call-spread": {{"CE", 0, 1}, {"CE", 1, -1}},
+	"bear-call-spread": {{"CE", 0, -1}, {"CE", 1, 1}},
+	"bull-put-spread":  {{"PE", 0, -1}, {"PE", -1, 1}},
+	"bear-put-spread":  {{"PE", 0, 1}, {"PE", -1, -1}},
+	"iron-condor":      {{"CE", 1, -1}, {"CE", 2, 1}, {"PE", -1, -1}, {"PE", -2, 1}},
+	"iron-butterfly":   {{"CE", 0, -1}, {"PE", 0, -1}, {"CE", 1, 1}, {"PE", -1, 1}},
+}
+
+type strategyLeg struct {
+	Tradingsymbol   string  `json:"tradingsymbol"`
+	Exchange        string  `json:"exchange"`
+	Type            string  `json:"type"`
+	Strike          float64 `json:"strike"`
+	TransactionType string  `json:"transaction_type"`
+	Quantity        int     `json:"quantity"`
+	LastPrice       float64 `json:"last_price"`
+	OrderID         string  `json:"order_id,omitempty"`
+
+	contract kiteconnect.Instrument
+}
+
+func (l strategyLeg) signedQuantity() int {
+	if l.TransactionType == kiteconnect.TransactionTypeSell {
+		return -l.Quantity
+	}
+	return l.Quantity
+}
+
+func newOptionsStrategyCmd(opts *rootOptions) *cobra.Command {
+	var (
+		strategyUnderlying string
+		strategyExpiry     string
+		strategyExchange   string
+		strategyLots       int
+		strategyWidth      float64
+		strategyShort      bool
+		strategyProduct    string
+		strategyOrderType  string
+		strategyTag        string
+		strategyYes        bool
+		strategyConsider   bool
+		strategyFlags      greeksFlags
+	)
+	strategyCmd := &cobra.Command{
+		Use:   "strategy <" + strings.Join(sortedKeys(optionStrategies), "|") + ">",
+		Short: "Build a multi-leg option strategy, show margin and payoff, and place it on confirmation",
+		Long: strings.Join([]string{
+			"Resolves the strategy legs to concrete contracts around the ATM strike (from the underlying LTP)",
+			"using the instrument master, then shows the combined basket margin and payoff at expiry.",
+			"Orders are placed only after answering the confirmation prompt, or with --yes.",
+			"Straddle and strangle are long by default; --short flips every leg.",
+			"--width is the strike distance between legs and defaults to the strike interval near ATM.",
+		}, " "),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.ToLower(strings.TrimSpace(args[0]))
+			specs, ok := optionStrategies[name]
+			if !ok {
+				return exitcode.New(exitcode.Validation, fmt.Sprintf("unknown strategy %q; use one of %s", args[0], strings.Join(sortedKeys(optionStrategies), ", ")))
+			}
+			underlying := normalizeUpper(strategyUnderlying)
+			if underlying == "" {
+				return exitcode.New(exitcode.Validation, "--underlying is required")
+			}
+			if strategyLots <= 0 {
+				return exitcode.New(exitcode.Validation, "--lots must be > 0")
+			}
+			if strategyWidth < 0 {
+				return exitcode.New(exitcode.Validation, "--width cannot be negative")
+			}
+			product := normalizeUpper(strategyProduct)
+			if product != kiteconnect.ProductNRML && product != kiteconnect.ProductMIS {
+				return exitcode.New(exitcode.Validation, "invalid --product; use NRML or MIS")
+			}
+			orderType := normalizeUpper(strategyOrderType)
+			if orderType != kiteconnect.OrderTypeMarket && orderType != kiteconnect.OrderTypeLimit {
+				return exitcode.New(exitcode.Validation, "invalid --order-type; use MARKET or LIMIT")
+			}
+			if err := validateGreeksFlags(strategyFlags); err != nil {
+				return err
+			}
+			exchange := normalizeUpper(strategyExchange)
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			profileName, profile, err := ctx.resolveProfile(true)
+			if err != nil {
+				return err
+			}
+			if err := ensureAccessToken(profile); err != nil {
+				return err
+			}
+
+			instruments, err := loadExchangeInstruments(ctx, profileName, profile, exchange)
+			if err != nil {
+				return err
+			}
+			contracts := optionContracts(instruments, underlying)
+			if len(contracts) == 0 {
+				return exitcode.New(exitcode.Validation, fmt.Sprintf("no %s option contracts found for %s", exchange, underlying))
+			}
+			now := time.Now()
+			expiry, err := selectOptionExpiry(contracts, strategyExpiry, now)
+			if err != nil {
+				return err
+			}
+			strikes := optionStrikes(contracts, expiry)
+
+			prices, err := resolveUnderlyingPrices(ctx, profileName, profile, strategyFlags, contracts[:1], newInstrumentIndex(instruments), now)
+			if err != nil {
+				return err
+			}
+			spot := prices[underlying]
+
+			legs, err := resolveStrategyLegs(specs, strikes, nearestStrikeIndex(strikes, spot), strategyWidth, strategyLots, strategyShort)
+			if err != nil {
+				return err
+			}
+
+			keys := make([]string, 0, len(legs))
+			for _, leg := range legs {
+				keys = append(keys, leg.Exchange+":"+leg.Tradingsymbol)
+			}
+			ltp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.QuoteLTP, error) {
+				return client.GetLTP(keys...)
+			})
+			if err != nil {
+				return err
+			}
+			for i := range legs {
+				legs[i].LastPrice = ltp[keys[i]].LastPrice
+				if orderType == kiteconnect.OrderTypeLimit && legs[i].LastPrice <= 0 {
+					return exitcode.New(exitcode.API, fmt.Sprintf("no LTP for %s; cannot price a LIMIT order", keys[i]))
+				}
+			}
+
+			marginParams := make([]kiteconnect.OrderMarginParam, 0, len(legs))
+			for _, leg := range legs {
+				marginParams = append(marginParams, kiteconnect.OrderMarginParam{
+					Exchange:        leg.Exchange,
+					Tradingsymbol:   leg.Tradingsymbol,
+					TransactionType: leg.TransactionType,
+					Variety:         kiteconnect.VarietyRegular,
+					Product:         product,
+					OrderType:       orderType,
+					Quantity:        float64(leg.Quantity),
+					Price:           strategyLegPrice(leg, orderType),
+				})
+			}
+			margins, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.BasketMargins, error) {
+				return client.GetBasketMargins(kiteconnect.GetBasketParams{
+					OrderParams:       marginParams,
+					ConsiderPositions: strategyConsider,
+				})
+			})
+			if err != nil {
+				return err
+			}
+
+			summary, netPremium := strategyRisk(legs, expiry, prices, strategyFlags.model(), ctx.riskFreeRate(cmd, strategyFlags), now)
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			out := cmd.OutOrStdout()
+			if !printer.IsJSON() {
+				if err := printStrategyPlan(printer, name, underlying, expiry, spot, netPremium, legs, margins, summary); err != nil {
+					return err
+				}
+			}
+
+			place := strategyYes
+			if !place && !printer.IsJSON() {
+				place, err = confirmPrompt(cmd.InOrStdin(), out, fmt.Sprintf("Place %d orders for %s %s? [y/N]: ", len(legs), underlying, name))
+				if err != nil {
+					return err
+				}
+			}
+
+			var placeErr error
+			if place {
+				placeErr = placeStrategyLegs(ctx, profileName, profile, legs, product, orderType, strings.TrimSpace(strategyTag))
+			}
+
+			if printer.IsJSON() {
+				if err := printer.JSON(map[string]any{
+					"strategy":    name,
+					"underlying":  underlying,
+					"expiry":      expiry,
+					"spot":        spot,
+					"net_premium": netPremium,
+					"legs":        legs,
+					"margins":     margins,
+					"payoff":      summary,
+					"placed":      place && placeErr == nil,
+				}); err != nil {
+					return err
+				}
+			} else if place {
+				rows := make([][]string, 0, len(legs))
+				for _, leg := range legs {
+					orderID := leg.OrderID
+					if orderID == "" {
+						orderID = "-"
+					}
+					rows = append(rows, []string{leg.Tradingsymbol, leg.TransactionType, intToString(leg.Quantity), orderID})
+				}
+				if err := printer.Table([]string{"SYMBOL", "TXN", "QTY", "ORDER_ID"}, rows); err != nil {
+					return err
+				}
+			} else {
+				if _, err := fmt.Fprintln(out, "Not placed."); err != nil {
+					return err
+				}
+			}
+			return placeErr
+		},
+	}
+	strategyCmd.Flags().StringVar(&strategyUnderlying, "underlying", "", "Underlying name, e.g. NIFTY or BANKNIFTY")
+	strategyCmd.Flags().StringVar(&strategyExpiry, "expiry", optionExpiryNearest, "Expiry date (YYYY-MM-DD) or nearest")
+	strategyCmd.Flags().StringVar(&strategyExchange, "exchange", "NFO", "Derivatives exchange (NFO, BFO)")
+	strategyCmd.Flags().IntVar(&strategyLots, "lots", 1, "Lots per leg")
+	strategyCmd.Flags().Float64Var(&strategyWidth, "width", 0, "Strike distance between legs (0 = strike interval near ATM)")
+	strategyCmd.Flags().BoolVar(&strategyShort, "short", false, "Flip every leg (e.g. short straddle)")
+	strategyCmd.Flags().StringVar(&strategyProduct, "product", kiteconnect.ProductNRML, "Order product (NRML, MIS)")
+	strategyCmd.Flags().StringVar(&strategyOrderType, "order-type", kiteconnect.OrderTypeMarket, "Order type (MARKET, or LIMIT at each leg's LTP)")
+	strategyCmd.Flags().StringVar(&strategyTag, "tag", "", "Optional order tag")
+	strategyCmd.Flags().BoolVar(&strategyYes, "yes", false, "Place the orders without prompting")
+	strategyCmd.Flags().BoolVar(&strategyConsider, "consider-positions", false, "Factor current positions in basket margin")
+	strategyCmd.Flags().StringVar(&strategyFlags.spotInstrument, "spot-instrument", "", "EXCHANGE:SYMBOL used as spot price (defaults per underlying)")
+	strategyCmd.Flags().Float64Var(&strategyFlags.riskFreeRate, "risk-free-rate", defaultRiskFreeRate, "Annual risk-free rate as a decimal (defaults to the configured rate, else 0.065)")
+	strategyFlags.underlying = underlyingSpot
+	return strategyCmd
+}
+
+// strategyRisk values the legs as if filled at their LTPs and returns the
+// payoff summary shown before placing, along with the net premium received.
+func strategyRisk(legs []strategyLeg, expiry string, prices map[string]float64, model pricing.Model, rate float64, now time.Time) (riskReport, float64) {
+	riskLegs := make([]riskLeg, 0, len(legs))
+	netPremium := 0.0
+	for _, leg := range legs {
+		riskLegs = append(riskLegs, riskLeg{
+			Tradingsymbol: leg.Tradingsymbol,
+			Exchange:      leg.Exchange,
+			Type:          leg.Type,
+			Strike:        leg.Strike,
+			Expiry:        expiry,
+			Quantity:      leg.signedQuantity(),
+			AveragePrice:  leg.LastPrice,
+			LastPrice:     leg.LastPrice,
+			contract:      leg.contract,
+		})
+		netPremium -= float64(leg.signedQuantity()) * leg.LastPrice
+	}
+	return buildRiskReports(riskLegs, prices, model, rate, now, defaultRiskRangePercent, defaultRiskSteps, nil)[0], netPremium
+}
+
+// resolveStrategyLegs maps leg specs onto listed strikes around atm.
+func resolveStrategyLegs(specs []strategyLegSpec, strikes []optionStrike, atm int, width float64, lots int, short bool) ([]strategyLeg, error) {
+	if len(strikes) == 0 {
+		return nil, exitcode.New(exitcode.Validation, "no strikes available")
+	}
+	if width == 0 {
+		width = strikeInterval(strikes, atm)
+	}
+	// Without an interval every leg would land on ATM.
+	if width <= 0 && slices.ContainsFunc(specs, func(spec strategyLegSpec) bool { return spec.offset != 0 }) {
+		return nil, exitcode.New(exitcode.Validation, "cannot infer the strike interval from a single listed strike; pass --width")
+	}
+	center := strikes[atm].strike
+
+	legs := make([]strategyLeg, 0, len(specs))
+	for _, spec := range specs {
+		target := center + float64(spec.offset)*width
+		idx := slices.IndexFunc(strikes, func(s optionStrike) bool { return math.Abs(s.strike-target) < 1e-6 })
+		var contract *kiteconnect.Instrument
+		if idx >= 0 {
+			if spec.kind == "CE" {
+				contract = strikes[idx].call
+			} else {
+				contract = strikes[idx].put
+			}
+		}
+		if contract == nil {
+			return nil, exitcode.New(exitcode.Validation, fmt.Sprintf("no %s contract at strike %s; adjust --width", spec.kind, formatFloat(target)))
+		}
+
+		qty := spec.lots * lots
+		if short {
+			qty = -qty
+		}
+		txn := kiteconnect.TransactionTypeBuy
+		if qty < 0 {
+			txn = kiteconnect.TransactionTypeSell
+			qty = -qty
+		}
+		legs = append(legs, strategyLeg{
+			Tradingsymbol:   contract.Tradingsymbol,
+			Exchange:        contract.Exchange,
+			Type:            contract.InstrumentType,
+			Strike:          contract.StrikePrice,
+			TransactionType: txn,
+			Quantity:        qty * int(contract.LotSize),
+			contract:        *contract,
+		})
+	}
+
+	// Buy legs go first so hedges are in place before the short legs,
+	// which keeps the margin requirement down while the basket fills.
+	slices.SortStableFunc(legs, func(a, b strategyLeg) int {
+		if a.TransactionType == b.TransactionType {
+			return 0
+		}
+		if a.TransactionType == kiteconnect.TransactionTypeBuy {
+			return -1
+		}
+		return 1
+	})
+	return legs, nil
+}
+
+func strikeInterval(strikes []optionStrike, atm int) float64 {
+	if atm+1 < len(strikes) {
+		return strikes[atm+1].strike - strikes[atm].strike
+	}
+	if atm > 0 {
+		return strikes[atm].strike - strikes[atm-1].strike
+	}
+	return 0
+}
+
+func strategyLegPrice(leg strategyLeg, orderType string) float64 {
+	if orderType == kiteconnect.OrderTypeLimit {
+		return leg.LastPrice
+	}
+	return 0
+}
+
+func placeStrategyLegs(
+	ctx *commandContext,
+	profileName string,
+	profile *config.Profile,
+	legs []strategyLeg,
+	product string,
+	orderType string,
+	tag string,
+) error {
+	for i := range legs {
+		leg := &legs[i]
+		resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.OrderResponse, error) {
+			return client.PlaceOrder(kiteconnect.VarietyRegular, kiteconnect.OrderParams{
+				Exchange:        leg.Exchange,
+				Tradingsymbol:   leg.Tradingsymbol,
+				TransactionType: leg.TransactionType,
+				OrderType:       orderType,
+				Product:         product,
+				Quantity:        leg.Quantity,
+				Price:           strategyLegPrice(*leg, orderType),
+				Validity:        kiteconnect.ValidityDay,
+				Tag:             tag,
+			})
+		})
+		if err != nil {
+			return exitcode.Wrap(exitcode.API, fmt.Sprintf("place leg %d/%d (%s %s); earlier legs were placed", i+1, len(legs), leg.TransactionType, leg.Tradingsymbol), err)
+		}
+		leg.OrderID = resp.OrderID
+	}
+	return nil
+}
+
+func printStrategyPlan(
+	printer output.Printer,
+	name string,
+	underlying string,
+	expiry string,
+	spot float64,
+	netPremium float64,
+	legs []strategyLeg,
+	margins kiteconnect.BasketMargins,
+	summary riskReport,
+) error {
+	breakevens := make([]string, 0, len(summary.Breakevens))
+	for _, price := range summary.Breakevens {
+		breakevens = append(breakevens, formatFloat(price))
+	}
+	if len(breakevens) == 0 {
+		breakevens = append(breakevens, "-")
+	}
+
+	if err := printer.KV([][2]string{
+		{"strategy", name},
+		{"underlying", underlying},
+		{"expiry", expiry},
+		{"spot", formatFloat(spot)},
+		{"net_premium", formatFloat(netPremium)},
+		{"margin_required", formatFloat(margins.Final.Total)},
+		{"charges", formatFloat(margins.Final.Charges.Total)},
+		{"breakevens", strings.Join(breakevens, ", ")},
+		{"max_profit", formatRiskBound(summary.MaxProfit)},
+		{"max_loss", formatRiskBound(summary.MaxLoss)},
+		{"net_delta", fmt.Sprintf("%.2f", summary.NetDelta)},
+		{"net_theta", fmt.Sprintf("%.2f", summary.NetTheta)},
+	}); err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(legs))
+	for _, leg := range legs {
+		rows = append(rows, []string{
+			leg.Tradingsymbol,
+			leg.Type,
+			formatFloat(leg.Strike),
+			leg.TransactionType,
+			intToString(leg.Quantity),
+			formatFloat(leg.LastPrice),
+		})
+	}
+	return printer.Table([]string{"SYMBOL", "TYPE", "STRIKE", "TXN", "QTY", "LTP"}, rows)
+}
+
+// confirmPrompt asks a yes/no question; anything but y/yes is a no.
+func confirmPrompt(in io.Reader, out io.Writer, question string) (bool, error) {
+	if _, err := fmt.Fprint(out, question); err != nil {
+		return false, err
+	}
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
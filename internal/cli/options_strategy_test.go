@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/pricing"
+)
+
+func testStrikeLadder(t *testing.T, low, high, step float64) []optionStrike {
+	t.Helper()
+
+	expiry := time.Date(2026, 10, 27, 0, 0, 0, 0, istLocation)
+	var contracts kiteconnect.Instruments
+	token := 1
+	for strike := low; strike <= high; strike += step {
+		for _, kind := range []string{"CE", "PE"} {
+			symbol := "NIFTY26OCT" + formatFloat(strike) + kind
+			contracts = append(contracts, testOptionContract(token, symbol, "NIFTY", kind, strike, expiry))
+			token++
+		}
+	}
+	return optionStrikes(contracts, "2026-10-27")
+}
+
+func TestResolveStrategyLegsIronCondor(t *testing.T) {
+	strikes := testStrikeLadder(t, 24500, 25500, 50)
+	atm := nearestStrikeIndex(strikes, 25020)
+
+	legs, err := resolveStrategyLegs(optionStrategies["iron-condor"], strikes, atm, 200, 2, false)
+	if err != nil {
+		t.Fatalf("resolve legs: %v", err)
+	}
+
+	type want struct {
+		strike float64
+		kind   string
+		txn    string
+	}
+	expected := []want{
+		{25400, "CE", kiteconnect.TransactionTypeBuy},
+		{24600, "PE", kiteconnect.TransactionTypeBuy},
+		{25200, "CE", kiteconnect.TransactionTypeSell},
+		{24800, "PE", kiteconnect.TransactionTypeSell},
+	}
+	if len(legs) != len(expected) {
+		t.Fatalf("expected %d legs, got %d", len(expected), len(legs))
+	}
+	for i, leg := range legs {
+		if leg.Strike != expected[i].strike || leg.Type != expected[i].kind || leg.TransactionType != expected[i].txn {
+			t.Fatalf("leg %d: expected %+v, got %+v", i, expected[i], leg)
+		}
+		if leg.Quantity != 150 {
+			t.Fatalf("expected 2 lots of 75, got %d", leg.Quantity)
+		}
+	}
+}
+
+func TestResolveStrategyLegsDefaultsWidthAndShort(t *testing.T) {
+	strikes := testStrikeLadder(t, 24800, 25200, 100)
+	atm := nearestStrikeIndex(strikes, 24990)
+
+	legs, err := resolveStrategyLegs(optionStrategies["strangle"], strikes, atm, 0, 1, true)
+	if err != nil {
+		t.Fatalf("resolve legs: %v", err)
+	}
+	if legs[0].Strike != 25100 || legs[1].Strike != 24900 {
+		t.Fatalf("expected strangle at 25100/24900, got %v/%v", legs[0].Strike, legs[1].Strike)
+	}
+	for _, leg := range legs {
+		if leg.TransactionType != kiteconnect.TransactionTypeSell || leg.signedQuantity() != -75 {
+			t.Fatalf("expected short legs, got %+v", leg)
+		}
+	}
+
+	if _, err := resolveStrategyLegs(optionStrategies["iron-condor"], strikes, atm, 200, 1, false); err == nil {
+		t.Fatalf("expected error when wing strikes are not listed")
+	}
+}
+
+func TestResolveStrategyLegsNeedsWidthForSingleStrike(t *testing.T) {
+	strikes := testStrikeLadder(t, 25000, 25000, 100)
+
+	for _, name := range []string{"strangle", "iron-condor"} {
+		if _, err := resolveStrategyLegs(optionStrategies[name], strikes, 0, 0, 1, false); exitcode.Code(err) != exitcode.Validation {
+			t.Fatalf("expected %s without a strike interval to be rejected, got %v", name, err)
+		}
+	}
+	if legs, err := resolveStrategyLegs(optionStrategies["straddle"], strikes, 0, 0, 1, false); err != nil || len(legs) != 2 {
+		t.Fatalf("expected a straddle to need no interval, got %+v, %v", legs, err)
+	}
+}
+
+func TestStrategyRiskIncludesWingsOutsideRange(t *testing.T) {
+	strikes := testStrikeLadder(t, 20000, 30000, 500)
+	atm := nearestStrikeIndex(strikes, 25000)
+
+	// Wings 3000 points (12%) out sit beyond the +/-10% payoff grid.
+	legs, err := resolveStrategyLegs(optionStrategies["iron-condor"], strikes, atm, 1500, 1, false)
+	if err != nil {
+		t.Fatalf("resolve legs: %v", err)
+	}
+	ltps := map[float64]float64{26500: 60, 28000: 15, 23500: 70, 22000: 20}
+	for i := range legs {
+		legs[i].LastPrice = ltps[legs[i].Strike]
+	}
+
+	now := time.Date(2026, 10, 20, 15, 30, 0, 0, istLocation)
+	summary, netPremium := strategyRisk(legs, "2026-10-27", map[string]float64{"NIFTY": 25000}, pricing.BlackScholes, 0.065, now)
+	if math.Abs(netPremium-75*95) > 1e-6 {
+		t.Fatalf("expected a net credit of %v, got %v", 75*95, netPremium)
+	}
+	if summary.MaxProfit == nil || math.Abs(*summary.MaxProfit-75*95) > 1e-6 {
+		t.Fatalf("expected max profit of the credit, got %v", summary.MaxProfit)
+	}
+	if summary.MaxLoss == nil || math.Abs(*summary.MaxLoss+75*(1500-95)) > 1e-6 {
+		t.Fatalf("expected max loss at the wings, got %v", summary.MaxLoss)
+	}
+}
+
+func TestConfirmPrompt(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{input: "y\n", want: true},
+		{input: " YES \n", want: true},
+		{input: "n\n", want: false},
+		{input: "\n", want: false},
+		{input: "", want: false},
+	}
+
+	for _, tc := range tests {
+		var out bytes.Buffer
+		got, err := confirmPrompt(strings.NewReader(tc.input), &out, "Place? [y/N]: ")
+		if err != nil {
+			t.Fatalf("confirm %q: %v", tc.input, err)
+		}
+		if got != tc.want {
+			t.Fatalf("expected %t for %q, got %t", tc.want, tc.input, got)
+		}
+		if out.String() != "Place? [y/N]: " {
+			t.Fatalf("expected prompt to be written, got %q", out.String())
+		}
+	}
+}
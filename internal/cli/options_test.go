@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/pricing"
+	"github.com/spf13/cobra"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+	"github.com/zerodha/gokiteconnect/v4/models"
+)
+
+func testOptionContract(token int, symbol, name, kind string, strike float64, expiry time.Time) kiteconnect.Instrument {
+	return kiteconnect.Instrument{
+		InstrumentToken: token,
+		Tradingsymbol:   symbol,
+		Name:            name,
+		Exchange:        "NFO",
+		InstrumentType:  kind,
+		StrikePrice:     strike,
+		LotSize:         75,
+		Expiry:          models.Time{Time: expiry},
+	}
+}
+
+func TestSelectOptionExpiry(t *testing.T) {
+	near := time.Date(2026, 10, 20, 0, 0, 0, 0, istLocation)
+	far := time.Date(2026, 10, 27, 0, 0, 0, 0, istLocation)
+	contracts := kiteconnect.Instruments{
+		testOptionContract(1, "NIFTY26O2025000CE", "NIFTY", "CE", 25000, far),
+		testOptionContract(2, "NIFTY26O2025000PE", "NIFTY", "PE", 25000, near),
+	}
+
+	tests := []struct {
+		name    string
+		raw     string
+		now     time.Time
+		want    string
+		wantErr bool
+	}{
+		{name: "nearest", raw: "nearest", now: time.Date(2026, 10, 18, 12, 0, 0, 0, istLocation), want: "2026-10-20"},
+		{name: "nearest on expiry day", raw: "", now: time.Date(2026, 10, 20, 15, 0, 0, 0, istLocation), want: "2026-10-20"},
+		{name: "nearest skips expired", raw: "nearest", now: time.Date(2026, 10, 21, 9, 0, 0, 0, istLocation), want: "2026-10-27"},
+		{name: "explicit date", raw: "2026-10-27", now: time.Date(2026, 10, 18, 12, 0, 0, 0, istLocation), want: "2026-10-27"},
+		{name: "unknown date", raw: "2026-10-22", now: time.Date(2026, 10, 18, 12, 0, 0, 0, istLocation), wantErr: true},
+		{name: "bad format", raw: "27-10-2026", now: time.Date(2026, 10, 18, 12, 0, 0, 0, istLocation), wantErr: true},
+		{name: "all expired", raw: "nearest", now: time.Date(2026, 11, 1, 12, 0, 0, 0, istLocation), wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := selectOptionExpiry(contracts, tc.raw, tc.now)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestOptionStrikesPairsContractsAroundATM(t *testing.T) {
+	expiry := time.Date(2026, 10, 27, 0, 0, 0, 0, istLocation)
+	other := time.Date(2026, 11, 24, 0, 0, 0, 0, istLocation)
+	instruments := kiteconnect.Instruments{
+		testOptionContract(1, "NIFTY25100CE", "NIFTY", "CE", 25100, expiry),
+		testOptionContract(2, "NIFTY25000PE", "NIFTY", "PE", 25000, expiry),
+		testOptionContract(3, "NIFTY25000CE", "NIFTY", "CE", 25000, expiry),
+		testOptionContract(4, "NIFTY24900PE", "NIFTY", "PE", 24900, expiry),
+		testOptionContract(5, "NIFTYNOV25000CE", "NIFTY", "CE", 25000, other),
+		testOptionContract(6, "BANKNIFTY25000CE", "BANKNIFTY", "CE", 25000, expiry),
+		{InstrumentToken: 7, Tradingsymbol: "NIFTY26OCTFUT", Name: "NIFTY", InstrumentType: "FUT", Expiry: models.Time{Time: expiry}},
+	}
+
+	contracts := optionContracts(instruments, "nifty")
+	if len(contracts) != 5 {
+		t.Fatalf("expected 5 NIFTY option contracts, got %d", len(contracts))
+	}
+
+	strikes := optionStrikes(contracts, "2026-10-27")
+	if len(strikes) != 3 {
+		t.Fatalf("expected 3 strikes, got %d", len(strikes))
+	}
+	if strikes[0].strike != 24900 || strikes[0].call != nil || strikes[0].put == nil {
+		t.Fatalf("unexpected lowest strike %+v", strikes[0])
+	}
+	if strikes[1].call == nil || strikes[1].put == nil || strikes[1].call.InstrumentToken != 3 {
+		t.Fatalf("expected both legs at 25000, got %+v", strikes[1])
+	}
+
+	if got := nearestStrikeIndex(strikes, 25040); got != 1 {
+		t.Fatalf("expected ATM index 1, got %d", got)
+	}
+	if got := nearestStrikeIndex(strikes, 25500); got != 2 {
+		t.Fatalf("expected ATM index 2, got %d", got)
+	}
+}
+
+func TestOptionChainLegComputesOIChangeFromBaseline(t *testing.T) {
+	contract := testOptionContract(101, "NIFTY25000CE", "NIFTY", "CE", 25000, time.Date(2026, 10, 27, 0, 0, 0, 0, istLocation))
+	quotes := kiteconnect.Quote{}
+	q := quotes["NFO:NIFTY25000CE"]
+	q.LastPrice = 120.5
+	q.OI = 1500
+	q.Volume = 900
+	q.Depth.Buy[0].Price = 120
+	q.Depth.Sell[0].Price = 121
+	quotes["NFO:NIFTY25000CE"] = q
+
+	current := map[string]float64{}
+	leg := optionChainLegFromQuote(&contract, quotes, map[string]float64{"101": 1200}, current)
+	if leg.OIChange == nil || *leg.OIChange != 300 {
+		t.Fatalf("expected OI change 300, got %v", leg.OIChange)
+	}
+	if leg.Bid != 120 || leg.Ask != 121 || leg.Volume != 900 {
+		t.Fatalf("unexpected leg %+v", leg)
+	}
+	if current["101"] != 1500 {
+		t.Fatalf("expected current OI to be recorded, got %v", current)
+	}
+
+	leg = optionChainLegFromQuote(&contract, quotes, nil, current)
+	if leg.OIChange != nil {
+		t.Fatalf("expected no OI change without baseline, got %v", *leg.OIChange)
+	}
+	if cells := optionChainLegCells(leg); cells[1] != "-" {
+		t.Fatalf("expected blank OI change cell, got %q", cells[1])
+	}
+}
+
+func TestOISnapshotStoreBaselineUsesPreviousDay(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("HOME", cacheHome)
+
+	store := newOISnapshotStore("NFO")
+	friday := time.Date(2026, 10, 16, 15, 30, 0, 0, istLocation)
+	monday := time.Date(2026, 10, 19, 10, 0, 0, 0, istLocation)
+
+	store.record(friday, map[string]float64{"101": 1200})
+	store.record(monday, map[string]float64{"101": 1500})
+
+	baseline := store.baseline(monday)
+	if baseline["101"] != 1200 {
+		t.Fatalf("expected friday baseline 1200, got %v", baseline)
+	}
+	if got := store.baseline(friday); got != nil {
+		t.Fatalf("expected no baseline before first snapshot, got %v", got)
+	}
+}
+
+func TestAnalyzeOptionRecoversVolatility(t *testing.T) {
+	now := time.Date(2026, 10, 20, 15, 30, 0, 0, istLocation)
+	contract := testOptionContract(101, "NIFTY25000CE", "NIFTY", "CE", 25000, time.Date(2026, 10, 27, 0, 0, 0, 0, istLocation))
+
+	years := yearsToExpiry(contract, now)
+	if math.Abs(years-7.0/365) > 1e-9 {
+		t.Fatalf("expected 7 days to expiry, got %f years", years)
+	}
+
+	price := pricing.Price(pricing.Call, 24900, 25000, years, 0.065, 0.13)
+	analytics, err := analyzeOption(contract, price, pricing.BlackScholes, 24900, 0.065, now)
+	if err != nil {
+		t.Fatalf("analyze option: %v", err)
+	}
+	if math.Abs(analytics.IV-0.13) > 1e-5 {
+		t.Fatalf("expected IV 0.13, got %f", analytics.IV)
+	}
+	if analytics.Delta <= 0 || analytics.Delta >= 0.5 {
+		t.Fatalf("expected OTM call delta between 0 and 0.5, got %f", analytics.Delta)
+	}
+
+	if _, err := analyzeOption(contract, price, pricing.BlackScholes, 24900, 0.065, now.AddDate(0, 0, 8)); err == nil {
+		t.Fatalf("expected error for an expired contract")
+	}
+
+	// Off a futures price the same premium must not be grown by the rate again.
+	future := 24900 * math.Exp(0.065*years)
+	forward, err := analyzeOption(contract, price, pricing.Black76, future, 0.065, now)
+	if err != nil {
+		t.Fatalf("analyze option off futures: %v", err)
+	}
+	if forward.Model != pricing.Black76 || math.Abs(forward.IV-0.13) > 1e-5 {
+		t.Fatalf("expected Black-76 to recover IV 0.13 from the futures price, got %+v", forward)
+	}
+	if spotIV, _ := pricing.ImpliedVolatility(pricing.Call, price, future, 25000, years, 0.065); spotIV >= 0.13 {
+		t.Fatalf("expected treating futures as spot to understate IV, got %f", spotIV)
+	}
+}
+
+func TestNearestFutureSkipsExpiredContracts(t *testing.T) {
+	index := newInstrumentIndex(kiteconnect.Instruments{
+		{InstrumentToken: 1, Tradingsymbol: "NIFTY26SEPFUT", Name: "NIFTY", Exchange: "NFO", InstrumentType: "FUT", Expiry: models.Time{Time: time.Date(2026, 9, 29, 0, 0, 0, 0, istLocation)}},
+		{InstrumentToken: 2, Tradingsymbol: "NIFTY26NOVFUT", Name: "NIFTY", Exchange: "NFO", InstrumentType: "FUT", Expiry: models.Time{Time: time.Date(2026, 11, 24, 0, 0, 0, 0, istLocation)}},
+		{InstrumentToken: 3, Tradingsymbol: "NIFTY26OCTFUT", Name: "NIFTY", Exchange: "NFO", InstrumentType: "FUT", Expiry: models.Time{Time: time.Date(2026, 10, 27, 0, 0, 0, 0, istLocation)}},
+		{InstrumentToken: 4, Tradingsymbol: "BANKNIFTY26OCTFUT", Name: "BANKNIFTY", Exchange: "NFO", InstrumentType: "FUT", Expiry: models.Time{Time: time.Date(2026, 10, 20, 0, 0, 0, 0, istLocation)}},
+	})
+
+	future, ok := nearestFuture(index, "NFO", "NIFTY", time.Date(2026, 10, 18, 10, 0, 0, 0, istLocation))
+	if !ok || future.Tradingsymbol != "NIFTY26OCTFUT" {
+		t.Fatalf("expected NIFTY26OCTFUT, got %+v (found=%t)", future, ok)
+	}
+	if _, ok := nearestFuture(index, "BFO", "NIFTY", time.Date(2026, 10, 18, 10, 0, 0, 0, istLocation)); ok {
+		t.Fatalf("expected no BFO future")
+	}
+}
+
+func TestRiskFreeRatePrecedence(t *testing.T) {
+	configPath := writeTestConfigWithToken(t)
+	if _, _, err := executeCLICommand(t, configPath, "config", "set-risk-free-rate", "0.07"); err != nil {
+		t.Fatalf("set risk-free rate: %v", err)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "config", "set-risk-free-rate", "6.5"); err == nil {
+		t.Fatalf("expected percentage input to be rejected")
+	}
+
+	ctx, err := newCommandContext(&rootOptions{configPath: configPath})
+	if err != nil {
+		t.Fatalf("new command context: %v", err)
+	}
+	cmd := &cobra.Command{Use: "greeks"}
+	var flags greeksFlags
+	bindGreeksFlags(cmd, &flags)
+
+	if got := ctx.riskFreeRate(cmd, flags); got != 0.07 {
+		t.Fatalf("expected configured rate 0.07, got %f", got)
+	}
+	if err := cmd.Flags().Set("risk-free-rate", "0.05"); err != nil {
+		t.Fatalf("set flag: %v", err)
+	}
+	if got := ctx.riskFreeRate(cmd, flags); got != 0.05 {
+		t.Fatalf("expected flag rate 0.05, got %f", got)
+	}
+
+	ctx.cfg.RiskFreeRate = nil
+	if got := ctx.riskFreeRate(newOptionsGreeksCmd(&rootOptions{}), flags); got != defaultRiskFreeRate {
+		t.Fatalf("expected default rate, got %f", got)
+	}
+}
@@ -52,7 +52,7 @@ func newOrderCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.OrderResponse, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.OrderResponse, error) {
 				return client.PlaceOrder(variety, params)
 			})
 			if err != nil {
@@ -103,7 +103,7 @@ func newOrderCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.OrderResponse, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.OrderResponse, error) {
 				return client.ModifyOrder(variety, orderID, params)
 			})
 			if err != nil {
@@ -156,7 +156,7 @@ func newOrderCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.OrderResponse, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.OrderResponse, error) {
 				return client.CancelOrder(variety, orderID, parentID)
 			})
 			if err != nil {
@@ -211,7 +211,7 @@ func newOrderCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.OrderResponse, error) {
+			resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.OrderResponse, error) {
 				return client.ExitOrder(variety, orderID, parentID)
 			})
 			if err != nil {
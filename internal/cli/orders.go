@@ -3,7 +3,9 @@ package cli
 import (
 	"strings"
 
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
 	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/output"
 	"github.com/spf13/cobra"
 	kiteconnect "github.com/zerodha/gokiteconnect/v4"
 )
@@ -15,7 +17,7 @@ func newOrdersCmd(opts *rootOptions) *cobra.Command {
 	}
 
 	var listLimit int
-	listCmd := &cobra.Command{
+	listCmd := allowFanOut(&cobra.Command{
 		Use:   "list",
 		Short: "List orders",
 		RunE: func(cmd *cobra.Command, _ []string) error {
@@ -27,6 +29,15 @@ func newOrdersCmd(opts *rootOptions) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if opts.fanOut() {
+				results, err := fanOutProfiles(ctx, func(ctx *commandContext, name string, profile *config.Profile) (kiteconnect.Orders, error) {
+					return fetchOrders(ctx, name, profile, listLimit)
+				})
+				if err != nil {
+					return err
+				}
+				return printFanOut(ctx, cmd, results, orderHeaders, orderRows)
+			}
 			profileName, profile, err := ctx.resolveProfile(true)
 			if err != nil {
 				return err
@@ -35,49 +46,20 @@ func newOrdersCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			orders, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.Orders, error) {
-				return client.GetOrders()
-			})
+			orders, err := fetchOrders(ctx, profileName, profile, listLimit)
 			if err != nil {
 				return err
 			}
-			orders = applyLimit(orders, listLimit)
 
 			printer := ctx.printer(cmd.OutOrStdout())
 			if printer.IsJSON() {
 				return printer.JSON(orders)
 			}
 
-			rows := make([][]string, 0, len(orders))
-			for _, order := range orders {
-				rows = append(rows, []string{
-					order.OrderID,
-					order.TradingSymbol,
-					order.Exchange,
-					order.TransactionType,
-					formatFloat(order.Quantity),
-					formatFloat(order.Price),
-					order.Status,
-					order.OrderType,
-					order.Product,
-					order.OrderTimestamp.Time.Format("2006-01-02 15:04:05"),
-				})
-			}
-
-			return printer.Table([]string{
-				"ORDER_ID",
-				"SYMBOL",
-				"EXCHANGE",
-				"TXN",
-				"QTY",
-				"PRICE",
-				"STATUS",
-				"TYPE",
-				"PRODUCT",
-				"TIMESTAMP",
-			}, rows)
+			rows := orderRows(orders)
+			return printer.Table(orderHeaders, rows)
 		},
-	}
+	})
 	listCmd.Flags().IntVar(&listLimit, "limit", 0, "Limit number of rows (0 = no limit)")
 
 	var showOrderID string
@@ -105,7 +87,7 @@ func newOrdersCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			history, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) ([]kiteconnect.Order, error) {
+			history, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) ([]kiteconnect.Order, error) {
 				return client.GetOrderHistory(showOrderID)
 			})
 			if err != nil {
@@ -160,7 +142,7 @@ func newOrdersCmd(opts *rootOptions) *cobra.Command {
 			orderID := strings.TrimSpace(tradesOrderID)
 			var trades []kiteconnect.Trade
 			if orderID == "" {
-				result, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.Trades, error) {
+				result, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Trades, error) {
 					return client.GetTrades()
 				})
 				if err != nil {
@@ -168,7 +150,7 @@ func newOrdersCmd(opts *rootOptions) *cobra.Command {
 				}
 				trades = []kiteconnect.Trade(result)
 			} else {
-				result, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) ([]kiteconnect.Trade, error) {
+				result, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) ([]kiteconnect.Trade, error) {
 					return client.GetOrderTrades(orderID)
 				})
 				if err != nil {
@@ -205,6 +187,188 @@ func newOrdersCmd(opts *rootOptions) *cobra.Command {
 	tradesCmd.Flags().StringVar(&tradesOrderID, "order-id", "", "Filter trades for a specific order ID")
 	tradesCmd.Flags().IntVar(&tradesLimit, "limit", 0, "Limit number of rows (0 = no limit)")
 
-	ordersCmd.AddCommand(listCmd, showCmd, tradesCmd)
+	ordersCmd.AddCommand(listCmd, showCmd, tradesCmd, newOrdersWatchCmd(opts))
 	return ordersCmd
 }
+
+var orderHeaders = []string{"ORDER_ID", "SYMBOL", "EXCHANGE", "TXN", "QTY", "PRICE", "STATUS", "TYPE", "PRODUCT", "TIMESTAMP"}
+
+func fetchOrders(ctx *commandContext, profileName string, profile *config.Profile, limit int) (kiteconnect.Orders, error) {
+	orders, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Orders, error) {
+		return client.GetOrders()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applyLimit(orders, limit), nil
+}
+
+func orderRows(orders kiteconnect.Orders) [][]string {
+	rows := make([][]string, 0, len(orders))
+	for _, order := range orders {
+		rows = append(rows, []string{
+			order.OrderID,
+			order.TradingSymbol,
+			order.Exchange,
+			order.TransactionType,
+			formatFloat(order.Quantity),
+			formatFloat(order.Price),
+			order.Status,
+			order.OrderType,
+			order.Product,
+			order.OrderTimestamp.Time.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return rows
+}
+
+func newOrdersWatchCmd(opts *rootOptions) *cobra.Command {
+	var (
+		watchTags    []string
+		watchSymbols []string
+		watchLimit   int
+		watchTicker  tickerFlags
+	)
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Stream live order updates over the Kite websocket",
+		Long: strings.Join([]string{
+			"Streams order postbacks from the Kite websocket ticker until interrupted.",
+			"Each update is printed as a table row, or as one JSON object per line with --json.",
+			"Dropped connections are retried with exponential backoff.",
+		}, " "),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := validateLimit(watchLimit); err != nil {
+				return err
+			}
+			if err := validateTickerFlags(watchTicker); err != nil {
+				return err
+			}
+			filter := newOrderUpdateFilter(watchTags, watchSymbols)
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			_, profile, err := ctx.resolveProfile(true)
+			if err != nil {
+				return err
+			}
+			if err := ensureAccessToken(profile); err != nil {
+				return err
+			}
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			var table *output.StreamTable
+			if !printer.IsJSON() {
+				table, err = printer.StreamTable([]string{"TIME", "ORDER_ID", "SYMBOL", "EXCHANGE", "TXN", "STATUS", "QTY", "FILLED_QTY", "AVG_PRICE", "TAG"})
+				if err != nil {
+					return err
+				}
+			}
+
+			streamCtx, stop := streamContext(cmd)
+			defer stop()
+
+			session := newTickerSession(*profile, watchTicker, cmd.ErrOrStderr())
+			seen := 0
+			var writeErr error
+			session.ticker.OnOrderUpdate(func(order kiteconnect.Order) {
+				if !filter.match(order) {
+					return
+				}
+				session.do(func() {
+					if printer.IsJSON() {
+						writeErr = printer.NDJSON(order)
+					} else {
+						writeErr = table.Row(orderUpdateRow(order))
+					}
+					seen++
+					if writeErr != nil || (watchLimit > 0 && seen >= watchLimit) {
+						stop()
+					}
+				})
+			})
+
+			if err := session.run(streamCtx, nil); err != nil {
+				return err
+			}
+			return writeErr
+		},
+	}
+	watchCmd.Flags().StringSliceVar(&watchTags, "tag", nil, "Only show orders with this tag (repeatable)")
+	watchCmd.Flags().StringSliceVar(&watchSymbols, "symbol", nil, "Only show orders for this SYMBOL or EXCHANGE:SYMBOL (repeatable)")
+	watchCmd.Flags().IntVar(&watchLimit, "limit", 0, "Exit after this many matching updates (0 = run until interrupted)")
+	bindTickerFlags(watchCmd, &watchTicker)
+
+	return watchCmd
+}
+
+type orderUpdateFilter struct {
+	tags    map[string]struct{}
+	symbols map[string]struct{}
+}
+
+func newOrderUpdateFilter(tags, symbols []string) orderUpdateFilter {
+	filter := orderUpdateFilter{
+		tags:    make(map[string]struct{}),
+		symbols: make(map[string]struct{}),
+	}
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filter.tags[tag] = struct{}{}
+		}
+	}
+	for _, symbol := range symbols {
+		if symbol = normalizeUpper(symbol); symbol != "" {
+			filter.symbols[symbol] = struct{}{}
+		}
+	}
+	return filter
+}
+
+func (f orderUpdateFilter) match(order kiteconnect.Order) bool {
+	if len(f.symbols) > 0 {
+		symbol := normalizeUpper(order.TradingSymbol)
+		_, bare := f.symbols[symbol]
+		_, qualified := f.symbols[normalizeUpper(order.Exchange)+":"+symbol]
+		if !bare && !qualified {
+			return false
+		}
+	}
+	if len(f.tags) > 0 {
+		if _, ok := f.tags[order.Tag]; ok {
+			return true
+		}
+		for _, tag := range order.Tags {
+			if _, ok := f.tags[tag]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func orderUpdateRow(order kiteconnect.Order) []string {
+	ts := order.ExchangeUpdateTimestamp.Time
+	if ts.IsZero() {
+		ts = order.OrderTimestamp.Time
+	}
+	tag := order.Tag
+	if tag == "" {
+		tag = "-"
+	}
+	return []string{
+		formatModelTime(ts),
+		order.OrderID,
+		order.TradingSymbol,
+		order.Exchange,
+		order.TransactionType,
+		order.Status,
+		formatFloat(order.Quantity),
+		formatFloat(order.FilledQuantity),
+		formatFloat(order.AveragePrice),
+		tag,
+	}
+}
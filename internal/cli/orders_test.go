@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+func writeTestConfigWithToken(t *testing.T) string {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := config.Default()
+	cfg.ActiveProfile = "default"
+	cfg.Profiles["default"] = config.Profile{
+		APIKey:      "test_key",
+		APISecret:   "test_secret",
+		AccessToken: "test_access_token",
+	}
+	saveTestConfig(t, configPath, cfg)
+	return configPath
+}
+
+func TestOrderUpdateFilterMatchesTagsAndSymbols(t *testing.T) {
+	order := kiteconnect.Order{
+		Exchange:      "NSE",
+		TradingSymbol: "INFY",
+		Tag:           "bot",
+		Tags:          []string{"bot", "swing"},
+	}
+
+	tests := []struct {
+		name    string
+		tags    []string
+		symbols []string
+		want    bool
+	}{
+		{name: "no filters", want: true},
+		{name: "bare symbol", symbols: []string{"infy"}, want: true},
+		{name: "qualified symbol", symbols: []string{"NSE:INFY"}, want: true},
+		{name: "other exchange", symbols: []string{"BSE:INFY"}, want: false},
+		{name: "primary tag", tags: []string{"bot"}, want: true},
+		{name: "secondary tag", tags: []string{"swing"}, want: true},
+		{name: "unknown tag", tags: []string{"manual"}, want: false},
+		{name: "symbol and tag", tags: []string{"swing"}, symbols: []string{"TCS"}, want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := newOrderUpdateFilter(tc.tags, tc.symbols).match(order); got != tc.want {
+				t.Fatalf("expected match=%v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestOrdersWatchStreamsFilteredUpdatesAsNDJSON(t *testing.T) {
+	tickerURL := newFakeTickerServer(t, func(conn *websocket.Conn) {
+		for _, msg := range []string{
+			`{"type":"order","data":{"order_id":"1","tradingsymbol":"TCS","exchange":"NSE","status":"OPEN","tag":"manual"}}`,
+			`{"type":"order","data":{"order_id":"2","tradingsymbol":"INFY","exchange":"NSE","status":"COMPLETE","tag":"bot"}}`,
+		} {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return
+			}
+		}
+		_, _, _ = conn.ReadMessage()
+	})
+
+	stdout, _, err := executeCLICommand(
+		t,
+		writeTestConfigWithToken(t),
+		"--json",
+		"orders",
+		"watch",
+		"--tag",
+		"bot",
+		"--limit",
+		"1",
+		"--ticker-url",
+		tickerURL,
+	)
+	if err != nil {
+		t.Fatalf("orders watch failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 NDJSON line, got %d: %q", len(lines), stdout)
+	}
+	var order struct {
+		OrderID string `json:"order_id"`
+		Status  string `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &order); err != nil {
+		t.Fatalf("decode update: %v", err)
+	}
+	if order.OrderID != "2" || order.Status != "COMPLETE" {
+		t.Fatalf("expected filtered update for order 2, got %+v", order)
+	}
+}
+
+func TestOrdersWatchGivesUpWhenTickerUnreachable(t *testing.T) {
+	_, _, err := executeCLICommand(
+		t,
+		writeTestConfigWithToken(t),
+		"orders",
+		"watch",
+		"--max-retries",
+		"0",
+		"--ticker-url",
+		"ws://127.0.0.1:1/",
+	)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "ticker connection failed") {
+		t.Fatalf("expected ticker connection error, got %q", err.Error())
+	}
+}
@@ -0,0 +1,991 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/cache"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/paths"
+	"github.com/spf13/cobra"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+	"github.com/zerodha/gokiteconnect/v4/models"
+)
+
+const (
+	defaultPaperCapital = 1000000
+
+	paperStatusOpen      = "OPEN"
+	paperStatusTrigger   = "TRIGGER PENDING"
+	paperStatusComplete  = "COMPLETE"
+	paperStatusCancelled = "CANCELLED"
+	paperStatusRejected  = "REJECTED"
+)
+
+var errPaperUnsupported = exitcode.New(exitcode.Validation, "not supported in --paper mode")
+
+// paperState is the simulated account for one profile. Timestamps are kept as
+// RFC3339 strings since models.Time does not round-trip through JSON.
+type paperState struct {
+	Capital  float64      `json:"capital"`
+	Cash     float64      `json:"cash"`
+	NextID   int          `json:"next_id"`
+	Orders   []paperOrder `json:"orders"`
+	Trades   []paperTrade `json:"trades"`
+	Clock    string       `json:"clock,omitempty"`
+	Interval string       `json:"interval,omitempty"`
+}
+
+type paperOrder struct {
+	OrderID         string             `json:"order_id"`
+	Variety         string             `json:"variety"`
+	Exchange        string             `json:"exchange"`
+	Tradingsymbol   string             `json:"tradingsymbol"`
+	TransactionType string             `json:"transaction_type"`
+	OrderType       string             `json:"order_type"`
+	Product         string             `json:"product"`
+	Validity        string             `json:"validity"`
+	Quantity        int                `json:"quantity"`
+	Price           float64            `json:"price"`
+	TriggerPrice    float64            `json:"trigger_price"`
+	AveragePrice    float64            `json:"average_price"`
+	FilledQuantity  int                `json:"filled_quantity"`
+	Status          string             `json:"status"`
+	StatusMessage   string             `json:"status_message,omitempty"`
+	Tag             string             `json:"tag,omitempty"`
+	PlacedAt        string             `json:"placed_at"`
+	Since           string             `json:"since"`
+	History         []paperOrderChange `json:"history"`
+}
+
+type paperOrderChange struct {
+	Status string `json:"status"`
+	At     string `json:"at"`
+}
+
+type paperTrade struct {
+	TradeID         string  `json:"trade_id"`
+	OrderID         string  `json:"order_id"`
+	Exchange        string  `json:"exchange"`
+	Tradingsymbol   string  `json:"tradingsymbol"`
+	TransactionType string  `json:"transaction_type"`
+	Product         string  `json:"product"`
+	Quantity        int     `json:"quantity"`
+	Price           float64 `json:"price"`
+	FilledAt        string  `json:"filled_at"`
+}
+
+// paperPrice is what orders fill against: the live touch, or in replay mode
+// the bars up to the clock.
+type paperPrice struct {
+	last, bid, ask, close float64
+	bars                  []kiteconnect.HistoricalData
+}
+
+func newPaperState(capital float64) paperState {
+	return paperState{Capital: capital, Cash: capital, NextID: 1}
+}
+
+func paperStore() (*cache.FSStore, error) {
+	cacheDir, err := paths.DefaultCacheDir()
+	if err != nil {
+		return nil, exitcode.Wrap(exitcode.Internal, "paper account needs a cache directory", err)
+	}
+	return cache.NewFSStore(filepath.Join(cacheDir, "paper")), nil
+}
+
+func paperStateKey(profileName string) string {
+	return "paper:" + profileName
+}
+
+func loadPaperState(profileName string) (paperState, error) {
+	store, err := paperStore()
+	if err != nil {
+		return paperState{}, err
+	}
+	data, err := store.Get(paperStateKey(profileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return newPaperState(defaultPaperCapital), nil
+	}
+	if err != nil {
+		return paperState{}, exitcode.Wrap(exitcode.Internal, "read paper account", err)
+	}
+	var state paperState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, exitcode.Wrap(exitcode.Internal, "decode paper account", err)
+	}
+	return state, nil
+}
+
+func savePaperState(profileName string, state paperState) error {
+	store, err := paperStore()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return exitcode.Wrap(exitcode.Internal, "encode paper account", err)
+	}
+	if err := store.Put(paperStateKey(profileName), data); err != nil {
+		return exitcode.Wrap(exitcode.Internal, "save paper account", err)
+	}
+	return nil
+}
+
+func (s paperState) clock() (time.Time, bool) {
+	if s.Clock == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, s.Clock)
+	return t, err == nil
+}
+
+// paperBroker implements kiteAPI on top of the live client: market data and
+// instruments pass through, while orders, trades, positions and holdings come
+// from the simulated account.
+type paperBroker struct {
+	kiteAPI
+	profileName string
+	state       paperState
+	candles     *candleStore
+	now         func() time.Time
+	prices      func(keys []string) (map[string]paperPrice, error)
+}
+
+func newPaperBroker(profileName string, client kiteAPI) (*paperBroker, error) {
+	state, err := loadPaperState(profileName)
+	if err != nil {
+		return nil, err
+	}
+	b := &paperBroker{kiteAPI: client, profileName: profileName, state: state, candles: newCandleStore()}
+	b.now = func() time.Time {
+		if clock, ok := b.state.clock(); ok {
+			return clock
+		}
+		return time.Now()
+	}
+	b.prices = b.fetchPrices
+	return b, nil
+}
+
+func (b *paperBroker) fetchPrices(keys []string) (map[string]paperPrice, error) {
+	prices := make(map[string]paperPrice, len(keys))
+	if len(keys) == 0 {
+		return prices, nil
+	}
+	if clock, ok := b.state.clock(); ok {
+		for _, key := range keys {
+			token, found := b.candles.lookupToken(key)
+			if !found {
+				return nil, exitcode.New(exitcode.Validation, fmt.Sprintf("no stored candles for %s; fetch them with `zerodha quote historical --instrument %s`", key, key))
+			}
+			// A year of bars covers any order still pending since placement.
+			bars := b.candles.load(token, b.state.Interval, false, clock.AddDate(-1, 0, 0), clock)
+			if len(bars) == 0 {
+				return nil, exitcode.New(exitcode.Validation, fmt.Sprintf("no stored %s candles for %s at or before %s", b.state.Interval, key, clock.In(istLocation).Format(time.RFC3339)))
+			}
+			bars = replayBars(bars, clock, historicalIntervals[b.state.Interval])
+			last := bars[len(bars)-1].Close
+			prices[key] = paperPrice{last: last, bid: last, ask: last, bars: bars}
+		}
+		return prices, nil
+	}
+
+	quotes, err := b.kiteAPI.GetQuote(keys...)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		quote, ok := quotes[key]
+		if !ok {
+			return nil, exitcode.New(exitcode.Validation, fmt.Sprintf("no quote for %s", key))
+		}
+		prices[key] = paperPrice{
+			last:  quote.LastPrice,
+			bid:   quote.Depth.Buy[0].Price,
+			ask:   quote.Depth.Sell[0].Price,
+			close: quote.OHLC.Close,
+		}
+	}
+	return prices, nil
+}
+
+// replayBars hides what is not yet known at the clock: a bar that started
+// but has not closed only shows its open, as its high, low and close.
+func replayBars(bars []kiteconnect.HistoricalData, clock time.Time, width time.Duration) []kiteconnect.HistoricalData {
+	known := slices.Clone(bars)
+	for i := range known {
+		bar := &known[i]
+		if bar.Date.Time.Add(width).After(clock) {
+			bar.High, bar.Low, bar.Close, bar.Volume = bar.Open, bar.Open, bar.Open, 0
+		}
+	}
+	return known
+}
+
+func (b *paperBroker) stamp() string {
+	return b.now().UTC().Format(time.RFC3339)
+}
+
+func (b *paperBroker) save() error {
+	return savePaperState(b.profileName, b.state)
+}
+
+func (b *paperBroker) findOrder(orderID string) (*paperOrder, error) {
+	for i := range b.state.Orders {
+		if b.state.Orders[i].OrderID == orderID {
+			return &b.state.Orders[i], nil
+		}
+	}
+	return nil, exitcode.New(exitcode.Validation, fmt.Sprintf("paper order %q not found", orderID))
+}
+
+func (o *paperOrder) setStatus(status, message, at string) {
+	o.Status = status
+	o.StatusMessage = message
+	o.History = append(o.History, paperOrderChange{Status: status, At: at})
+}
+
+func (o *paperOrder) pending() bool {
+	return o.Status == paperStatusOpen || o.Status == paperStatusTrigger
+}
+
+func (o *paperOrder) key() string {
+	return o.Exchange + ":" + o.Tradingsymbol
+}
+
+func (b *paperBroker) PlaceOrder(variety string, params kiteconnect.OrderParams) (kiteconnect.OrderResponse, error) {
+	if params.Quantity <= 0 {
+		return kiteconnect.OrderResponse{}, exitcode.New(exitcode.Validation, "paper orders need a positive quantity")
+	}
+	order := paperOrder{
+		OrderID:         "PAPER-" + strconv.Itoa(b.state.NextID),
+		Variety:         variety,
+		Exchange:        params.Exchange,
+		Tradingsymbol:   params.Tradingsymbol,
+		TransactionType: params.TransactionType,
+		OrderType:       params.OrderType,
+		Product:         params.Product,
+		Validity:        params.Validity,
+		Quantity:        params.Quantity,
+		Price:           params.Price,
+		TriggerPrice:    params.TriggerPrice,
+		Tag:             params.Tag,
+		PlacedAt:        b.stamp(),
+	}
+	order.Since = order.PlacedAt
+	b.state.NextID++
+
+	status := paperStatusOpen
+	if order.OrderType == kiteconnect.OrderTypeSL || order.OrderType == kiteconnect.OrderTypeSLM {
+		status = paperStatusTrigger
+	}
+	order.setStatus(status, "", order.PlacedAt)
+	if order.Product == kiteconnect.ProductCNC && order.TransactionType == kiteconnect.TransactionTypeSell && order.Quantity > b.sellableCNC(order.Exchange, order.Tradingsymbol) {
+		order.setStatus(paperStatusRejected, "insufficient holdings for CNC sell", order.PlacedAt)
+	}
+	b.state.Orders = append(b.state.Orders, order)
+
+	if err := b.match(); err != nil {
+		return kiteconnect.OrderResponse{}, err
+	}
+	if err := b.save(); err != nil {
+		return kiteconnect.OrderResponse{}, err
+	}
+	return kiteconnect.OrderResponse{OrderID: order.OrderID}, nil
+}
+
+func (b *paperBroker) ModifyOrder(_ string, orderID string, params kiteconnect.OrderParams) (kiteconnect.OrderResponse, error) {
+	order, err := b.findOrder(orderID)
+	if err != nil {
+		return kiteconnect.OrderResponse{}, err
+	}
+	if !order.pending() {
+		return kiteconnect.OrderResponse{}, exitcode.New(exitcode.Validation, fmt.Sprintf("paper order %s is %s and cannot be modified", orderID, order.Status))
+	}
+	if params.Quantity > 0 {
+		order.Quantity = params.Quantity
+	}
+	if params.Price > 0 {
+		order.Price = params.Price
+	}
+	if params.TriggerPrice > 0 {
+		order.TriggerPrice = params.TriggerPrice
+	}
+	if params.OrderType != "" {
+		order.OrderType = params.OrderType
+	}
+	if params.Validity != "" {
+		order.Validity = params.Validity
+	}
+	// Bars that passed before the modification must not fill the new terms.
+	order.Since = b.stamp()
+	order.History = append(order.History, paperOrderChange{Status: order.Status, At: order.Since})
+
+	if err := b.match(); err != nil {
+		return kiteconnect.OrderResponse{}, err
+	}
+	if err := b.save(); err != nil {
+		return kiteconnect.OrderResponse{}, err
+	}
+	return kiteconnect.OrderResponse{OrderID: orderID}, nil
+}
+
+func (b *paperBroker) CancelOrder(_ string, orderID string, _ *string) (kiteconnect.OrderResponse, error) {
+	order, err := b.findOrder(orderID)
+	if err != nil {
+		return kiteconnect.OrderResponse{}, err
+	}
+	if !order.pending() {
+		return kiteconnect.OrderResponse{}, exitcode.New(exitcode.Validation, fmt.Sprintf("paper order %s is %s and cannot be cancelled", orderID, order.Status))
+	}
+	order.setStatus(paperStatusCancelled, "", b.stamp())
+	if err := b.save(); err != nil {
+		return kiteconnect.OrderResponse{}, err
+	}
+	return kiteconnect.OrderResponse{OrderID: orderID}, nil
+}
+
+func (b *paperBroker) ExitOrder(variety string, orderID string, parentOrderID *string) (kiteconnect.OrderResponse, error) {
+	return b.CancelOrder(variety, orderID, parentOrderID)
+}
+
+func (b *paperBroker) GetOrders() (kiteconnect.Orders, error) {
+	if err := b.refresh(); err != nil {
+		return nil, err
+	}
+	orders := make(kiteconnect.Orders, 0, len(b.state.Orders))
+	for _, order := range b.state.Orders {
+		orders = append(orders, order.kiteOrder(order.Status, order.History[len(order.History)-1].At))
+	}
+	return orders, nil
+}
+
+func (b *paperBroker) GetOrderHistory(orderID string) ([]kiteconnect.Order, error) {
+	if err := b.refresh(); err != nil {
+		return nil, err
+	}
+	order, err := b.findOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]kiteconnect.Order, 0, len(order.History))
+	for _, change := range order.History {
+		history = append(history, order.kiteOrder(change.Status, change.At))
+	}
+	return history, nil
+}
+
+func (b *paperBroker) GetTrades() (kiteconnect.Trades, error) {
+	if err := b.refresh(); err != nil {
+		return nil, err
+	}
+	trades := make(kiteconnect.Trades, 0, len(b.state.Trades))
+	for _, trade := range b.state.Trades {
+		trades = append(trades, trade.kiteTrade())
+	}
+	return trades, nil
+}
+
+func (b *paperBroker) GetOrderTrades(orderID string) ([]kiteconnect.Trade, error) {
+	trades, err := b.GetTrades()
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(trades, func(t kiteconnect.Trade) bool { return t.OrderID != orderID }), nil
+}
+
+func (b *paperBroker) GetPositions() (kiteconnect.Positions, error) {
+	if err := b.refresh(); err != nil {
+		return kiteconnect.Positions{}, err
+	}
+	today := b.now().In(istLocation).Format("2006-01-02")
+	var net, day []paperTrade
+	for _, trade := range b.state.Trades {
+		isToday := tradeDay(trade) == today
+		if trade.Product != kiteconnect.ProductCNC || isToday {
+			net = append(net, trade)
+		}
+		if isToday {
+			day = append(day, trade)
+		}
+	}
+	prices, err := b.prices(tradeKeys(net))
+	if err != nil {
+		return kiteconnect.Positions{}, err
+	}
+	return kiteconnect.Positions{Net: paperPositions(net, prices), Day: paperPositions(day, prices)}, nil
+}
+
+func (b *paperBroker) GetHoldings() (kiteconnect.Holdings, error) {
+	if err := b.refresh(); err != nil {
+		return nil, err
+	}
+	today := b.now().In(istLocation).Format("2006-01-02")
+	var cnc []paperTrade
+	for _, trade := range b.state.Trades {
+		if trade.Product == kiteconnect.ProductCNC {
+			cnc = append(cnc, trade)
+		}
+	}
+	prices, err := b.prices(tradeKeys(cnc))
+	if err != nil {
+		return nil, err
+	}
+	return paperHoldings(cnc, today, prices), nil
+}
+
+func (b *paperBroker) ConvertPosition(kiteconnect.ConvertPositionParams) (bool, error) {
+	return false, errPaperUnsupported
+}
+
+func (b *paperBroker) PlaceGTT(kiteconnect.GTTParams) (kiteconnect.GTTResponse, error) {
+	return kiteconnect.GTTResponse{}, errPaperUnsupported
+}
+
+func (b *paperBroker) ModifyGTT(int, kiteconnect.GTTParams) (kiteconnect.GTTResponse, error) {
+	return kiteconnect.GTTResponse{}, errPaperUnsupported
+}
+
+func (b *paperBroker) DeleteGTT(int) (kiteconnect.GTTResponse, error) {
+	return kiteconnect.GTTResponse{}, errPaperUnsupported
+}
+
+func (b *paperBroker) PlaceMFOrder(kiteconnect.MFOrderParams) (kiteconnect.MFOrderResponse, error) {
+	return kiteconnect.MFOrderResponse{}, errPaperUnsupported
+}
+
+func (b *paperBroker) CancelMFOrder(string) (kiteconnect.MFOrderResponse, error) {
+	return kiteconnect.MFOrderResponse{}, errPaperUnsupported
+}
+
+func (b *paperBroker) PlaceMFSIP(kiteconnect.MFSIPParams) (kiteconnect.MFSIPResponse, error) {
+	return kiteconnect.MFSIPResponse{}, errPaperUnsupported
+}
+
+func (b *paperBroker) ModifyMFSIP(string, kiteconnect.MFSIPModifyParams) (kiteconnect.MFSIPResponse, error) {
+	return kiteconnect.MFSIPResponse{}, errPaperUnsupported
+}
+
+func (b *paperBroker) CancelMFSIP(string) (kiteconnect.MFSIPResponse, error) {
+	return kiteconnect.MFSIPResponse{}, errPaperUnsupported
+}
+
+// refresh fills any pending orders the market has reached since the last
+// call and persists the result.
+func (b *paperBroker) refresh() error {
+	before := len(b.state.Trades)
+	if err := b.match(); err != nil {
+		return err
+	}
+	if len(b.state.Trades) == before {
+		return nil
+	}
+	return b.save()
+}
+
+func (b *paperBroker) match() error {
+	var keys []string
+	for _, order := range b.state.Orders {
+		if order.pending() && !slices.Contains(keys, order.key()) {
+			keys = append(keys, order.key())
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	prices, err := b.prices(keys)
+	if err != nil {
+		return err
+	}
+	for i := range b.state.Orders {
+		order := &b.state.Orders[i]
+		if !order.pending() {
+			continue
+		}
+		price, ok := paperFillPrice(order, prices[order.key()])
+		if ok {
+			b.fill(order, price)
+		}
+	}
+	return nil
+}
+
+// paperFillPrice reports whether an order would fill now and at what price.
+// Live prices fill at the touch. In replay mode the order is walked through
+// every bar that opened at or after it was placed, so a market order fills at
+// the next open and limits and triggers fill when a bar's range reaches them.
+// The bar in progress at placement is skipped: its range so far is unknown.
+func paperFillPrice(order *paperOrder, price paperPrice) (float64, bool) {
+	buy := order.TransactionType == kiteconnect.TransactionTypeBuy
+	if price.bars == nil {
+		touch := price.ask
+		if !buy {
+			touch = price.bid
+		}
+		if touch <= 0 {
+			touch = price.last
+		}
+		return fillAgainst(order, buy, touch, touch, touch)
+	}
+
+	since, _ := time.Parse(time.RFC3339, order.Since)
+	for _, bar := range price.bars {
+		if bar.Date.Time.Before(since) {
+			continue
+		}
+		if fill, ok := fillAgainst(order, buy, bar.Open, bar.High, bar.Low); ok {
+			return fill, true
+		}
+	}
+	return 0, false
+}
+
+// fillAgainst fills at the open when it already beats the order's price, the
+// way a gap through a limit or trigger would.
+func fillAgainst(order *paperOrder, buy bool, open, high, low float64) (float64, bool) {
+	switch order.OrderType {
+	case kiteconnect.OrderTypeMarket:
+		return open, open > 0
+	case kiteconnect.OrderTypeLimit:
+		if buy && low <= order.Price {
+			return min(open, order.Price), true
+		}
+		if !buy && high >= order.Price {
+			return max(open, order.Price), true
+		}
+	case kiteconnect.OrderTypeSL, kiteconnect.OrderTypeSLM:
+		if buy && high >= order.TriggerPrice {
+			fill := max(open, order.TriggerPrice)
+			return fill, order.OrderType == kiteconnect.OrderTypeSLM || fill <= order.Price
+		}
+		if !buy && low <= order.TriggerPrice {
+			fill := min(open, order.TriggerPrice)
+			return fill, order.OrderType == kiteconnect.OrderTypeSLM || fill >= order.Price
+		}
+	}
+	return 0, false
+}
+
+func (b *paperBroker) fill(order *paperOrder, price float64) {
+	at := b.stamp()
+	value := price * float64(order.Quantity)
+	if order.TransactionType == kiteconnect.TransactionTypeBuy {
+		if value > b.state.Cash {
+			order.setStatus(paperStatusRejected, fmt.Sprintf("insufficient funds: need %s, have %s", formatFloat(value), formatFloat(b.state.Cash)), at)
+			return
+		}
+		b.state.Cash -= value
+	} else {
+		b.state.Cash += value
+	}
+	order.AveragePrice = price
+	order.FilledQuantity = order.Quantity
+	order.setStatus(paperStatusComplete, "", at)
+	b.state.Trades = append(b.state.Trades, paperTrade{
+		TradeID:         order.OrderID + "-T",
+		OrderID:         order.OrderID,
+		Exchange:        order.Exchange,
+		Tradingsymbol:   order.Tradingsymbol,
+		TransactionType: order.TransactionType,
+		Product:         order.Product,
+		Quantity:        order.Quantity,
+		Price:           price,
+		FilledAt:        at,
+	})
+}
+
+func (b *paperBroker) sellableCNC(exchange, symbol string) int {
+	qty := 0
+	for _, trade := range b.state.Trades {
+		if trade.Product != kiteconnect.ProductCNC || trade.Exchange != exchange || trade.Tradingsymbol != symbol {
+			continue
+		}
+		qty += trade.signedQuantity()
+	}
+	return qty
+}
+
+func (t paperTrade) signedQuantity() int {
+	if t.TransactionType == kiteconnect.TransactionTypeSell {
+		return -t.Quantity
+	}
+	return t.Quantity
+}
+
+func (t paperTrade) filledAt() time.Time {
+	at, _ := time.Parse(time.RFC3339, t.FilledAt)
+	return at
+}
+
+func tradeDay(t paperTrade) string {
+	return t.filledAt().In(istLocation).Format("2006-01-02")
+}
+
+func tradeKeys(trades []paperTrade) []string {
+	var keys []string
+	for _, trade := range trades {
+		key := trade.Exchange + ":" + trade.Tradingsymbol
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (o paperOrder) kiteOrder(status, at string) kiteconnect.Order {
+	placed, _ := time.Parse(time.RFC3339, o.PlacedAt)
+	updated, _ := time.Parse(time.RFC3339, at)
+	order := kiteconnect.Order{
+		OrderID:                 o.OrderID,
+		Status:                  status,
+		OrderTimestamp:          models.Time{Time: placed.In(istLocation)},
+		ExchangeUpdateTimestamp: models.Time{Time: updated.In(istLocation)},
+		Variety:                 o.Variety,
+		Exchange:                o.Exchange,
+		TradingSymbol:           o.Tradingsymbol,
+		OrderType:               o.OrderType,
+		TransactionType:         o.TransactionType,
+		Validity:                o.Validity,
+		Product:                 o.Product,
+		Quantity:                float64(o.Quantity),
+		Price:                   o.Price,
+		TriggerPrice:            o.TriggerPrice,
+		Tag:                     o.Tag,
+	}
+	if status == o.Status {
+		order.StatusMessage = o.StatusMessage
+	}
+	switch status {
+	case paperStatusComplete:
+		order.AveragePrice = o.AveragePrice
+		order.FilledQuantity = float64(o.FilledQuantity)
+	case paperStatusCancelled:
+		order.CancelledQuantity = float64(o.Quantity)
+	case paperStatusOpen, paperStatusTrigger:
+		order.PendingQuantity = float64(o.Quantity)
+	}
+	if o.Tag != "" {
+		order.Tags = []string{o.Tag}
+	}
+	return order
+}
+
+func (t paperTrade) kiteTrade() kiteconnect.Trade {
+	at := models.Time{Time: t.filledAt().In(istLocation)}
+	return kiteconnect.Trade{
+		AveragePrice:      t.Price,
+		Quantity:          float64(t.Quantity),
+		TradeID:           t.TradeID,
+		Product:           t.Product,
+		FillTimestamp:     at,
+		ExchangeTimestamp: at,
+		OrderID:           t.OrderID,
+		TransactionType:   t.TransactionType,
+		TradingSymbol:     t.Tradingsymbol,
+		Exchange:          t.Exchange,
+	}
+}
+
+func paperPositions(trades []paperTrade, prices map[string]paperPrice) []kiteconnect.Position {
+	var positions []kiteconnect.Position
+	index := make(map[string]int)
+	for _, trade := range trades {
+		id := trade.Exchange + ":" + trade.Tradingsymbol + ":" + trade.Product
+		i, ok := index[id]
+		if !ok {
+			i = len(positions)
+			index[id] = i
+			positions = append(positions, kiteconnect.Position{
+				Tradingsymbol: trade.Tradingsymbol,
+				Exchange:      trade.Exchange,
+				Product:       trade.Product,
+				Multiplier:    1,
+			})
+		}
+		p := &positions[i]
+		value := trade.Price * float64(trade.Quantity)
+		if trade.TransactionType == kiteconnect.TransactionTypeBuy {
+			p.BuyQuantity += trade.Quantity
+			p.BuyValue += value
+		} else {
+			p.SellQuantity += trade.Quantity
+			p.SellValue += value
+		}
+	}
+	for i := range positions {
+		p := &positions[i]
+		price := prices[p.Exchange+":"+p.Tradingsymbol]
+		p.Quantity = p.BuyQuantity - p.SellQuantity
+		if p.BuyQuantity > 0 {
+			p.BuyPrice = p.BuyValue / float64(p.BuyQuantity)
+		}
+		if p.SellQuantity > 0 {
+			p.SellPrice = p.SellValue / float64(p.SellQuantity)
+		}
+		p.DayBuyQuantity, p.DayBuyPrice, p.DayBuyValue = p.BuyQuantity, p.BuyPrice, p.BuyValue
+		p.DaySellQuantity, p.DaySellPrice, p.DaySellValue = p.SellQuantity, p.SellPrice, p.SellValue
+		switch {
+		case p.Quantity > 0:
+			p.AveragePrice = p.BuyPrice
+		case p.Quantity < 0:
+			p.AveragePrice = p.SellPrice
+		}
+		p.LastPrice = price.last
+		p.ClosePrice = price.close
+		p.Value = p.SellValue - p.BuyValue
+		p.PnL = p.Value + float64(p.Quantity)*price.last
+		p.M2M = p.PnL
+		closed := min(p.BuyQuantity, p.SellQuantity)
+		p.Realised = float64(closed) * (p.SellPrice - p.BuyPrice)
+		p.Unrealised = p.PnL - p.Realised
+	}
+	return positions
+}
+
+// paperHoldings settles CNC trades from earlier days into holdings; today's
+// buys show up as T1 quantity, as they would before settlement.
+func paperHoldings(trades []paperTrade, today string, prices map[string]paperPrice) kiteconnect.Holdings {
+	var holdings kiteconnect.Holdings
+	index := make(map[string]int)
+	cost := make(map[string]float64)
+	bought := make(map[string]int)
+	for _, trade := range trades {
+		key := trade.Exchange + ":" + trade.Tradingsymbol
+		i, ok := index[key]
+		if !ok {
+			i = len(holdings)
+			index[key] = i
+			holdings = append(holdings, kiteconnect.Holding{
+				Tradingsymbol: trade.Tradingsymbol,
+				Exchange:      trade.Exchange,
+				Product:       kiteconnect.ProductCNC,
+			})
+		}
+		h := &holdings[i]
+		switch {
+		case trade.TransactionType == kiteconnect.TransactionTypeSell:
+			h.Quantity -= trade.Quantity
+		case tradeDay(trade) == today:
+			h.T1Quantity += trade.Quantity
+		default:
+			h.Quantity += trade.Quantity
+		}
+		if trade.TransactionType == kiteconnect.TransactionTypeBuy {
+			cost[key] += trade.Price * float64(trade.Quantity)
+			bought[key] += trade.Quantity
+		}
+	}
+	holdings = slices.DeleteFunc(holdings, func(h kiteconnect.Holding) bool { return h.Quantity+h.T1Quantity <= 0 })
+	for i := range holdings {
+		h := &holdings[i]
+		key := h.Exchange + ":" + h.Tradingsymbol
+		price := prices[key]
+		if bought[key] > 0 {
+			h.AveragePrice = cost[key] / float64(bought[key])
+		}
+		h.LastPrice = price.last
+		h.ClosePrice = price.close
+		h.PnL = float64(h.Quantity+h.T1Quantity) * (price.last - h.AveragePrice)
+		if price.close > 0 {
+			h.DayChange = price.last - price.close
+			h.DayChangePercentage = h.DayChange / price.close * 100
+		}
+	}
+	return holdings
+}
+
+func newPaperCmd(opts *rootOptions) *cobra.Command {
+	paperCmd := &cobra.Command{
+		Use:   "paper",
+		Short: "Manage the local paper-trading account used by --paper",
+		Long: strings.Join([]string{
+			"With --paper, order place/modify/cancel, orders list/trades, positions and holdings use a simulated account stored in the cache directory.",
+			"Orders fill against live quotes, or against candles saved by quote historical once a replay clock is set.",
+		}, " "),
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show paper account cash, orders and replay clock",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, profileName, state, err := loadPaperCommandState(opts)
+			if err != nil {
+				return err
+			}
+			return printPaperStatus(ctx, cmd, profileName, state)
+		},
+	}
+
+	var resetCapital float64
+	var resetYes bool
+	resetCmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Discard all paper orders and trades and start over",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if resetCapital <= 0 {
+				return exitcode.New(exitcode.Validation, "--capital must be positive")
+			}
+			ctx, profileName, _, err := loadPaperCommandState(opts)
+			if err != nil {
+				return err
+			}
+			if !resetYes {
+				ok, err := confirmPrompt(cmd.InOrStdin(), cmd.OutOrStdout(), fmt.Sprintf("Reset paper account for %s? [y/N]: ", profileName))
+				if err != nil {
+					return exitcode.Wrap(exitcode.Internal, "read confirmation", err)
+				}
+				if !ok {
+					return exitcode.New(exitcode.Validation, "reset cancelled")
+				}
+			}
+			state := newPaperState(resetCapital)
+			if err := savePaperState(profileName, state); err != nil {
+				return err
+			}
+			return printPaperStatus(ctx, cmd, profileName, state)
+		},
+	}
+	resetCmd.Flags().Float64Var(&resetCapital, "capital", defaultPaperCapital, "Starting cash")
+	resetCmd.Flags().BoolVar(&resetYes, "yes", false, "Skip the confirmation prompt")
+
+	clockCmd := &cobra.Command{
+		Use:   "clock",
+		Short: "Replay stored candles instead of live quotes",
+	}
+
+	var setInterval string
+	setCmd := &cobra.Command{
+		Use:   "set <time>",
+		Short: "Start replay at a time (IST) using stored candles of an interval",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, ok := historicalIntervals[setInterval]; !ok {
+				return exitcode.New(exitcode.Validation, "--interval must be one of minute, 3minute, 5minute, 10minute, 15minute, 30minute, 60minute, day")
+			}
+			at, err := parseHistoricalTime(args[0], "time")
+			if err != nil {
+				return err
+			}
+			ctx, profileName, state, err := loadPaperCommandState(opts)
+			if err != nil {
+				return err
+			}
+			state.Clock = istWallClock(at).UTC().Format(time.RFC3339)
+			state.Interval = setInterval
+			if err := savePaperState(profileName, state); err != nil {
+				return err
+			}
+			return printPaperStatus(ctx, cmd, profileName, state)
+		},
+	}
+	setCmd.Flags().StringVar(&setInterval, "interval", "day", "Candle interval to replay")
+
+	var stepBars int
+	stepCmd := &cobra.Command{
+		Use:   "step",
+		Short: "Advance the replay clock by whole bars and fill pending orders",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if stepBars <= 0 {
+				return exitcode.New(exitcode.Validation, "--bars must be positive")
+			}
+			ctx, profileName, state, err := loadPaperCommandState(opts)
+			if err != nil {
+				return err
+			}
+			clock, ok := state.clock()
+			if !ok {
+				return exitcode.New(exitcode.Validation, "no replay clock set; run `zerodha paper clock set <time>` first")
+			}
+			state.Clock = clock.Add(time.Duration(stepBars) * historicalIntervals[state.Interval]).UTC().Format(time.RFC3339)
+			if err := savePaperState(profileName, state); err != nil {
+				return err
+			}
+			return printPaperStatus(ctx, cmd, profileName, state)
+		},
+	}
+	stepCmd.Flags().IntVar(&stepBars, "bars", 1, "Number of bars to advance")
+
+	liveCmd := &cobra.Command{
+		Use:   "live",
+		Short: "Stop replay and fill against live quotes again",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, profileName, state, err := loadPaperCommandState(opts)
+			if err != nil {
+				return err
+			}
+			state.Clock, state.Interval = "", ""
+			if err := savePaperState(profileName, state); err != nil {
+				return err
+			}
+			return printPaperStatus(ctx, cmd, profileName, state)
+		},
+	}
+
+	clockCmd.AddCommand(setCmd, stepCmd, liveCmd)
+	paperCmd.AddCommand(statusCmd, resetCmd, clockCmd)
+	return paperCmd
+}
+
+func loadPaperCommandState(opts *rootOptions) (*commandContext, string, paperState, error) {
+	ctx, err := newCommandContext(opts)
+	if err != nil {
+		return nil, "", paperState{}, err
+	}
+	profileName, _, err := ctx.resolveProfile(true)
+	if err != nil {
+		return nil, "", paperState{}, err
+	}
+	state, err := loadPaperState(profileName)
+	return ctx, profileName, state, err
+}
+
+func printPaperStatus(ctx *commandContext, cmd *cobra.Command, profileName string, state paperState) error {
+	open := 0
+	for _, order := range state.Orders {
+		if order.pending() {
+			open++
+		}
+	}
+	mode, clock := "live", ""
+	if at, ok := state.clock(); ok {
+		mode, clock = "replay", at.In(istLocation).Format(time.RFC3339)
+	}
+
+	printer := ctx.printer(cmd.OutOrStdout())
+	if printer.IsJSON() {
+		return printer.JSON(map[string]any{
+			"profile":     profileName,
+			"mode":        mode,
+			"clock":       clock,
+			"interval":    state.Interval,
+			"capital":     state.Capital,
+			"cash":        state.Cash,
+			"orders":      len(state.Orders),
+			"open_orders": open,
+			"trades":      len(state.Trades),
+		})
+	}
+	return printer.KV([][2]string{
+		{"profile", profileName},
+		{"mode", mode},
+		{"clock", dashIfEmpty(clock)},
+		{"interval", dashIfEmpty(state.Interval)},
+		{"capital", formatFloat(state.Capital)},
+		{"cash", formatFloat(state.Cash)},
+		{"orders", intToString(len(state.Orders))},
+		{"open_orders", intToString(open)},
+		{"trades", intToString(len(state.Trades))},
+	})
+}
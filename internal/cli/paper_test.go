@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/paths"
+)
+
+func newTestPaperBroker(t *testing.T, now time.Time, prices map[string]paperPrice) *paperBroker {
+	t.Helper()
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("HOME", cacheHome)
+
+	b, err := newPaperBroker("default", nil)
+	if err != nil {
+		t.Fatalf("new paper broker: %v", err)
+	}
+	b.now = func() time.Time { return now }
+	b.prices = func(keys []string) (map[string]paperPrice, error) {
+		out := make(map[string]paperPrice, len(keys))
+		for _, key := range keys {
+			out[key] = prices[key]
+		}
+		return out, nil
+	}
+	return b
+}
+
+func TestPaperBrokerFillsAgainstLiveQuotes(t *testing.T) {
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, istLocation)
+	prices := map[string]paperPrice{"NSE:INFY": {last: 1500, bid: 1499.5, ask: 1500.5, close: 1480}}
+	b := newTestPaperBroker(t, now, prices)
+
+	buy := kiteconnect.OrderParams{Exchange: "NSE", Tradingsymbol: "INFY", TransactionType: "BUY", OrderType: "MARKET", Product: "CNC", Quantity: 10}
+	resp, err := b.PlaceOrder("regular", buy)
+	if err != nil {
+		t.Fatalf("place market: %v", err)
+	}
+	if b.state.Cash != defaultPaperCapital-15005 {
+		t.Fatalf("expected buy at the ask, cash %v", b.state.Cash)
+	}
+
+	// More than the 10 shares bought cannot be sold delivery.
+	oversell := buy
+	oversell.TransactionType, oversell.Quantity = "SELL", 11
+	if _, err := b.PlaceOrder("regular", oversell); err != nil {
+		t.Fatalf("place oversell: %v", err)
+	}
+
+	sell := buy
+	sell.TransactionType, sell.OrderType, sell.Price = "SELL", "LIMIT", 1510
+	limit, err := b.PlaceOrder("regular", sell)
+	if err != nil {
+		t.Fatalf("place limit: %v", err)
+	}
+
+	orders, err := b.GetOrders()
+	if err != nil {
+		t.Fatalf("orders: %v", err)
+	}
+	if orders[0].OrderID != resp.OrderID || orders[0].Status != paperStatusComplete || orders[0].AveragePrice != 1500.5 {
+		t.Fatalf("unexpected market order: %+v", orders[0])
+	}
+	if orders[1].Status != paperStatusRejected || orders[2].Status != paperStatusOpen {
+		t.Fatalf("expected rejected oversell and open limit, got %s and %s", orders[1].Status, orders[2].Status)
+	}
+
+	positions, err := b.GetPositions()
+	if err != nil {
+		t.Fatalf("positions: %v", err)
+	}
+	if len(positions.Net) != 1 || positions.Net[0].Quantity != 10 || positions.Net[0].PnL != -5 {
+		t.Fatalf("unexpected positions: %+v", positions.Net)
+	}
+	holdings, err := b.GetHoldings()
+	if err != nil {
+		t.Fatalf("holdings: %v", err)
+	}
+	if len(holdings) != 1 || holdings[0].Quantity != 0 || holdings[0].T1Quantity != 10 {
+		t.Fatalf("expected today's buy as T1 quantity, got %+v", holdings)
+	}
+
+	prices["NSE:INFY"] = paperPrice{last: 1512, bid: 1511, ask: 1513}
+	history, err := b.GetOrderHistory(limit.OrderID)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(history) != 2 || history[1].Status != paperStatusComplete || history[1].AveragePrice != 1511 {
+		t.Fatalf("expected limit to fill at the bid once reached, got %+v", history)
+	}
+	if b.state.Cash != defaultPaperCapital-15005+15110 {
+		t.Fatalf("unexpected cash after round trip: %v", b.state.Cash)
+	}
+
+	reloaded, err := loadPaperState("default")
+	if err != nil || len(reloaded.Trades) != 2 {
+		t.Fatalf("expected trades to persist, got %+v %v", reloaded.Trades, err)
+	}
+
+	if _, err := b.CancelOrder("regular", limit.OrderID, nil); err == nil {
+		t.Fatalf("expected completed order to be uncancellable")
+	}
+	if _, err := b.PlaceGTT(kiteconnect.GTTParams{}); err == nil {
+		t.Fatalf("expected GTT to be unsupported")
+	}
+}
+
+func TestPaperOrderPlacedOnceAcrossTokenRefresh(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+	srv.ExpireAccessToken()
+
+	place := []string{"order", "place", "--paper", "--exchange", "NSE", "--symbol", "INFY", "--txn", "BUY", "--type", "MARKET", "--product", "CNC", "--qty", "5"}
+	if _, _, err := executeCLICommand(t, configPath, place...); err != nil {
+		t.Fatalf("paper place: %v", err)
+	}
+	if srv.Renewals() != 1 {
+		t.Fatalf("expected the first quote to force a token refresh, got %d renewals", srv.Renewals())
+	}
+
+	state, err := loadPaperState("default")
+	if err != nil {
+		t.Fatalf("load paper account: %v", err)
+	}
+	if len(state.Orders) != 1 || len(state.Trades) != 1 {
+		t.Fatalf("expected one order and one fill, got %+v and %+v", state.Orders, state.Trades)
+	}
+}
+
+func TestLoadPaperStateReportsUnreadableAccount(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("HOME", cacheHome)
+
+	state, err := loadPaperState("default")
+	if err != nil || state.Cash != defaultPaperCapital {
+		t.Fatalf("expected a missing account to start fresh, got %+v, %v", state, err)
+	}
+
+	cacheDir := filepath.Join(cacheHome, paths.AppName)
+	if err := os.MkdirAll(cacheDir, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "paper"), nil, 0o600); err != nil {
+		t.Fatalf("block paper store: %v", err)
+	}
+	if _, err := loadPaperState("default"); exitcode.Code(err) != exitcode.Internal || !strings.Contains(err.Error(), "read paper account") {
+		t.Fatalf("expected an unreadable account to be reported, got %v", err)
+	}
+}
+
+func TestPaperFillPriceReplaysBars(t *testing.T) {
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, istLocation)
+	bars := []kiteconnect.HistoricalData{
+		testCandle(day, 100, 102, 98, 101, 10, 0),
+		testCandle(day.AddDate(0, 0, 1), 101, 103, 99, 102, 10, 0),
+		testCandle(day.AddDate(0, 0, 2), 95, 97, 94, 96, 10, 0),
+	}
+	// Placed mid-way through the first bar, whose range so far is unknown.
+	since := day.Add(12 * time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name  string
+		order paperOrder
+		want  float64
+		fills bool
+	}{
+		{name: "market at next open", order: paperOrder{TransactionType: "BUY", OrderType: "MARKET"}, want: 101, fills: true},
+		{name: "limit buy on gap down", order: paperOrder{TransactionType: "BUY", OrderType: "LIMIT", Price: 97}, want: 95, fills: true},
+		{name: "limit sell at price", order: paperOrder{TransactionType: "SELL", OrderType: "LIMIT", Price: 103}, want: 103, fills: true},
+		{name: "stop sell gaps through", order: paperOrder{TransactionType: "SELL", OrderType: "SL-M", TriggerPrice: 98.5}, want: 95, fills: true},
+		{name: "stop limit missed", order: paperOrder{TransactionType: "SELL", OrderType: "SL", TriggerPrice: 98.5, Price: 98}, fills: false},
+		{name: "limit never reached", order: paperOrder{TransactionType: "SELL", OrderType: "LIMIT", Price: 110}, fills: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.order.Since = since
+			got, ok := paperFillPrice(&tc.order, paperPrice{bars: bars})
+			if ok != tc.fills || got != tc.want {
+				t.Fatalf("expected (%v, %v), got (%v, %v)", tc.want, tc.fills, got, ok)
+			}
+		})
+	}
+}
+
+func TestReplayBarsHidesUnclosedBar(t *testing.T) {
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, istLocation)
+	bars := []kiteconnect.HistoricalData{
+		testCandle(day, 100, 102, 98, 101, 10, 0),
+		testCandle(day.AddDate(0, 0, 1), 101, 103, 99, 102, 10, 0),
+	}
+
+	known := replayBars(bars, day.AddDate(0, 0, 1), 24*time.Hour)
+	if known[0] != bars[0] {
+		t.Fatalf("expected the closed bar unchanged, got %+v", known[0])
+	}
+	if open := known[1]; open.High != 101 || open.Low != 101 || open.Close != 101 || open.Volume != 0 {
+		t.Fatalf("expected only the open of the bar in progress, got %+v", open)
+	}
+	if bars[1].Close != 102 {
+		t.Fatalf("expected the input bars to be left alone")
+	}
+
+	// At the clock the day has just opened: a market order fills at its open,
+	// not at a close that is not known yet.
+	order := paperOrder{TransactionType: "BUY", OrderType: "MARKET", Since: day.AddDate(0, 0, 1).UTC().Format(time.RFC3339)}
+	if got, ok := paperFillPrice(&order, paperPrice{bars: known}); !ok || got != 101 {
+		t.Fatalf("expected a fill at the open 101, got (%v, %v)", got, ok)
+	}
+}
+
+func TestPaperReplayThroughCLI(t *testing.T) {
+	configPath := writeTestConfigWithToken(t)
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("HOME", cacheHome)
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, istLocation)
+	if err := newCandleStore().save(408065, "day", false, "NSE:INFY", []kiteconnect.HistoricalData{
+		testCandle(day, 100, 102, 98, 101, 10, 0),
+		testCandle(day.AddDate(0, 0, 1), 101, 103, 96, 102, 10, 0),
+	}); err != nil {
+		t.Fatalf("seed candle store: %v", err)
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "paper", "clock", "set", "2026-10-01", "--interval", "day"); err != nil {
+		t.Fatalf("clock set: %v", err)
+	}
+	place := []string{"order", "place", "--paper", "--exchange", "NSE", "--symbol", "INFY", "--txn", "BUY", "--type", "LIMIT", "--price", "97", "--product", "CNC", "--qty", "5"}
+	if _, _, err := executeCLICommand(t, configPath, place...); err != nil {
+		t.Fatalf("paper place: %v", err)
+	}
+	// The second bar's low is only known once it has closed.
+	if _, _, err := executeCLICommand(t, configPath, "paper", "clock", "step"); err != nil {
+		t.Fatalf("clock step: %v", err)
+	}
+	if stdout, _, _ := executeCLICommand(t, configPath, "orders", "list", "--paper", "--json"); !strings.Contains(stdout, `"status": "OPEN"`) {
+		t.Fatalf("expected the limit to wait for the bar to close, got %s", stdout)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "paper", "clock", "step"); err != nil {
+		t.Fatalf("clock step: %v", err)
+	}
+
+	stdout, _, err := executeCLICommand(t, configPath, "orders", "list", "--paper", "--json")
+	if err != nil {
+		t.Fatalf("paper orders: %v", err)
+	}
+	var orders []struct {
+		Status       string  `json:"status"`
+		AveragePrice float64 `json:"average_price"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &orders); err != nil {
+		t.Fatalf("decode orders: %v\n%s", err, stdout)
+	}
+	if len(orders) != 1 || orders[0].Status != paperStatusComplete || orders[0].AveragePrice != 97 {
+		t.Fatalf("expected limit to fill on the next bar's low, got %+v", orders)
+	}
+
+	stdout, _, err = executeCLICommand(t, configPath, "paper", "status")
+	if err != nil {
+		t.Fatalf("paper status: %v", err)
+	}
+	for _, want := range []string{"replay", "2026-10-03", "999515.00"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected status to contain %q, got:\n%s", want, stdout)
+		}
+	}
+}
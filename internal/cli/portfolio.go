@@ -0,0 +1,313 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+)
+
+const (
+	assetClassEquity     = "equity"
+	assetClassMutualFund = "mutual_fund"
+)
+
+type profileHoldings struct {
+	equity kiteconnect.Holdings
+	mf     kiteconnect.MFHoldings
+}
+
+type consolidatedHolding struct {
+	ISIN           string         `json:"isin"`
+	Symbol         string         `json:"symbol"`
+	Name           string         `json:"name,omitempty"`
+	AssetClass     string         `json:"asset_class"`
+	Quantity       float64        `json:"quantity"`
+	AveragePrice   float64        `json:"average_price"`
+	LastPrice      float64        `json:"last_price"`
+	Invested       float64        `json:"invested"`
+	CurrentValue   float64        `json:"current_value"`
+	PnL            float64        `json:"pnl"`
+	PnLPercent     float64        `json:"pnl_percent"`
+	PortfolioShare float64        `json:"portfolio_percent"`
+	Accounts       []accountShare `json:"accounts"`
+}
+
+type accountShare struct {
+	Profile      string  `json:"profile"`
+	Quantity     float64 `json:"quantity"`
+	AveragePrice float64 `json:"average_price"`
+	Invested     float64 `json:"invested"`
+	CurrentValue float64 `json:"current_value"`
+	SharePercent float64 `json:"share_percent"`
+}
+
+type portfolioTotals struct {
+	AssetClass   string  `json:"asset_class"`
+	Invested     float64 `json:"invested"`
+	CurrentValue float64 `json:"current_value"`
+	PnL          float64 `json:"pnl"`
+	PnLPercent   float64 `json:"pnl_percent"`
+	SharePercent float64 `json:"share_percent"`
+}
+
+type consolidatedPortfolio struct {
+	Profiles     []string              `json:"profiles"`
+	Holdings     []consolidatedHolding `json:"holdings"`
+	AssetClasses []portfolioTotals     `json:"asset_classes"`
+	Totals       portfolioTotals       `json:"totals"`
+	// Errors holds the profiles left out because their fetch failed.
+	Errors map[string]any `json:"errors,omitempty"`
+}
+
+func newPortfolioCmd(opts *rootOptions) *cobra.Command {
+	portfolioCmd := &cobra.Command{
+		Use:   "portfolio",
+		Short: "Household views across profiles",
+	}
+
+	consolidatedCmd := allowFanOut(&cobra.Command{
+		Use:   "consolidated",
+		Short: "Merge equity and MF holdings across profiles by ISIN",
+		Long: strings.Join([]string{
+			"Fetches equity and mutual fund holdings for every profile, or those picked with --profiles or --profile, and merges them by ISIN.",
+			"Each line shows the combined quantity, quantity-weighted average cost, current value, P&L and each account's share, followed by an asset-class split and totals.",
+			"Equity quantities include T1 shares. A profile that fails is reported and left out of the totals.",
+		}, " "),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if credentialEnvSet() {
+				return exitcode.New(exitcode.Validation, "ZERODHA_API_KEY and the other credential variables would apply to every profile; unset them to consolidate profiles")
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			results, err := fanOutProfiles(ctx, func(ctx *commandContext, name string, profile *config.Profile) (profileHoldings, error) {
+				equity, err := fetchHoldings(ctx, name, profile, 0)
+				if err != nil {
+					return profileHoldings{}, err
+				}
+				mf, err := fetchMFHoldings(ctx, name, profile, 0)
+				if err != nil {
+					return profileHoldings{}, err
+				}
+				return profileHoldings{equity: equity, mf: mf}, nil
+			})
+			if err != nil {
+				return err
+			}
+
+			portfolio := consolidateHoldings(results)
+			if err := printConsolidatedPortfolio(ctx, cmd, portfolio, results); err != nil {
+				return err
+			}
+			return fanOutError(results)
+		},
+	})
+
+	portfolioCmd.AddCommand(consolidatedCmd)
+	return portfolioCmd
+}
+
+// consolidateHoldings merges the successful results by ISIN, falling back to
+// exchange and symbol for holdings without one.
+func consolidateHoldings(results []profileResult[profileHoldings]) consolidatedPortfolio {
+	portfolio := consolidatedPortfolio{Profiles: []string{}, Holdings: []consolidatedHolding{}}
+	byKey := make(map[string]*consolidatedHolding)
+	add := func(key string, line consolidatedHolding, account accountShare) {
+		holding, ok := byKey[key]
+		if !ok {
+			holding = &line
+			byKey[key] = holding
+		}
+		if holding.LastPrice == 0 {
+			holding.LastPrice = line.LastPrice
+		}
+		holding.Quantity += account.Quantity
+		holding.Invested += account.Invested
+		holding.CurrentValue += account.CurrentValue
+		holding.Accounts = append(holding.Accounts, account)
+	}
+
+	for _, result := range results {
+		if result.err != nil {
+			continue
+		}
+		portfolio.Profiles = append(portfolio.Profiles, result.profile)
+		for _, h := range result.value.equity {
+			quantity := float64(h.Quantity + h.T1Quantity)
+			if quantity <= 0 {
+				continue
+			}
+			key := strings.TrimSpace(h.ISIN)
+			if key == "" {
+				key = h.Exchange + ":" + h.Tradingsymbol
+			}
+			add(key, consolidatedHolding{
+				ISIN:       h.ISIN,
+				Symbol:     h.Tradingsymbol,
+				AssetClass: assetClassEquity,
+				LastPrice:  h.LastPrice,
+			}, accountShare{
+				Profile:      result.profile,
+				Quantity:     quantity,
+				AveragePrice: h.AveragePrice,
+				Invested:     quantity * h.AveragePrice,
+				CurrentValue: quantity * h.LastPrice,
+			})
+		}
+		// Kite reports a fund's ISIN as its tradingsymbol.
+		for _, h := range result.value.mf {
+			if h.Quantity <= 0 {
+				continue
+			}
+			add(h.Tradingsymbol, consolidatedHolding{
+				ISIN:       h.Tradingsymbol,
+				Symbol:     h.Tradingsymbol,
+				Name:       h.Fund,
+				AssetClass: assetClassMutualFund,
+				LastPrice:  h.LastPrice,
+			}, accountShare{
+				Profile:      result.profile,
+				Quantity:     h.Quantity,
+				AveragePrice: h.AveragePrice,
+				Invested:     h.Quantity * h.AveragePrice,
+				CurrentValue: h.Quantity * h.LastPrice,
+			})
+		}
+	}
+
+	classes := map[string]*portfolioTotals{
+		assetClassEquity:     {AssetClass: assetClassEquity},
+		assetClassMutualFund: {AssetClass: assetClassMutualFund},
+	}
+	portfolio.Totals.AssetClass = "total"
+	for _, holding := range byKey {
+		holding.AveragePrice = holding.Invested / holding.Quantity
+		holding.PnL = holding.CurrentValue - holding.Invested
+		holding.PnLPercent = percentOf(holding.PnL, holding.Invested)
+		for i := range holding.Accounts {
+			holding.Accounts[i].SharePercent = percentOf(holding.Accounts[i].Quantity, holding.Quantity)
+		}
+		sort.SliceStable(holding.Accounts, func(i, j int) bool {
+			return holding.Accounts[i].Quantity > holding.Accounts[j].Quantity
+		})
+		for _, totals := range []*portfolioTotals{classes[holding.AssetClass], &portfolio.Totals} {
+			totals.Invested += holding.Invested
+			totals.CurrentValue += holding.CurrentValue
+		}
+		portfolio.Holdings = append(portfolio.Holdings, *holding)
+	}
+
+	for i := range portfolio.Holdings {
+		portfolio.Holdings[i].PortfolioShare = percentOf(portfolio.Holdings[i].CurrentValue, portfolio.Totals.CurrentValue)
+	}
+	sort.Slice(portfolio.Holdings, func(i, j int) bool {
+		a, b := portfolio.Holdings[i], portfolio.Holdings[j]
+		if a.CurrentValue != b.CurrentValue {
+			return a.CurrentValue > b.CurrentValue
+		}
+		return a.ISIN+a.Symbol < b.ISIN+b.Symbol
+	})
+
+	for _, class := range []string{assetClassEquity, assetClassMutualFund} {
+		portfolio.AssetClasses = append(portfolio.AssetClasses, finishTotals(*classes[class], portfolio.Totals.CurrentValue))
+	}
+	portfolio.Totals = finishTotals(portfolio.Totals, portfolio.Totals.CurrentValue)
+	return portfolio
+}
+
+func finishTotals(totals portfolioTotals, portfolioValue float64) portfolioTotals {
+	totals.PnL = totals.CurrentValue - totals.Invested
+	totals.PnLPercent = percentOf(totals.PnL, totals.Invested)
+	totals.SharePercent = percentOf(totals.CurrentValue, portfolioValue)
+	return totals
+}
+
+func percentOf(part, whole float64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return part / whole * 100
+}
+
+func printConsolidatedPortfolio(ctx *commandContext, cmd *cobra.Command, portfolio consolidatedPortfolio, results []profileResult[profileHoldings]) error {
+	printer := ctx.printer(cmd.OutOrStdout())
+	if printer.IsJSON() {
+		for _, result := range results {
+			if result.err == nil {
+				continue
+			}
+			if portfolio.Errors == nil {
+				portfolio.Errors = make(map[string]any)
+			}
+			portfolio.Errors[result.profile] = profileErrorJSON(result.err)
+		}
+		return printer.JSON(portfolio)
+	}
+
+	for _, result := range results {
+		if result.err != nil {
+			if _, err := fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", result.profile, result.err); err != nil {
+				return err
+			}
+		}
+	}
+
+	rows := make([][]string, 0, len(portfolio.Holdings))
+	for _, holding := range portfolio.Holdings {
+		shares := make([]string, 0, len(holding.Accounts))
+		for _, account := range holding.Accounts {
+			shares = append(shares, fmt.Sprintf("%s %.0f%%", account.Profile, account.SharePercent))
+		}
+		symbol := holding.Symbol
+		if holding.Name != "" {
+			symbol = holding.Name
+		}
+		rows = append(rows, []string{
+			dashIfEmpty(holding.ISIN),
+			symbol,
+			holding.AssetClass,
+			formatFloat(holding.Quantity),
+			formatFloat(holding.AveragePrice),
+			formatFloat(holding.LastPrice),
+			formatFloat(holding.CurrentValue),
+			formatFloat(holding.PnL),
+			formatFloat(holding.PnLPercent),
+			formatFloat(holding.PortfolioShare),
+			strings.Join(shares, ", "),
+		})
+	}
+	if len(rows) == 0 {
+		rows = append(rows, []string{"-", "-", "-", "0.00", "0.00", "0.00", "0.00", "0.00", "0.00", "0.00", "-"})
+	}
+	if err := printer.Table(
+		[]string{"ISIN", "SYMBOL", "CLASS", "QTY", "AVG_COST", "LTP", "VALUE", "PNL", "PNL_%", "WEIGHT_%", "ACCOUNTS"},
+		rows,
+	); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(cmd.OutOrStdout()); err != nil {
+		return err
+	}
+
+	split := make([][]string, 0, len(portfolio.AssetClasses)+1)
+	for _, totals := range append(portfolio.AssetClasses, portfolio.Totals) {
+		split = append(split, []string{
+			totals.AssetClass,
+			formatFloat(totals.Invested),
+			formatFloat(totals.CurrentValue),
+			formatFloat(totals.PnL),
+			formatFloat(totals.PnLPercent),
+			formatFloat(totals.SharePercent),
+		})
+	}
+	return printer.Table([]string{"ASSET_CLASS", "INVESTED", "VALUE", "PNL", "PNL_%", "SHARE_%"}, split)
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+)
+
+func TestPortfolioConsolidatedMergesProfilesByISIN(t *testing.T) {
+	_, configPath := newFanOutConfig(t)
+
+	stdout, _, err := executeCLICommand(t, configPath, "portfolio", "consolidated", "--profiles", "main,family", "--json")
+	if err != nil {
+		t.Fatalf("portfolio consolidated: %v", err)
+	}
+
+	var out consolidatedPortfolio
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout)
+	}
+	if strings.Join(out.Profiles, ",") != "main,family" || out.Errors != nil {
+		t.Fatalf("expected main and family without errors, got %+v", out)
+	}
+
+	byISIN := make(map[string]consolidatedHolding)
+	for _, holding := range out.Holdings {
+		byISIN[holding.ISIN] = holding
+	}
+	tcs := byISIN["INE467B01029"]
+	if tcs.Quantity != 8 || tcs.AveragePrice != 3050 || tcs.CurrentValue != 25600 || tcs.PnL != 1200 {
+		t.Fatalf("unexpected TCS line: %+v", tcs)
+	}
+	if len(tcs.Accounts) != 2 || tcs.Accounts[0].SharePercent != 50 || tcs.Accounts[1].SharePercent != 50 {
+		t.Fatalf("expected an even split across two accounts, got %+v", tcs.Accounts)
+	}
+	if infy := byISIN["INE009A01021"]; infy.Quantity != 20 {
+		t.Fatalf("expected T1 quantity to count, got %+v", infy)
+	}
+	fund := byISIN["INF109K01Z48"]
+	if fund.AssetClass != assetClassMutualFund || fund.Name == "" || math.Abs(fund.Quantity-1009.93) > 1e-6 {
+		t.Fatalf("unexpected fund line: %+v", fund)
+	}
+
+	if len(out.AssetClasses) != 2 || out.AssetClasses[0].AssetClass != assetClassEquity || out.AssetClasses[1].AssetClass != assetClassMutualFund {
+		t.Fatalf("expected an equity and mutual fund split, got %+v", out.AssetClasses)
+	}
+	sum := out.AssetClasses[0].CurrentValue + out.AssetClasses[1].CurrentValue
+	if out.Totals.AssetClass != "total" || math.Abs(out.Totals.CurrentValue-sum) > 1e-6 || math.Abs(out.AssetClasses[0].SharePercent+out.AssetClasses[1].SharePercent-100) > 1e-6 {
+		t.Fatalf("expected totals to add up, got %+v and %+v", out.Totals, out.AssetClasses)
+	}
+}
+
+func TestPortfolioConsolidatedReportsFailedProfiles(t *testing.T) {
+	_, configPath := newFanOutConfig(t)
+
+	stdout, _, err := executeCLICommand(t, configPath, "portfolio", "consolidated", "--all-profiles", "--json")
+	if exitcode.Code(err) != exitcode.Auth || !strings.Contains(err.Error(), "1 of 3 profiles failed: stale") {
+		t.Fatalf("expected the stale profile to fail the command, got %v", err)
+	}
+	var out consolidatedPortfolio
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout)
+	}
+	if len(out.Profiles) != 2 || out.Errors["stale"] == nil {
+		t.Fatalf("expected stale to be reported and left out, got %+v", out)
+	}
+
+	stdout, stderr, err := executeCLICommand(t, configPath, "portfolio", "consolidated", "--all-profiles")
+	if exitcode.Code(err) != exitcode.Auth {
+		t.Fatalf("expected an auth failure, got %v", err)
+	}
+	if !strings.Contains(stderr, "stale: ") {
+		t.Fatalf("expected the stale error on stderr, got %q", stderr)
+	}
+	if !strings.HasPrefix(stdout, "ISIN") || !strings.Contains(stdout, "ASSET_CLASS") || !strings.Contains(stdout, "family 50%, main 50%") {
+		t.Fatalf("unexpected table output:\n%s", stdout)
+	}
+}
+
+func TestConsolidateHoldingsWeightsAverageCost(t *testing.T) {
+	results := []profileResult[profileHoldings]{
+		{profile: "main", value: profileHoldings{equity: kiteconnect.Holdings{
+			{Tradingsymbol: "TCS", Exchange: "NSE", ISIN: "INE467B01029", Quantity: 30, AveragePrice: 3000, LastPrice: 3200},
+		}}},
+		{profile: "family", value: profileHoldings{equity: kiteconnect.Holdings{
+			{Tradingsymbol: "TCS", Exchange: "BSE", ISIN: "INE467B01029", Quantity: 10, AveragePrice: 3400, LastPrice: 3200},
+			{Tradingsymbol: "NOISIN", Exchange: "NSE", Quantity: 1, AveragePrice: 10, LastPrice: 10},
+		}}},
+	}
+
+	portfolio := consolidateHoldings(results)
+	if len(portfolio.Holdings) != 2 {
+		t.Fatalf("expected TCS merged across exchanges, got %+v", portfolio.Holdings)
+	}
+	tcs := portfolio.Holdings[0]
+	if tcs.Quantity != 40 || tcs.AveragePrice != 3100 || tcs.PnL != 4000 {
+		t.Fatalf("expected a quantity-weighted average, got %+v", tcs)
+	}
+	if tcs.Accounts[0].Profile != "main" || tcs.Accounts[0].SharePercent != 75 {
+		t.Fatalf("expected main to hold 75%%, got %+v", tcs.Accounts)
+	}
+	if portfolio.Holdings[1].ISIN != "" || portfolio.Holdings[1].Symbol != "NOISIN" {
+		t.Fatalf("expected a holding without ISIN to stand alone, got %+v", portfolio.Holdings[1])
+	}
+}
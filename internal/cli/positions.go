@@ -1,27 +1,56 @@
 package cli
 
 import (
+	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
 	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
 	"github.com/spf13/cobra"
 	kiteconnect "github.com/zerodha/gokiteconnect/v4"
 )
 
 func newPositionsCmd(opts *rootOptions) *cobra.Command {
-	var positionsLimit int
-	positionsCmd := &cobra.Command{
+	var (
+		positionsLimit  int
+		positionsGreeks bool
+		greeksOpts      greeksFlags
+	)
+	positionsCmd := allowFanOut(&cobra.Command{
 		Use:   "positions",
 		Short: "List current positions",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			if err := validateLimit(positionsLimit); err != nil {
 				return err
 			}
+			if err := validateGreeksFlags(greeksOpts); err != nil {
+				return err
+			}
+			if positionsGreeks && opts.fanOut() {
+				return exitcode.New(exitcode.Validation, "--greeks cannot be combined with --all-profiles or --profiles")
+			}
 
 			ctx, err := newCommandContext(opts)
 			if err != nil {
 				return err
 			}
+			if opts.fanOut() {
+				results, err := fanOutProfiles(ctx, func(ctx *commandContext, name string, profile *config.Profile) (kiteconnect.Positions, error) {
+					return fetchPositions(ctx, name, profile, positionsLimit)
+				})
+				if err != nil {
+					return err
+				}
+				return printFanOut(ctx, cmd, results, positionHeaders, func(positions kiteconnect.Positions) [][]string {
+					rows := make([][]string, 0, len(positions.Net))
+					for _, position := range positions.Net {
+						rows = append(rows, positionRow(position))
+					}
+					return rows
+				})
+			}
 			profileName, profile, err := ctx.resolveProfile(true)
 			if err != nil {
 				return err
@@ -30,41 +59,65 @@ func newPositionsCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			positions, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.Positions, error) {
-				return client.GetPositions()
-			})
+			positions, err := fetchPositions(ctx, profileName, profile, positionsLimit)
 			if err != nil {
 				return err
 			}
-			positions.Net = applyLimit(positions.Net, positionsLimit)
-			positions.Day = applyLimit(positions.Day, positionsLimit)
+
+			var greeks []*optionAnalytics
+			if positionsGreeks {
+				greeks, err = positionGreeks(ctx, cmd, profileName, profile, greeksOpts, positions.Net)
+				if err != nil {
+					return err
+				}
+			}
 
 			printer := ctx.printer(cmd.OutOrStdout())
 			if printer.IsJSON() {
-				return printer.JSON(positions)
+				if !positionsGreeks {
+					return printer.JSON(positions)
+				}
+				type netPosition struct {
+					kiteconnect.Position
+					Greeks *optionAnalytics `json:"greeks,omitempty"`
+				}
+				net := make([]netPosition, 0, len(positions.Net))
+				for i, position := range positions.Net {
+					net = append(net, netPosition{Position: position, Greeks: greeks[i]})
+				}
+				return printer.JSON(map[string]any{
+					"net": net,
+					"day": positions.Day,
+				})
 			}
 
+			headers := slices.Clone(positionHeaders)
+			if positionsGreeks {
+				headers = append(headers, "IV", "DELTA", "GAMMA", "THETA", "VEGA")
+			}
 			rows := make([][]string, 0, len(positions.Net))
-			for _, position := range positions.Net {
-				rows = append(rows, []string{
-					position.Tradingsymbol,
-					position.Exchange,
-					position.Product,
-					intToString(position.Quantity),
-					formatFloat(position.AveragePrice),
-					formatFloat(position.LastPrice),
-					formatFloat(position.PnL),
-				})
+			for i, position := range positions.Net {
+				row := positionRow(position)
+				if positionsGreeks {
+					row = append(row, positionGreeksCells(greeks[i])...)
+				}
+				rows = append(rows, row)
 			}
 
 			if len(rows) == 0 {
-				rows = append(rows, []string{"-", "-", "-", "0", "0.00", "0.00", "0.00"})
+				row := []string{"-", "-", "-", "0", "0.00", "0.00", "0.00"}
+				if positionsGreeks {
+					row = append(row, positionGreeksCells(nil)...)
+				}
+				rows = append(rows, row)
 			}
 
-			return printer.Table([]string{"SYMBOL", "EXCHANGE", "PRODUCT", "QTY", "AVG_PRICE", "LTP", "PNL"}, rows)
+			return printer.Table(headers, rows)
 		},
-	}
+	})
 	positionsCmd.Flags().IntVar(&positionsLimit, "limit", 0, "Limit number of rows (0 = no limit)")
+	positionsCmd.Flags().BoolVar(&positionsGreeks, "greeks", false, "Add per-unit IV and Greeks for NFO/BFO option positions")
+	bindGreeksFlags(positionsCmd, &greeksOpts)
 
 	var (
 		exchange     string
@@ -114,7 +167,7 @@ func newPositionsCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			converted, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (bool, error) {
+			converted, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (bool, error) {
 				return client.ConvertPosition(params)
 			})
 			if err != nil {
@@ -157,6 +210,88 @@ func newPositionsCmd(opts *rootOptions) *cobra.Command {
 	convertCmd.Flags().StringVar(&txnType, "txn", "", "Transaction type (BUY/SELL)")
 	convertCmd.Flags().IntVar(&quantity, "qty", 0, "Quantity")
 
-	positionsCmd.AddCommand(convertCmd)
+	positionsCmd.AddCommand(convertCmd, newPositionsRiskCmd(opts), newPositionsRolloverCmd(opts))
 	return positionsCmd
 }
+
+var positionHeaders = []string{"SYMBOL", "EXCHANGE", "PRODUCT", "QTY", "AVG_PRICE", "LTP", "PNL"}
+
+func fetchPositions(ctx *commandContext, profileName string, profile *config.Profile, limit int) (kiteconnect.Positions, error) {
+	positions, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Positions, error) {
+		return client.GetPositions()
+	})
+	if err != nil {
+		return positions, err
+	}
+	positions.Net = applyLimit(positions.Net, limit)
+	positions.Day = applyLimit(positions.Day, limit)
+	return positions, nil
+}
+
+func positionRow(position kiteconnect.Position) []string {
+	return []string{
+		position.Tradingsymbol,
+		position.Exchange,
+		position.Product,
+		intToString(position.Quantity),
+		formatFloat(position.AveragePrice),
+		formatFloat(position.LastPrice),
+		formatFloat(position.PnL),
+	}
+}
+
+// positionGreeks returns analytics aligned with positions; entries are nil for
+// non-option positions and contracts missing from the instrument master.
+func positionGreeks(
+	ctx *commandContext,
+	cmd *cobra.Command,
+	profileName string,
+	profile *config.Profile,
+	flags greeksFlags,
+	positions []kiteconnect.Position,
+) ([]*optionAnalytics, error) {
+	greeks := make([]*optionAnalytics, len(positions))
+	contracts, index, err := derivativeContracts(ctx, profileName, profile, positions, "NFO", "BFO")
+	if err != nil {
+		return nil, err
+	}
+
+	var options []kiteconnect.Instrument
+	for i, contract := range contracts {
+		if contract == nil || contract.InstrumentType == "FUT" {
+			contracts[i] = nil
+			continue
+		}
+		options = append(options, *contract)
+	}
+	if len(options) == 0 {
+		return greeks, nil
+	}
+
+	now := time.Now()
+	underlying, err := resolveUnderlyingPrices(ctx, profileName, profile, flags, options, index, now)
+	if err != nil {
+		return nil, err
+	}
+	rate := ctx.riskFreeRate(cmd, flags)
+	for i, contract := range contracts {
+		if contract == nil || positions[i].LastPrice <= 0 {
+			continue
+		}
+		greeks[i], _ = analyzeOption(*contract, positions[i].LastPrice, flags.model(), underlying[strings.ToUpper(contract.Name)], rate, now)
+	}
+	return greeks, nil
+}
+
+func positionGreeksCells(analytics *optionAnalytics) []string {
+	if analytics == nil {
+		return []string{"-", "-", "-", "-", "-"}
+	}
+	return []string{
+		formatPercent(analytics.IV),
+		fmt.Sprintf("%.3f", analytics.Delta),
+		fmt.Sprintf("%.5f", analytics.Gamma),
+		fmt.Sprintf("%.2f", analytics.Theta),
+		fmt.Sprintf("%.2f", analytics.Vega),
+	}
+}
@@ -0,0 +1,433 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/output"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/pricing"
+	"github.com/spf13/cobra"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+const (
+	defaultRiskRangePercent = 10
+	defaultRiskSteps        = 61
+	defaultRiskChartHeight  = 15
+)
+
+type riskReport struct {
+	Underlying      string                `json:"underlying"`
+	UnderlyingPrice float64               `json:"underlying_price"`
+	PayoffExpiry    string                `json:"payoff_expiry"`
+	NetDelta        float64               `json:"net_delta"`
+	NetGamma        float64               `json:"net_gamma"`
+	NetTheta        float64               `json:"net_theta"`
+	NetVega         float64               `json:"net_vega"`
+	Breakevens      []float64             `json:"breakevens"`
+	MaxProfit       *float64              `json:"max_profit"`
+	MaxLoss         *float64              `json:"max_loss"`
+	Legs            []riskLeg             `json:"legs"`
+	Payoff          []pricing.PayoffPoint `json:"payoff"`
+	Scenarios       []riskScenario        `json:"scenarios,omitempty"`
+}
+
+type riskLeg struct {
+	Tradingsymbol string           `json:"tradingsymbol"`
+	Exchange      string           `json:"exchange"`
+	Type          string           `json:"type"`
+	Strike        float64          `json:"strike,omitempty"`
+	Expiry        string           `json:"expiry"`
+	Quantity      int              `json:"quantity"`
+	AveragePrice  float64          `json:"average_price"`
+	LastPrice     float64          `json:"last_price"`
+	Greeks        *optionAnalytics `json:"greeks,omitempty"`
+
+	contract kiteconnect.Instrument
+}
+
+type riskScenario struct {
+	Price     float64 `json:"price"`
+	PnLNow    float64 `json:"pnl_now"`
+	PnLExpiry float64 `json:"pnl_expiry"`
+}
+
+func newPositionsRiskCmd(opts *rootOptions) *cobra.Command {
+	var (
+		riskName     string
+		riskRange    float64
+		riskSteps    int
+		riskAtPrices []float64
+		riskHeight   int
+		riskNoChart  bool
+		flags        greeksFlags
+	)
+	riskCmd := &cobra.Command{
+		Use:   "risk",
+		Short: "Aggregate Greeks and payoff at expiry per underlying for open F&O positions",
+		Long: strings.Join([]string{
+			"Groups open NFO/BFO net positions by underlying using the instrument master, sums position Greeks",
+			"(delta in underlying units, theta per day, vega per 1% vol), and plots the payoff at the earliest expiry in the group.",
+			"Legs expiring later are valued with Black-Scholes at their current implied volatility.",
+			"--at-price prints what-if P&L both now and at expiry for each given underlying price.",
+		}, " "),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if riskRange <= 0 || riskRange >= 100 {
+				return exitcode.New(exitcode.Validation, "--range must be between 0 and 100 (percent)")
+			}
+			if riskSteps < 2 {
+				return exitcode.New(exitcode.Validation, "--steps must be >= 2")
+			}
+			if riskHeight < 3 {
+				return exitcode.New(exitcode.Validation, "--chart-height must be >= 3")
+			}
+			for _, price := range riskAtPrices {
+				if price <= 0 {
+					return exitcode.New(exitcode.Validation, "--at-price values must be > 0")
+				}
+			}
+			if err := validateGreeksFlags(flags); err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			profileName, profile, err := ctx.resolveProfile(true)
+			if err != nil {
+				return err
+			}
+			if err := ensureAccessToken(profile); err != nil {
+				return err
+			}
+
+			positions, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Positions, error) {
+				return client.GetPositions()
+			})
+			if err != nil {
+				return err
+			}
+
+			open := make([]kiteconnect.Position, 0, len(positions.Net))
+			for _, position := range positions.Net {
+				if position.Quantity != 0 {
+					open = append(open, position)
+				}
+			}
+			contracts, index, err := derivativeContracts(ctx, profileName, profile, open, "NFO", "BFO")
+			if err != nil {
+				return err
+			}
+
+			var legs []riskLeg
+			var resolved []kiteconnect.Instrument
+			for i, position := range open {
+				contract := contracts[i]
+				if contract == nil {
+					continue
+				}
+				if riskName != "" && !strings.EqualFold(contract.Name, riskName) {
+					continue
+				}
+				legs = append(legs, riskLeg{
+					Tradingsymbol: position.Tradingsymbol,
+					Exchange:      position.Exchange,
+					Type:          contract.InstrumentType,
+					Strike:        contract.StrikePrice,
+					Expiry:        optionExpiryDate(*contract),
+					Quantity:      position.Quantity,
+					AveragePrice:  position.AveragePrice,
+					LastPrice:     position.LastPrice,
+					contract:      *contract,
+				})
+				resolved = append(resolved, *contract)
+			}
+
+			now := time.Now()
+			var reports []riskReport
+			if len(legs) > 0 {
+				underlying, err := resolveUnderlyingPrices(ctx, profileName, profile, flags, resolved, index, now)
+				if err != nil {
+					return err
+				}
+				reports = buildRiskReports(legs, underlying, flags.model(), ctx.riskFreeRate(cmd, flags), now, riskRange, riskSteps, riskAtPrices)
+			}
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				if reports == nil {
+					reports = []riskReport{}
+				}
+				return printer.JSON(reports)
+			}
+			if len(reports) == 0 {
+				_, err := fmt.Fprintln(cmd.OutOrStdout(), "No open NFO/BFO F&O positions found.")
+				return err
+			}
+
+			for i, report := range reports {
+				if i > 0 {
+					if _, err := fmt.Fprintln(cmd.OutOrStdout()); err != nil {
+						return err
+					}
+				}
+				if err := printRiskReport(cmd, printer, report, riskHeight, riskNoChart); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	riskCmd.Flags().StringVar(&riskName, "name", "", "Only show positions for this underlying (e.g. NIFTY)")
+	riskCmd.Flags().Float64Var(&riskRange, "range", defaultRiskRangePercent, "Payoff price range as +/- percent around the underlying price")
+	riskCmd.Flags().IntVar(&riskSteps, "steps", defaultRiskSteps, "Number of price points in the payoff range")
+	riskCmd.Flags().Float64SliceVar(&riskAtPrices, "at-price", nil, "What-if underlying price (repeatable or comma-separated)")
+	riskCmd.Flags().IntVar(&riskHeight, "chart-height", defaultRiskChartHeight, "Payoff chart height in rows")
+	riskCmd.Flags().BoolVar(&riskNoChart, "no-chart", false, "Skip the ASCII payoff chart")
+	bindGreeksFlags(riskCmd, &flags)
+	return riskCmd
+}
+
+// derivativeContracts looks up positions on the given exchanges in the
+// instrument master. The result is aligned with positions and nil for
+// anything that is not an option or futures contract.
+func derivativeContracts(
+	ctx *commandContext,
+	profileName string,
+	profile *config.Profile,
+	positions []kiteconnect.Position,
+	exchanges ...string,
+) ([]*kiteconnect.Instrument, *instrumentIndex, error) {
+	contracts := make([]*kiteconnect.Instrument, len(positions))
+
+	var all kiteconnect.Instruments
+	loaded := make(map[string]bool)
+	for _, position := range positions {
+		exchange := normalizeUpper(position.Exchange)
+		if !slices.Contains(exchanges, exchange) || loaded[exchange] {
+			continue
+		}
+		instruments, err := loadExchangeInstruments(ctx, profileName, profile, exchange)
+		if err != nil {
+			return nil, nil, err
+		}
+		all = append(all, instruments...)
+		loaded[exchange] = true
+	}
+	index := newInstrumentIndex(all)
+
+	for i, position := range positions {
+		instrument, ok := index.lookup(position.Exchange + ":" + position.Tradingsymbol)
+		if !ok {
+			continue
+		}
+		switch instrument.InstrumentType {
+		case "CE", "PE", "FUT":
+			contracts[i] = &instrument
+		}
+	}
+	return contracts, index, nil
+}
+
+func buildRiskReports(
+	legs []riskLeg,
+	underlying map[string]float64,
+	model pricing.Model,
+	rate float64,
+	now time.Time,
+	rangePercent float64,
+	steps int,
+	atPrices []float64,
+) []riskReport {
+	groups := make(map[string][]riskLeg)
+	for _, leg := range legs {
+		name := strings.ToUpper(leg.contract.Name)
+		groups[name] = append(groups[name], leg)
+	}
+
+	reports := make([]riskReport, 0, len(groups))
+	for _, name := range sortedKeys(groups) {
+		group := groups[name]
+		spot := underlying[name]
+		report := riskReport{Underlying: name, UnderlyingPrice: spot}
+
+		var payoffAt time.Time
+		book := make([]pricing.Leg, 0, len(group))
+		for i := range group {
+			leg := &group[i]
+			qty := float64(leg.Quantity)
+			pl := pricing.Leg{
+				Type:       pricing.OptionType(leg.Type),
+				Strike:     leg.Strike,
+				Quantity:   qty,
+				EntryPrice: leg.AveragePrice,
+				Expiry:     expiryClose(leg.contract),
+				Model:      model,
+			}
+			if leg.Type == "FUT" {
+				report.NetDelta += qty
+			} else if leg.LastPrice > 0 {
+				if analytics, err := analyzeOption(leg.contract, leg.LastPrice, model, spot, rate, now); err == nil {
+					leg.Greeks = analytics
+					pl.Vol = analytics.IV
+					report.NetDelta += qty * analytics.Delta
+					report.NetGamma += qty * analytics.Gamma
+					report.NetTheta += qty * analytics.Theta
+					report.NetVega += qty * analytics.Vega
+				}
+			}
+			if payoffAt.IsZero() || pl.Expiry.Before(payoffAt) {
+				payoffAt = pl.Expiry
+			}
+			book = append(book, pl)
+		}
+		report.Legs = group
+		report.PayoffExpiry = payoffAt.Format("2006-01-02")
+
+		prices := pricing.PriceGrid(spot*(1-rangePercent/100), spot*(1+rangePercent/100), steps)
+		report.Payoff = pricing.Payoff(book, prices, payoffAt, rate)
+		report.Breakevens = pricing.Breakevens(report.Payoff)
+		if report.Breakevens == nil {
+			report.Breakevens = []float64{}
+		}
+
+		// The payoff only bends at strikes, so its extremes are at a strike,
+		// at zero (the underlying cannot go lower) or out in a tail, which is
+		// unbounded when it slopes. Strikes outside the grid still count.
+		extremes := slices.Clone(report.Payoff)
+		for _, price := range append([]float64{0}, legStrikes(book)...) {
+			extremes = append(extremes, pricing.PayoffPoint{Price: price, PnL: pricing.PnL(book, price, payoffAt, rate)})
+		}
+		high, low := pricing.Extremes(extremes)
+		slope := pricing.UpperTailSlope(book)
+		if slope <= 0 {
+			report.MaxProfit = new(high.PnL)
+		}
+		if slope >= 0 {
+			report.MaxLoss = new(low.PnL)
+		}
+
+		for _, price := range atPrices {
+			report.Scenarios = append(report.Scenarios, riskScenario{
+				Price:     price,
+				PnLNow:    pricing.PnL(book, price, now, rate),
+				PnLExpiry: pricing.PnL(book, price, payoffAt, rate),
+			})
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+func legStrikes(book []pricing.Leg) []float64 {
+	var out []float64
+	for _, leg := range book {
+		if leg.Type != pricing.Future && leg.Strike > 0 {
+			out = append(out, leg.Strike)
+		}
+	}
+	return out
+}
+
+func printRiskReport(cmd *cobra.Command, printer output.Printer, report riskReport, height int, noChart bool) error {
+	breakevens := make([]string, 0, len(report.Breakevens))
+	for _, price := range report.Breakevens {
+		breakevens = append(breakevens, formatFloat(price))
+	}
+	if len(breakevens) == 0 {
+		breakevens = append(breakevens, "-")
+	}
+
+	if err := printer.KV([][2]string{
+		{"underlying", report.Underlying},
+		{"underlying_price", formatFloat(report.UnderlyingPrice)},
+		{"payoff_expiry", report.PayoffExpiry},
+		{"net_delta", fmt.Sprintf("%.2f", report.NetDelta)},
+		{"net_gamma", fmt.Sprintf("%.4f", report.NetGamma)},
+		{"net_theta", fmt.Sprintf("%.2f", report.NetTheta)},
+		{"net_vega", fmt.Sprintf("%.2f", report.NetVega)},
+		{"breakevens", strings.Join(breakevens, ", ")},
+		{"max_profit", formatRiskBound(report.MaxProfit)},
+		{"max_loss", formatRiskBound(report.MaxLoss)},
+	}); err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(report.Legs))
+	for _, leg := range report.Legs {
+		strike := "-"
+		if leg.Type != "FUT" {
+			strike = formatFloat(leg.Strike)
+		}
+		iv, delta, theta, vega := "-", "-", "-", "-"
+		if leg.Type == "FUT" {
+			delta = fmt.Sprintf("%.2f", float64(leg.Quantity))
+		}
+		if leg.Greeks != nil {
+			qty := float64(leg.Quantity)
+			iv = formatPercent(leg.Greeks.IV)
+			delta = fmt.Sprintf("%.2f", qty*leg.Greeks.Delta)
+			theta = fmt.Sprintf("%.2f", qty*leg.Greeks.Theta)
+			vega = fmt.Sprintf("%.2f", qty*leg.Greeks.Vega)
+		}
+		rows = append(rows, []string{
+			leg.Tradingsymbol,
+			leg.Type,
+			strike,
+			leg.Expiry,
+			intToString(leg.Quantity),
+			formatFloat(leg.AveragePrice),
+			formatFloat(leg.LastPrice),
+			iv,
+			delta,
+			theta,
+			vega,
+		})
+	}
+	if err := printer.Table([]string{"SYMBOL", "TYPE", "STRIKE", "EXPIRY", "QTY", "AVG_PRICE", "LTP", "IV", "DELTA", "THETA", "VEGA"}, rows); err != nil {
+		return err
+	}
+
+	if len(report.Scenarios) > 0 {
+		scenarioRows := make([][]string, 0, len(report.Scenarios))
+		for _, scenario := range report.Scenarios {
+			scenarioRows = append(scenarioRows, []string{
+				formatFloat(scenario.Price),
+				formatFloat(scenario.PnLNow),
+				formatFloat(scenario.PnLExpiry),
+			})
+		}
+		if _, err := fmt.Fprintln(cmd.OutOrStdout()); err != nil {
+			return err
+		}
+		if err := printer.Table([]string{"AT_PRICE", "PNL_NOW", "PNL_EXPIRY"}, scenarioRows); err != nil {
+			return err
+		}
+	}
+
+	if noChart {
+		return nil
+	}
+	if _, err := fmt.Fprintf(cmd.OutOrStdout(), "\nPayoff at expiry (%s)\n", report.PayoffExpiry); err != nil {
+		return err
+	}
+	xs := make([]float64, 0, len(report.Payoff))
+	ys := make([]float64, 0, len(report.Payoff))
+	for _, point := range report.Payoff {
+		xs = append(xs, point.Price)
+		ys = append(ys, point.PnL)
+	}
+	return printer.Chart(xs, ys, height)
+}
+
+func formatRiskBound(v *float64) string {
+	if v == nil {
+		return "unlimited"
+	}
+	return formatFloat(*v)
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/output"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/pricing"
+	"github.com/spf13/cobra"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+func testRiskLeg(contract kiteconnect.Instrument, qty int, avg, ltp float64) riskLeg {
+	return riskLeg{
+		Tradingsymbol: contract.Tradingsymbol,
+		Exchange:      contract.Exchange,
+		Type:          contract.InstrumentType,
+		Strike:        contract.StrikePrice,
+		Expiry:        optionExpiryDate(contract),
+		Quantity:      qty,
+		AveragePrice:  avg,
+		LastPrice:     ltp,
+		contract:      contract,
+	}
+}
+
+func TestBuildRiskReportsShortStraddle(t *testing.T) {
+	expiry := time.Date(2026, 10, 27, 0, 0, 0, 0, istLocation)
+	now := time.Date(2026, 10, 20, 15, 30, 0, 0, istLocation)
+	years := 7.0 / 365
+	call := testOptionContract(1, "NIFTY25000CE", "NIFTY", "CE", 25000, expiry)
+	put := testOptionContract(2, "NIFTY25000PE", "NIFTY", "PE", 25000, expiry)
+	callLTP := pricing.Price(pricing.Call, 25000, 25000, years, 0.065, 0.12)
+	putLTP := pricing.Price(pricing.Put, 25000, 25000, years, 0.065, 0.12)
+
+	legs := []riskLeg{
+		testRiskLeg(call, -75, 150, callLTP),
+		testRiskLeg(put, -75, 140, putLTP),
+		testRiskLeg(testOptionContract(3, "BANKNIFTY56000CE", "BANKNIFTY", "CE", 56000, expiry), 35, 300, 0),
+	}
+	reports := buildRiskReports(legs, map[string]float64{"NIFTY": 25000, "BANKNIFTY": 56000}, pricing.BlackScholes, 0.065, now, 5, 101, []float64{25000, 25500})
+	if len(reports) != 2 || reports[0].Underlying != "BANKNIFTY" || reports[1].Underlying != "NIFTY" {
+		t.Fatalf("expected reports grouped by underlying, got %+v", reports)
+	}
+
+	bank := reports[0]
+	if bank.Legs[0].Greeks != nil || bank.NetDelta != 0 {
+		t.Fatalf("expected no greeks for a leg without LTP, got %+v", bank)
+	}
+	if bank.MaxProfit != nil {
+		t.Fatalf("expected unlimited profit for a long call, got %v", *bank.MaxProfit)
+	}
+
+	nifty := reports[1]
+	if nifty.PayoffExpiry != "2026-10-27" {
+		t.Fatalf("expected payoff expiry 2026-10-27, got %s", nifty.PayoffExpiry)
+	}
+	if math.Abs(nifty.NetDelta) > 10 {
+		t.Fatalf("expected near-zero delta for an ATM straddle, got %f", nifty.NetDelta)
+	}
+	if nifty.NetTheta <= 0 || nifty.NetVega >= 0 || nifty.NetGamma >= 0 {
+		t.Fatalf("expected short straddle to be long theta, short vega and gamma, got %+v", nifty)
+	}
+	if len(nifty.Breakevens) != 2 || math.Abs(nifty.Breakevens[0]-24710) > 1e-6 || math.Abs(nifty.Breakevens[1]-25290) > 1e-6 {
+		t.Fatalf("expected breakevens 24710/25290, got %v", nifty.Breakevens)
+	}
+	if nifty.MaxLoss != nil {
+		t.Fatalf("expected unlimited loss, got %v", *nifty.MaxLoss)
+	}
+	if nifty.MaxProfit == nil || math.Abs(*nifty.MaxProfit-75*290) > 1e-6 {
+		t.Fatalf("expected max profit %v, got %v", 75*290, nifty.MaxProfit)
+	}
+
+	if len(nifty.Scenarios) != 2 {
+		t.Fatalf("expected 2 scenarios, got %d", len(nifty.Scenarios))
+	}
+	if got := nifty.Scenarios[1].PnLExpiry; math.Abs(got-(-75*500+75*290)) > 1e-6 {
+		t.Fatalf("unexpected expiry P&L at 25500: %f", got)
+	}
+	if nifty.Scenarios[0].PnLNow >= nifty.Scenarios[0].PnLExpiry {
+		t.Fatalf("expected P&L now to trail P&L at expiry for a short straddle at the strike")
+	}
+}
+
+func TestBuildRiskReportsCapsAtStrikesOutsideRange(t *testing.T) {
+	expiry := time.Date(2026, 10, 27, 0, 0, 0, 0, istLocation)
+	now := time.Date(2026, 10, 20, 15, 30, 0, 0, istLocation)
+	legs := []riskLeg{
+		testRiskLeg(testOptionContract(1, "NIFTY25000CE", "NIFTY", "CE", 25000, expiry), 75, 200, 0),
+		testRiskLeg(testOptionContract(2, "NIFTY28000CE", "NIFTY", "CE", 28000, expiry), -75, 20, 0),
+	}
+	// 28000 is 12% above spot, outside the +/-10% grid.
+	report := buildRiskReports(legs, map[string]float64{"NIFTY": 25000}, pricing.BlackScholes, 0.065, now, 10, 21, nil)[0]
+
+	if report.MaxProfit == nil || math.Abs(*report.MaxProfit-75*(3000-180)) > 1e-6 {
+		t.Fatalf("expected max profit capped at the short strike, got %v", report.MaxProfit)
+	}
+	if report.MaxLoss == nil || math.Abs(*report.MaxLoss+75*180) > 1e-6 {
+		t.Fatalf("expected max loss of the net debit, got %v", report.MaxLoss)
+	}
+}
+
+func TestPrintRiskReportRendersChart(t *testing.T) {
+	expiry := time.Date(2026, 10, 27, 0, 0, 0, 0, istLocation)
+	future := kiteconnect.Instrument{Tradingsymbol: "NIFTY26OCTFUT", Name: "NIFTY", Exchange: "NFO", InstrumentType: "FUT"}
+	future.Expiry.Time = expiry
+	reports := buildRiskReports(
+		[]riskLeg{testRiskLeg(future, 75, 25000, 25100)},
+		map[string]float64{"NIFTY": 25000},
+		pricing.BlackScholes,
+		0.065,
+		time.Date(2026, 10, 20, 10, 0, 0, 0, istLocation),
+		2, 21, nil,
+	)
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	if err := printRiskReport(cmd, output.New(&out, false), reports[0], 5, false); err != nil {
+		t.Fatalf("print risk report: %v", err)
+	}
+
+	text := out.String()
+	for _, want := range []string{"net_delta", "75.00", "max_profit", "unlimited", "Payoff at expiry (2026-10-27)", "*"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, text)
+		}
+	}
+}
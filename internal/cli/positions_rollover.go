@@ -0,0 +1,395 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/output"
+	"github.com/spf13/cobra"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+var rolloverExchanges = []string{"NFO", "BFO", "MCX"}
+
+const (
+	defaultRolloverFillTimeout = 30 * time.Second
+	rolloverPollInterval       = 500 * time.Millisecond
+)
+
+type rolloverPlan struct {
+	Exchange      string  `json:"exchange"`
+	Product       string  `json:"product"`
+	Quantity      int     `json:"quantity"`
+	Current       string  `json:"current"`
+	CurrentExpiry string  `json:"current_expiry"`
+	Next          string  `json:"next"`
+	NextExpiry    string  `json:"next_expiry"`
+	CurrentLTP    float64 `json:"current_ltp"`
+	NextLTP       float64 `json:"next_ltp"`
+	Spread        float64 `json:"spread"`
+	// ExecSpread is the spread at the touch: next ask minus current bid when
+	// rolling a long, next bid minus current ask when rolling a short.
+	ExecSpread   float64 `json:"exec_spread"`
+	CloseOrderID string  `json:"close_order_id,omitempty"`
+	OpenOrderID  string  `json:"open_order_id,omitempty"`
+
+	currentBid float64
+	currentAsk float64
+	tickSize   float64
+}
+
+func newPositionsRolloverCmd(opts *rootOptions) *cobra.Command {
+	var (
+		rolloverSymbols     []string
+		rolloverLimitSpread float64
+		rolloverDryRun      bool
+		rolloverYes         bool
+		rolloverTag         string
+		rolloverFillTimeout time.Duration
+	)
+	rolloverCmd := &cobra.Command{
+		Use:   "rollover",
+		Short: "Roll open futures positions to the next expiry",
+		Long: strings.Join([]string{
+			"Finds open NFO/BFO/MCX futures positions, pairs each with the next-expiry contract from the instrument master,",
+			"and shows the calendar spread (next minus current) from live quotes.",
+			"After confirmation (or with --yes) each close leg is placed first and its open leg only once the close has filled;",
+			"a close leg that is rejected or still pending after --fill-timeout stops the roll and is left for you to manage.",
+			"--dry-run only shows the plan.",
+			"Without --limit-spread both legs are MARKET orders. With it, the close leg is a LIMIT at the touch and the open leg",
+			"is a LIMIT at the close price plus the spread, so the roll never costs more than the given spread.",
+		}, " "),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			limitSpread := cmd.Flags().Changed("limit-spread")
+			if rolloverFillTimeout <= 0 {
+				return exitcode.New(exitcode.Validation, "--fill-timeout must be greater than 0")
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			profileName, profile, err := ctx.resolveProfile(true)
+			if err != nil {
+				return err
+			}
+			if err := ensureAccessToken(profile); err != nil {
+				return err
+			}
+
+			positions, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Positions, error) {
+				return client.GetPositions()
+			})
+			if err != nil {
+				return err
+			}
+			contracts, index, err := derivativeContracts(ctx, profileName, profile, positions.Net, rolloverExchanges...)
+			if err != nil {
+				return err
+			}
+			plans := planRollovers(positions.Net, contracts, index, rolloverSymbols)
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if len(plans) == 0 {
+				if printer.IsJSON() {
+					return printer.JSON([]rolloverPlan{})
+				}
+				_, err := fmt.Fprintln(cmd.OutOrStdout(), "No open futures positions to roll.")
+				return err
+			}
+
+			keys := make([]string, 0, 2*len(plans))
+			for _, plan := range plans {
+				keys = append(keys, plan.Exchange+":"+plan.Current, plan.Exchange+":"+plan.Next)
+			}
+			quotes, err := fetchQuotesBatched(ctx, profileName, profile, keys)
+			if err != nil {
+				return err
+			}
+			for i := range plans {
+				applyRolloverQuotes(&plans[i], quotes)
+			}
+
+			if !printer.IsJSON() {
+				if err := printRolloverPlans(printer, plans); err != nil {
+					return err
+				}
+			}
+
+			place := !rolloverDryRun && rolloverYes
+			if !rolloverDryRun && !rolloverYes && !printer.IsJSON() {
+				place, err = confirmPrompt(cmd.InOrStdin(), cmd.OutOrStdout(), fmt.Sprintf("Roll %d position(s)? [y/N]: ", len(plans)))
+				if err != nil {
+					return err
+				}
+			}
+
+			var placeErr error
+			if place {
+				placeErr = placeRollovers(ctx, profileName, profile, plans, limitSpread, rolloverLimitSpread, strings.TrimSpace(rolloverTag), rolloverFillTimeout)
+			}
+
+			if printer.IsJSON() {
+				if err := printer.JSON(plans); err != nil {
+					return err
+				}
+			} else if place {
+				rows := make([][]string, 0, len(plans))
+				for _, plan := range plans {
+					rows = append(rows, []string{plan.Current, dashIfEmpty(plan.CloseOrderID), plan.Next, dashIfEmpty(plan.OpenOrderID)})
+				}
+				if err := printer.Table([]string{"CLOSE", "CLOSE_ORDER_ID", "OPEN", "OPEN_ORDER_ID"}, rows); err != nil {
+					return err
+				}
+				if placeErr != nil {
+					if _, err := fmt.Fprintln(cmd.ErrOrStderr(), "Rollover stopped early: the order IDs above are live, rows without one were not placed."); err != nil {
+						return err
+					}
+				}
+			} else {
+				if _, err := fmt.Fprintln(cmd.OutOrStdout(), "Not placed."); err != nil {
+					return err
+				}
+			}
+			return placeErr
+		},
+	}
+	rolloverCmd.Flags().StringSliceVar(&rolloverSymbols, "symbol", nil, "Only roll these tradingsymbols or underlyings (repeatable)")
+	rolloverCmd.Flags().Float64Var(&rolloverLimitSpread, "limit-spread", 0, "Worst acceptable calendar spread (next minus current) for LIMIT pricing")
+	rolloverCmd.Flags().BoolVar(&rolloverDryRun, "dry-run", false, "Show the rollover plan without placing orders")
+	rolloverCmd.Flags().BoolVar(&rolloverYes, "yes", false, "Place the orders without prompting")
+	rolloverCmd.Flags().StringVar(&rolloverTag, "tag", "", "Optional order tag")
+	rolloverCmd.Flags().DurationVar(&rolloverFillTimeout, "fill-timeout", defaultRolloverFillTimeout, "How long to wait for each close leg to fill before placing its open leg")
+	return rolloverCmd
+}
+
+// planRollovers pairs each open futures position with the contract of the
+// same underlying that expires next after it.
+func planRollovers(
+	positions []kiteconnect.Position,
+	contracts []*kiteconnect.Instrument,
+	index *instrumentIndex,
+	filters []string,
+) []rolloverPlan {
+	var plans []rolloverPlan
+	for i, position := range positions {
+		contract := contracts[i]
+		if position.Quantity == 0 || contract == nil || contract.InstrumentType != "FUT" {
+			continue
+		}
+		if len(filters) > 0 && !matchesRolloverFilter(filters, position.Tradingsymbol, contract.Name) {
+			continue
+		}
+
+		current := optionExpiryDate(*contract)
+		var next *kiteconnect.Instrument
+		for _, candidate := range index.byToken {
+			if candidate.InstrumentType != "FUT" || candidate.Exchange != contract.Exchange || candidate.Name != contract.Name {
+				continue
+			}
+			expiry := optionExpiryDate(candidate)
+			if expiry <= current {
+				continue
+			}
+			if next == nil || expiry < optionExpiryDate(*next) {
+				next = &candidate
+			}
+		}
+		if next == nil {
+			continue
+		}
+
+		plans = append(plans, rolloverPlan{
+			Exchange:      position.Exchange,
+			Product:       position.Product,
+			Quantity:      position.Quantity,
+			Current:       position.Tradingsymbol,
+			CurrentExpiry: current,
+			Next:          next.Tradingsymbol,
+			NextExpiry:    optionExpiryDate(*next),
+			tickSize:      next.TickSize,
+		})
+	}
+	return plans
+}
+
+func matchesRolloverFilter(filters []string, symbol, name string) bool {
+	for _, filter := range filters {
+		filter = strings.TrimSpace(filter)
+		if strings.EqualFold(filter, symbol) || strings.EqualFold(filter, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func applyRolloverQuotes(plan *rolloverPlan, quotes kiteconnect.Quote) {
+	current := quotes[plan.Exchange+":"+plan.Current]
+	next := quotes[plan.Exchange+":"+plan.Next]
+
+	plan.CurrentLTP = current.LastPrice
+	plan.NextLTP = next.LastPrice
+	plan.Spread = next.LastPrice - current.LastPrice
+	plan.currentBid = current.Depth.Buy[0].Price
+	plan.currentAsk = current.Depth.Sell[0].Price
+	if plan.Quantity > 0 {
+		plan.ExecSpread = next.Depth.Sell[0].Price - plan.currentBid
+	} else {
+		plan.ExecSpread = next.Depth.Buy[0].Price - plan.currentAsk
+	}
+}
+
+// rolloverOrders returns the close and open orders for a plan. With a limit
+// spread, the close leg is priced at the touch and the open leg at that price
+// plus the spread, rounded to the tick size in the trader's favour.
+func rolloverOrders(plan rolloverPlan, useLimit bool, limitSpread float64) (kiteconnect.OrderParams, kiteconnect.OrderParams, error) {
+	qty := plan.Quantity
+	closeTxn, openTxn := kiteconnect.TransactionTypeSell, kiteconnect.TransactionTypeBuy
+	if qty < 0 {
+		qty = -qty
+		closeTxn, openTxn = kiteconnect.TransactionTypeBuy, kiteconnect.TransactionTypeSell
+	}
+
+	closeLeg := kiteconnect.OrderParams{
+		Exchange:        plan.Exchange,
+		Tradingsymbol:   plan.Current,
+		TransactionType: closeTxn,
+		OrderType:       kiteconnect.OrderTypeMarket,
+		Product:         plan.Product,
+		Quantity:        qty,
+		Validity:        kiteconnect.ValidityDay,
+	}
+	openLeg := closeLeg
+	openLeg.Tradingsymbol = plan.Next
+	openLeg.TransactionType = openTxn
+
+	if !useLimit {
+		return closeLeg, openLeg, nil
+	}
+
+	closePrice := plan.currentBid
+	if closeTxn == kiteconnect.TransactionTypeBuy {
+		closePrice = plan.currentAsk
+	}
+	if closePrice <= 0 {
+		return closeLeg, openLeg, exitcode.New(exitcode.API, fmt.Sprintf("no touch price for %s; cannot price a LIMIT roll", plan.Current))
+	}
+	closeLeg.OrderType = kiteconnect.OrderTypeLimit
+	closeLeg.Price = closePrice
+	openLeg.OrderType = kiteconnect.OrderTypeLimit
+	openLeg.Price = roundToTick(closePrice+limitSpread, plan.tickSize, openTxn == kiteconnect.TransactionTypeBuy)
+	return closeLeg, openLeg, nil
+}
+
+func roundToTick(price, tick float64, down bool) float64 {
+	if tick <= 0 {
+		return price
+	}
+	steps := price / tick
+	if down {
+		steps = math.Floor(steps + 1e-9)
+	} else {
+		steps = math.Ceil(steps - 1e-9)
+	}
+	return math.Round(steps*tick*100) / 100
+}
+
+func placeRollovers(
+	ctx *commandContext,
+	profileName string,
+	profile *config.Profile,
+	plans []rolloverPlan,
+	useLimit bool,
+	limitSpread float64,
+	tag string,
+	fillTimeout time.Duration,
+) error {
+	for i := range plans {
+		plan := &plans[i]
+		closeLeg, openLeg, err := rolloverOrders(*plan, useLimit, limitSpread)
+		if err != nil {
+			return err
+		}
+		closeLeg.Tag = tag
+		openLeg.Tag = tag
+
+		resp, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.OrderResponse, error) {
+			return client.PlaceOrder(kiteconnect.VarietyRegular, closeLeg)
+		})
+		if err != nil {
+			return exitcode.Wrap(exitcode.API, fmt.Sprintf("place close leg for %s", plan.Current), err)
+		}
+		plan.CloseOrderID = resp.OrderID
+		if err := waitForFill(ctx, profileName, profile, resp.OrderID, fillTimeout); err != nil {
+			return exitcode.Wrap(exitcode.API, fmt.Sprintf("close leg for %s (order %s); open leg for %s not placed", plan.Current, plan.CloseOrderID, plan.Next), err)
+		}
+
+		resp, err = callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.OrderResponse, error) {
+			return client.PlaceOrder(kiteconnect.VarietyRegular, openLeg)
+		})
+		if err != nil {
+			return exitcode.Wrap(exitcode.API, fmt.Sprintf("place open leg for %s after closing %s (order %s)", plan.Next, plan.Current, plan.CloseOrderID), err)
+		}
+		plan.OpenOrderID = resp.OrderID
+	}
+	return nil
+}
+
+// waitForFill polls the order until it completes. A rejected or cancelled
+// order, or one still pending at the timeout, is an error.
+func waitForFill(ctx *commandContext, profileName string, profile *config.Profile, orderID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		history, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) ([]kiteconnect.Order, error) {
+			return client.GetOrderHistory(orderID)
+		})
+		if err != nil {
+			return err
+		}
+		status := "UNKNOWN"
+		if len(history) > 0 {
+			last := history[len(history)-1]
+			status = last.Status
+			switch status {
+			case kiteconnect.OrderStatusComplete:
+				return nil
+			case kiteconnect.OrderStatusRejected, kiteconnect.OrderStatusCancelled:
+				return fmt.Errorf("order %s", strings.TrimSpace(strings.ToLower(status)+" "+last.StatusMessage))
+			}
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("order still %s after %s", status, timeout)
+		}
+		time.Sleep(min(rolloverPollInterval, remaining))
+	}
+}
+
+func printRolloverPlans(printer output.Printer, plans []rolloverPlan) error {
+	rows := make([][]string, 0, len(plans))
+	for _, plan := range plans {
+		rows = append(rows, []string{
+			plan.Current,
+			plan.Next,
+			plan.Product,
+			intToString(plan.Quantity),
+			formatFloat(plan.CurrentLTP),
+			formatFloat(plan.NextLTP),
+			formatFloat(plan.Spread),
+			formatFloat(plan.ExecSpread),
+		})
+	}
+	return printer.Table([]string{"CURRENT", "NEXT", "PRODUCT", "QTY", "CURRENT_LTP", "NEXT_LTP", "SPREAD", "EXEC_SPREAD"}, rows)
+}
+
+func dashIfEmpty(v string) string {
+	if v == "" {
+		return "-"
+	}
+	return v
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+func TestPlanRolloversPicksNextExpiry(t *testing.T) {
+	oct := time.Date(2026, 10, 27, 0, 0, 0, 0, istLocation)
+	nov := time.Date(2026, 11, 24, 0, 0, 0, 0, istLocation)
+	dec := time.Date(2026, 12, 29, 0, 0, 0, 0, istLocation)
+
+	current := testOptionContract(1, "NIFTY26OCTFUT", "NIFTY", "FUT", 0, oct)
+	next := testOptionContract(2, "NIFTY26NOVFUT", "NIFTY", "FUT", 0, nov)
+	next.TickSize = 0.1
+	far := testOptionContract(3, "NIFTY26DECFUT", "NIFTY", "FUT", 0, dec)
+	bank := testOptionContract(4, "BANKNIFTY26NOVFUT", "BANKNIFTY", "FUT", 0, nov)
+	option := testOptionContract(5, "NIFTY26OCT25000CE", "NIFTY", "CE", 25000, oct)
+	index := newInstrumentIndex(kiteconnect.Instruments{current, next, far, bank, option})
+
+	positions := []kiteconnect.Position{
+		{Tradingsymbol: "NIFTY26OCTFUT", Exchange: "NFO", Product: "NRML", Quantity: -75},
+		{Tradingsymbol: "NIFTY26OCT25000CE", Exchange: "NFO", Product: "NRML", Quantity: 75},
+		{Tradingsymbol: "NIFTY26DECFUT", Exchange: "NFO", Product: "NRML", Quantity: 75},
+		{Tradingsymbol: "BANKNIFTY26NOVFUT", Exchange: "NFO", Product: "NRML", Quantity: 0},
+	}
+	contracts := []*kiteconnect.Instrument{&current, &option, &far, &bank}
+
+	plans := planRollovers(positions, contracts, index, nil)
+	if len(plans) != 1 {
+		t.Fatalf("expected 1 plan, got %+v", plans)
+	}
+	plan := plans[0]
+	if plan.Next != "NIFTY26NOVFUT" || plan.NextExpiry != "2026-11-24" || plan.Quantity != -75 || plan.tickSize != 0.1 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+
+	if got := planRollovers(positions, contracts, index, []string{"banknifty"}); len(got) != 0 {
+		t.Fatalf("expected filter to exclude NIFTY, got %+v", got)
+	}
+	if got := planRollovers(positions, contracts, index, []string{"nifty"}); len(got) != 1 {
+		t.Fatalf("expected filter on underlying name to match, got %+v", got)
+	}
+}
+
+func TestRolloverOrders(t *testing.T) {
+	long := rolloverPlan{
+		Exchange:   "NFO",
+		Product:    "NRML",
+		Quantity:   150,
+		Current:    "NIFTY26OCTFUT",
+		Next:       "NIFTY26NOVFUT",
+		currentBid: 25010,
+		currentAsk: 25012,
+		tickSize:   0.1,
+	}
+
+	closeLeg, openLeg, err := rolloverOrders(long, false, 0)
+	if err != nil {
+		t.Fatalf("market orders: %v", err)
+	}
+	if closeLeg.TransactionType != kiteconnect.TransactionTypeSell || openLeg.TransactionType != kiteconnect.TransactionTypeBuy {
+		t.Fatalf("expected long roll to sell current and buy next, got %s/%s", closeLeg.TransactionType, openLeg.TransactionType)
+	}
+	if closeLeg.OrderType != kiteconnect.OrderTypeMarket || openLeg.Quantity != 150 || openLeg.Tradingsymbol != "NIFTY26NOVFUT" {
+		t.Fatalf("unexpected market legs: %+v %+v", closeLeg, openLeg)
+	}
+
+	closeLeg, openLeg, err = rolloverOrders(long, true, 120.55)
+	if err != nil {
+		t.Fatalf("limit orders: %v", err)
+	}
+	if closeLeg.OrderType != kiteconnect.OrderTypeLimit || closeLeg.Price != 25010 {
+		t.Fatalf("expected close LIMIT at bid, got %+v", closeLeg)
+	}
+	if math.Abs(openLeg.Price-25130.5) > 1e-9 {
+		t.Fatalf("expected open LIMIT rounded down to 25130.5, got %v", openLeg.Price)
+	}
+
+	short := long
+	short.Quantity = -75
+	closeLeg, openLeg, err = rolloverOrders(short, true, 120.55)
+	if err != nil {
+		t.Fatalf("short limit orders: %v", err)
+	}
+	if closeLeg.TransactionType != kiteconnect.TransactionTypeBuy || closeLeg.Price != 25012 || closeLeg.Quantity != 75 {
+		t.Fatalf("expected short roll to buy current at ask, got %+v", closeLeg)
+	}
+	if openLeg.TransactionType != kiteconnect.TransactionTypeSell || math.Abs(openLeg.Price-25132.6) > 1e-9 {
+		t.Fatalf("expected open SELL rounded up to 25132.6, got %+v", openLeg)
+	}
+
+	noBook := long
+	noBook.currentBid = 0
+	if _, _, err := rolloverOrders(noBook, true, 100); err == nil {
+		t.Fatalf("expected error without a touch price")
+	}
+}
+
+func TestRoundToTick(t *testing.T) {
+	tests := []struct {
+		price, tick float64
+		down        bool
+		want        float64
+	}{
+		{price: 100.07, tick: 0.05, down: true, want: 100.05},
+		{price: 100.07, tick: 0.05, down: false, want: 100.1},
+		{price: 100.1, tick: 0.05, down: false, want: 100.1},
+		{price: 100.07, tick: 0, down: true, want: 100.07},
+	}
+	for _, tc := range tests {
+		if got := roundToTick(tc.price, tc.tick, tc.down); math.Abs(got-tc.want) > 1e-9 {
+			t.Fatalf("roundToTick(%v, %v, %v): expected %v, got %v", tc.price, tc.tick, tc.down, tc.want, got)
+		}
+	}
+}
+
+func TestRolloverWaitsForCloseLegFill(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+	srv.SetOrderStatus(kiteconnect.OrderStatusComplete)
+
+	stdout, _, err := executeCLICommand(t, configPath, "positions", "rollover", "--yes")
+	if err != nil {
+		t.Fatalf("rollover: %v", err)
+	}
+	last, _ := srv.LastRequest("POST", "/orders/regular")
+	if last.Form.Get("tradingsymbol") != "NIFTY26NOVFUT" || last.Form.Get("transaction_type") != kiteconnect.TransactionTypeSell {
+		t.Fatalf("expected the open leg to be placed last, got %v", last.Form)
+	}
+	if !strings.Contains(stdout, "NIFTY26OCTFUT") || !strings.Contains(stdout, "NIFTY26NOVFUT") {
+		t.Fatalf("expected both legs in the output, got:\n%s", stdout)
+	}
+}
+
+func TestRolloverStopsWhenCloseLegDoesNotFill(t *testing.T) {
+	srv, configPath := newFakeKiteConfig(t)
+
+	stdout, stderr, err := executeCLICommand(t, configPath, "positions", "rollover", "--yes", "--fill-timeout", "50ms")
+	if err == nil || !strings.Contains(err.Error(), "open leg for NIFTY26NOVFUT not placed") || !strings.Contains(err.Error(), "still OPEN") {
+		t.Fatalf("expected the roll to stop at the unfilled close leg, got %v", err)
+	}
+
+	var placed []string
+	for _, request := range srv.Requests() {
+		if request.Method == "POST" && request.Path == "/orders/regular" {
+			placed = append(placed, request.Form.Get("tradingsymbol"))
+		}
+	}
+	if len(placed) != 1 || placed[0] != "NIFTY26OCTFUT" {
+		t.Fatalf("expected only the close leg to be placed, got %v", placed)
+	}
+	if !strings.Contains(stdout, "261016000000001") || !strings.Contains(stderr, "stopped early") {
+		t.Fatalf("expected the live close order to be reported, got stdout:\n%s\nstderr:\n%s", stdout, stderr)
+	}
+}
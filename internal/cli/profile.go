@@ -29,7 +29,7 @@ func newProfileCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			userProfile, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.UserProfile, error) {
+			userProfile, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.UserProfile, error) {
 				return client.GetUserProfile()
 			})
 			if err != nil {
@@ -69,7 +69,7 @@ func newProfileCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			userProfile, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.FullUserProfile, error) {
+			userProfile, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.FullUserProfile, error) {
 				return client.GetFullUserProfile()
 			})
 			if err != nil {
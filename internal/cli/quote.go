@@ -1,14 +1,20 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"maps"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
 	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/output"
 	"github.com/spf13/cobra"
 	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+	"github.com/zerodha/gokiteconnect/v4/models"
+	kiteticker "github.com/zerodha/gokiteconnect/v4/ticker"
 )
 
 func newQuoteCmd(opts *rootOptions) *cobra.Command {
@@ -17,59 +23,83 @@ func newQuoteCmd(opts *rootOptions) *cobra.Command {
 		Short: "Market quote utilities",
 	}
 
+	var (
+		getWide      bool
+		getWatch     time.Duration
+		getWatchlist string
+	)
 	getCmd := &cobra.Command{
-		Use:   "get <EXCHANGE:SYMBOL> [EXCHANGE:SYMBOL...]",
+		Use:   "get [EXCHANGE:SYMBOL...]",
 		Short: "Fetch snapshot quotes",
-		Args:  cobra.MinimumNArgs(1),
+		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx, err := newCommandContext(opts)
-			if err != nil {
+			if err := validateQuoteWatch(getWatch); err != nil {
 				return err
 			}
-			profileName, profile, err := ctx.resolveProfile(true)
+
+			ctx, err := newCommandContext(opts)
 			if err != nil {
 				return err
 			}
-			if err := ensureAccessToken(profile); err != nil {
+			instruments, err := ctx.quoteInstruments(args, getWatchlist)
+			if err != nil {
 				return err
 			}
-
-			quotes, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.Quote, error) {
-				return client.GetQuote(args...)
-			})
+			profileName, profile, err := ctx.resolveProfile(true)
 			if err != nil {
 				return err
 			}
-
-			printer := ctx.printer(cmd.OutOrStdout())
-			if printer.IsJSON() {
-				return printer.JSON(quotes)
-			}
-
-			keys := make([]string, 0, len(quotes))
-			for k := range quotes {
-				keys = append(keys, k)
+			if err := ensureAccessToken(profile); err != nil {
+				return err
 			}
-			sort.Strings(keys)
 
-			rows := make([][]string, 0, len(keys))
-			for _, key := range keys {
-				q := quotes[key]
-				rows = append(rows, []string{
-					key,
-					fmt.Sprintf("%.2f", q.LastPrice),
-					fmt.Sprintf("%.2f", q.NetChange),
-					fmt.Sprintf("%d", q.Volume),
-					q.Timestamp.Time.Format("2006-01-02 15:04:05"),
+			return runQuoteView(cmd, ctx, getWatch, func() (quoteView, error) {
+				quotes, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Quote, error) {
+					return client.GetQuote(instruments...)
 				})
-			}
-			return printer.Table([]string{"INSTRUMENT", "LTP", "NET_CHANGE", "VOLUME", "TIMESTAMP"}, rows)
+				if err != nil {
+					return quoteView{}, err
+				}
+
+				view := quoteView{
+					data:    quotes,
+					headers: []string{"INSTRUMENT", "LTP", "NET_CHANGE", "VOLUME", "TIMESTAMP"},
+					prices:  make(map[string]float64, len(quotes)),
+				}
+				if getWide {
+					view.headers = append(view.headers, "AVG_PRICE", "OI", "LOWER_CIRCUIT", "UPPER_CIRCUIT")
+				}
+				for _, key := range sortedKeys(quotes) {
+					q := quotes[key]
+					row := []string{
+						key,
+						fmt.Sprintf("%.2f", q.LastPrice),
+						fmt.Sprintf("%.2f", q.NetChange),
+						fmt.Sprintf("%d", q.Volume),
+						q.Timestamp.Time.Format("2006-01-02 15:04:05"),
+					}
+					if getWide {
+						row = append(row,
+							formatFloat(q.AveragePrice),
+							fmt.Sprintf("%.0f", q.OI),
+							formatFloat(q.LowerCircuitLimit),
+							formatFloat(q.UpperCircuitLimit),
+						)
+					}
+					view.rows = append(view.rows, row)
+					view.prices[key] = q.LastPrice
+				}
+				return view, nil
+			})
 		},
 	}
+	getCmd.Flags().BoolVar(&getWide, "wide", false, "Include average price, OI, and circuit limit columns")
+	bindQuoteWatchFlag(getCmd, &getWatch)
+	bindQuoteWatchlistFlag(getCmd, &getWatchlist)
 
-	ltpCmd := &cobra.Command{
-		Use:   "ltp <EXCHANGE:SYMBOL> [EXCHANGE:SYMBOL...]",
-		Short: "Fetch last traded prices",
+	depthCmd := &cobra.Command{
+		Use:   "depth <EXCHANGE:SYMBOL> [EXCHANGE:SYMBOL...]",
+		Short: "Show the 5-level market depth ladder",
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, err := newCommandContext(opts)
@@ -84,45 +114,74 @@ func newQuoteCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			quotes, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.QuoteLTP, error) {
-				return client.GetLTP(args...)
+			quotes, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Quote, error) {
+				return client.GetQuote(args...)
 			})
 			if err != nil {
 				return err
 			}
 
-			printer := ctx.printer(cmd.OutOrStdout())
-			if printer.IsJSON() {
-				return printer.JSON(quotes)
+			keys := sortedKeys(quotes)
+			ladders := make(map[string]depthLadder, len(keys))
+			for _, key := range keys {
+				q := quotes[key]
+				ladders[key] = buildDepthLadder(q.Depth, q.LastPrice, q.BuyQuantity, q.SellQuantity)
 			}
 
-			keys := make([]string, 0, len(quotes))
-			for k := range quotes {
-				keys = append(keys, k)
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(ladders)
 			}
-			sort.Strings(keys)
 
-			rows := make([][]string, 0, len(keys))
-			for _, key := range keys {
-				q := quotes[key]
-				rows = append(rows, []string{
-					key,
-					fmt.Sprintf("%.2f", q.LastPrice),
-				})
-			}
-			return printer.Table([]string{"INSTRUMENT", "LTP"}, rows)
+			for i, key := range keys {
+				ladder := ladders[key]
+				if i > 0 {
+					if _, err := fmt.Fprintln(cmd.OutOrStdout()); err != nil {
+						return err
+					}
+				}
+				if err := printer.KV([][2]string{
+					{"instrument", key},
+					{"ltp", formatFloat(ladder.LastPrice)},
+					{"spread", formatFloat(ladder.Spread)},
+					{"spread_%", formatFloat(ladder.SpreadPercent)},
+					{"total_buy_qty", intToString(ladder.TotalBuyQuantity)},
+					{"total_sell_qty", intToString(ladder.TotalSellQuantity)},
+				}); err != nil {
+					return err
+				}
+				if err := printer.Table(
+					[]string{"BID_ORDERS", "BID_QTY", "BID_CUM_QTY", "BID", "ASK", "ASK_QTY", "ASK_CUM_QTY", "ASK_ORDERS"},
+					depthLadderRows(ladder),
+				); err != nil {
+					return err
+				}
+			}
+			return nil
 		},
 	}
 
-	ohlcCmd := &cobra.Command{
-		Use:   "ohlc <EXCHANGE:SYMBOL> [EXCHANGE:SYMBOL...]",
-		Short: "Fetch OHLC snapshots",
-		Args:  cobra.MinimumNArgs(1),
+	var (
+		ltpWatch     time.Duration
+		ltpWatchlist string
+	)
+	ltpCmd := &cobra.Command{
+		Use:   "ltp [EXCHANGE:SYMBOL...]",
+		Short: "Fetch last traded prices",
+		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateQuoteWatch(ltpWatch); err != nil {
+				return err
+			}
+
 			ctx, err := newCommandContext(opts)
 			if err != nil {
 				return err
 			}
+			instruments, err := ctx.quoteInstruments(args, ltpWatchlist)
+			if err != nil {
+				return err
+			}
 			profileName, profile, err := ctx.resolveProfile(true)
 			if err != nil {
 				return err
@@ -131,58 +190,125 @@ func newQuoteCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			quotes, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) (kiteconnect.QuoteOHLC, error) {
-				return client.GetOHLC(args...)
+			return runQuoteView(cmd, ctx, ltpWatch, func() (quoteView, error) {
+				quotes, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.QuoteLTP, error) {
+					return client.GetLTP(instruments...)
+				})
+				if err != nil {
+					return quoteView{}, err
+				}
+
+				view := quoteView{
+					data:    quotes,
+					headers: []string{"INSTRUMENT", "LTP"},
+					prices:  make(map[string]float64, len(quotes)),
+				}
+				for _, key := range sortedKeys(quotes) {
+					q := quotes[key]
+					view.rows = append(view.rows, []string{
+						key,
+						fmt.Sprintf("%.2f", q.LastPrice),
+					})
+					view.prices[key] = q.LastPrice
+				}
+				return view, nil
 			})
-			if err != nil {
+		},
+	}
+	bindQuoteWatchFlag(ltpCmd, &ltpWatch)
+	bindQuoteWatchlistFlag(ltpCmd, &ltpWatchlist)
+
+	var (
+		ohlcWatch     time.Duration
+		ohlcWatchlist string
+	)
+	ohlcCmd := &cobra.Command{
+		Use:   "ohlc [EXCHANGE:SYMBOL...]",
+		Short: "Fetch OHLC snapshots",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateQuoteWatch(ohlcWatch); err != nil {
 				return err
 			}
 
-			printer := ctx.printer(cmd.OutOrStdout())
-			if printer.IsJSON() {
-				return printer.JSON(quotes)
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
 			}
-
-			keys := make([]string, 0, len(quotes))
-			for k := range quotes {
-				keys = append(keys, k)
+			instruments, err := ctx.quoteInstruments(args, ohlcWatchlist)
+			if err != nil {
+				return err
+			}
+			profileName, profile, err := ctx.resolveProfile(true)
+			if err != nil {
+				return err
+			}
+			if err := ensureAccessToken(profile); err != nil {
+				return err
 			}
-			sort.Strings(keys)
 
-			rows := make([][]string, 0, len(keys))
-			for _, key := range keys {
-				q := quotes[key]
-				rows = append(rows, []string{
-					key,
-					fmt.Sprintf("%.2f", q.OHLC.Open),
-					fmt.Sprintf("%.2f", q.OHLC.High),
-					fmt.Sprintf("%.2f", q.OHLC.Low),
-					fmt.Sprintf("%.2f", q.OHLC.Close),
-					fmt.Sprintf("%.2f", q.LastPrice),
+			return runQuoteView(cmd, ctx, ohlcWatch, func() (quoteView, error) {
+				quotes, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.QuoteOHLC, error) {
+					return client.GetOHLC(instruments...)
 				})
-			}
-			return printer.Table([]string{"INSTRUMENT", "OPEN", "HIGH", "LOW", "CLOSE", "LTP"}, rows)
+				if err != nil {
+					return quoteView{}, err
+				}
+
+				view := quoteView{
+					data:    quotes,
+					headers: []string{"INSTRUMENT", "OPEN", "HIGH", "LOW", "CLOSE", "LTP"},
+					prices:  make(map[string]float64, len(quotes)),
+				}
+				for _, key := range sortedKeys(quotes) {
+					q := quotes[key]
+					view.rows = append(view.rows, []string{
+						key,
+						fmt.Sprintf("%.2f", q.OHLC.Open),
+						fmt.Sprintf("%.2f", q.OHLC.High),
+						fmt.Sprintf("%.2f", q.OHLC.Low),
+						fmt.Sprintf("%.2f", q.OHLC.Close),
+						fmt.Sprintf("%.2f", q.LastPrice),
+					})
+					view.prices[key] = q.LastPrice
+				}
+				return view, nil
+			})
 		},
 	}
+	bindQuoteWatchFlag(ohlcCmd, &ohlcWatch)
+	bindQuoteWatchlistFlag(ohlcCmd, &ohlcWatchlist)
 
 	var (
 		hInstrumentToken int
+		hInstrument      string
 		hInterval        string
 		hFrom            string
 		hTo              string
 		hContinuous      bool
 		hOI              bool
 		hLimit           int
+		hResample        string
+		hSessionStart    string
+		hExport          string
 	)
 	historicalCmd := &cobra.Command{
 		Use:   "historical",
-		Short: "Fetch historical candles by instrument token",
+		Short: "Fetch historical candles and save them to the local candle store",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			if err := validateLimit(hLimit); err != nil {
 				return err
 			}
 
-			if hInstrumentToken <= 0 {
+			instrumentKey := strings.ToUpper(strings.TrimSpace(hInstrument))
+			switch {
+			case instrumentKey != "" && cmd.Flags().Changed("instrument-token"):
+				return exitcode.New(exitcode.Validation, "use either --instrument or --instrument-token, not both")
+			case instrumentKey != "":
+				if _, _, err := splitInstrumentKey(instrumentKey); err != nil {
+					return err
+				}
+			case hInstrumentToken <= 0:
 				return exitcode.New(exitcode.Validation, "--instrument-token must be greater than 0")
 			}
 			interval := strings.TrimSpace(hInterval)
@@ -202,6 +328,26 @@ func newQuoteCmd(opts *rootOptions) *cobra.Command {
 				return exitcode.New(exitcode.Validation, "--from must be before or equal to --to")
 			}
 
+			var resample candleResample
+			if hResample != "" {
+				resample, err = parseCandleResample(hResample, interval)
+				if err != nil {
+					return err
+				}
+			}
+			sessionStart, err := parseSessionStart(hSessionStart)
+			if err != nil {
+				return err
+			}
+			exportFile := strings.TrimSpace(hExport)
+			var exportFormat string
+			if exportFile != "" {
+				exportFormat, err = candleExportFormat(exportFile)
+				if err != nil {
+					return err
+				}
+			}
+
 			ctx, err := newCommandContext(opts)
 			if err != nil {
 				return err
@@ -214,15 +360,41 @@ func newQuoteCmd(opts *rootOptions) *cobra.Command {
 				return err
 			}
 
-			candles, err := callWithAuthRetry(ctx, profileName, profile, func(client *kiteconnect.Client) ([]kiteconnect.HistoricalData, error) {
-				return client.GetHistoricalData(hInstrumentToken, interval, from, to, hContinuous, hOI)
+			token := hInstrumentToken
+			if instrumentKey != "" {
+				instruments, _, err := resolveInstruments(ctx, profileName, profile, []string{instrumentKey})
+				if err != nil {
+					return err
+				}
+				token = instruments[0].InstrumentToken
+			}
+
+			candles, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) ([]kiteconnect.HistoricalData, error) {
+				return client.GetHistoricalData(token, interval, from, to, hContinuous, hOI)
 			})
 			if err != nil {
 				return err
 			}
+			storeCandles(cmd, token, interval, hContinuous, instrumentKey, candles)
+			if hResample != "" {
+				candles = resampleCandles(candles, resample, sessionStart)
+			}
 			candles = applyLimit(candles, hLimit)
 
 			printer := ctx.printer(cmd.OutOrStdout())
+			if exportFile != "" {
+				if err := exportCandles(exportFile, exportFormat, candles); err != nil {
+					return err
+				}
+				if printer.IsJSON() {
+					return printer.JSON(map[string]any{"file": exportFile, "format": exportFormat, "rows": len(candles)})
+				}
+				return printer.KV([][2]string{
+					{"file", exportFile},
+					{"format", exportFormat},
+					{"rows", intToString(len(candles))},
+				})
+			}
 			if printer.IsJSON() {
 				return printer.JSON(candles)
 			}
@@ -246,17 +418,530 @@ func newQuoteCmd(opts *rootOptions) *cobra.Command {
 		},
 	}
 	historicalCmd.Flags().IntVar(&hInstrumentToken, "instrument-token", 0, "Instrument token")
+	historicalCmd.Flags().StringVar(&hInstrument, "instrument", "", "Instrument as EXCHANGE:SYMBOL (alternative to --instrument-token)")
 	historicalCmd.Flags().StringVar(&hInterval, "interval", "", "Candle interval (minute, 3minute, 5minute, 10minute, 15minute, 30minute, 60minute, day)")
 	historicalCmd.Flags().StringVar(&hFrom, "from", "", "Start timestamp (YYYY-MM-DD, YYYY-MM-DD HH:MM:SS, or RFC3339)")
 	historicalCmd.Flags().StringVar(&hTo, "to", "", "End timestamp (YYYY-MM-DD, YYYY-MM-DD HH:MM:SS, or RFC3339)")
 	historicalCmd.Flags().BoolVar(&hContinuous, "continuous", false, "Set continuous=true for continuous futures data")
 	historicalCmd.Flags().BoolVar(&hOI, "oi", false, "Include open interest")
 	historicalCmd.Flags().IntVar(&hLimit, "limit", 0, "Limit number of rows (0 = no limit)")
+	historicalCmd.Flags().StringVar(&hResample, "resample", "", "Aggregate candles locally in IST (e.g. 2h, 45m, day, week, month)")
+	historicalCmd.Flags().StringVar(&hSessionStart, "session-start", defaultSessionStart, "Session open (HH:MM IST) that intraday --resample buckets are anchored to")
+	historicalCmd.Flags().StringVar(&hExport, "export", "", "Write candles to a .csv or .parquet file instead of printing them")
 
-	quoteCmd.AddCommand(getCmd, ltpCmd, ohlcCmd, historicalCmd)
+	quoteCmd.AddCommand(getCmd, depthCmd, ltpCmd, ohlcCmd, historicalCmd, newQuoteIndicatorsCmd(opts), newQuoteStreamCmd(opts))
 	return quoteCmd
 }
 
+func newQuoteStreamCmd(opts *rootOptions) *cobra.Command {
+	var (
+		streamMode      string
+		streamDuration  time.Duration
+		streamUntil     string
+		streamTicker    tickerFlags
+		streamWatchlist string
+	)
+	streamCmd := &cobra.Command{
+		Use:   "stream [EXCHANGE:SYMBOL...]",
+		Short: "Stream live market data over the Kite websocket",
+		Long: strings.Join([]string{
+			"Streams ticks for the given instruments until interrupted, --duration elapses, or --until is reached.",
+			"On a terminal the table is redrawn in place; when piped or with --json, each tick is printed as one JSON object per line.",
+		}, " "),
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mode, err := parseTickerMode(streamMode)
+			if err != nil {
+				return err
+			}
+			if err := validateTickerFlags(streamTicker); err != nil {
+				return err
+			}
+			deadline, err := streamDeadline(streamDuration, streamUntil, time.Now())
+			if err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			profileName, profile, err := ctx.resolveProfile(true)
+			if err != nil {
+				return err
+			}
+			if err := ensureAccessToken(profile); err != nil {
+				return err
+			}
+
+			keys, err := ctx.quoteInstruments(args, streamWatchlist)
+			if err != nil {
+				return err
+			}
+			instruments, _, err := resolveInstruments(ctx, profileName, profile, keys)
+			if err != nil {
+				return err
+			}
+			tokens := make([]uint32, 0, len(instruments))
+			names := make(map[uint32]string, len(instruments))
+			for _, instrument := range instruments {
+				token := uint32(instrument.InstrumentToken)
+				if _, dup := names[token]; dup {
+					continue
+				}
+				tokens = append(tokens, token)
+				names[token] = instrumentKey(instrument)
+			}
+
+			streamCtx, stop := streamContext(cmd)
+			defer stop()
+			if !deadline.IsZero() {
+				var cancel context.CancelFunc
+				streamCtx, cancel = context.WithDeadline(streamCtx, deadline)
+				defer cancel()
+			}
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			live := !printer.IsJSON() && output.IsTerminal(cmd.OutOrStdout())
+			session := newTickerSession(*profile, streamTicker, cmd.ErrOrStderr())
+			latest := make(map[uint32]models.Tick, len(tokens))
+			dirty := false
+			var writeErr error
+			session.ticker.OnTick(func(tick models.Tick) {
+				session.do(func() {
+					if !live {
+						if err := printer.NDJSON(newStreamTick(names[tick.InstrumentToken], tick)); err != nil {
+							writeErr = err
+							stop()
+						}
+						return
+					}
+					latest[tick.InstrumentToken] = tick
+					dirty = true
+				})
+			})
+
+			if live {
+				go func() {
+					refresh := time.NewTicker(500 * time.Millisecond)
+					defer refresh.Stop()
+					for {
+						select {
+						case <-streamCtx.Done():
+							return
+						case <-refresh.C:
+							session.do(func() {
+								if !dirty {
+									return
+								}
+								dirty = false
+								if err := printer.ClearScreen(); err == nil {
+									_ = printer.Table(streamTableHeaders(mode), streamTableRows(mode, tokens, names, latest))
+								}
+							})
+						}
+					}
+				}()
+			}
+
+			err = session.run(streamCtx, func(t *kiteticker.Ticker) error {
+				if err := t.Subscribe(tokens); err != nil {
+					return err
+				}
+				return t.SetMode(mode, tokens)
+			})
+			if err != nil {
+				return err
+			}
+			return writeErr
+		},
+	}
+	streamCmd.Flags().StringVar(&streamMode, "mode", string(kiteticker.ModeQuote), "Tick mode (ltp, quote, full)")
+	streamCmd.Flags().DurationVar(&streamDuration, "duration", 0, "Stop streaming after this long, e.g. 30s or 15m (0 = no limit)")
+	streamCmd.Flags().StringVar(&streamUntil, "until", "", "Stop streaming at this time (HH:MM IST today, YYYY-MM-DD HH:MM:SS IST, or RFC3339)")
+	bindTickerFlags(streamCmd, &streamTicker)
+	bindQuoteWatchlistFlag(streamCmd, &streamWatchlist)
+
+	return streamCmd
+}
+
+func parseTickerMode(raw string) (kiteticker.Mode, error) {
+	switch mode := kiteticker.Mode(strings.ToLower(strings.TrimSpace(raw))); mode {
+	case kiteticker.ModeLTP, kiteticker.ModeQuote, kiteticker.ModeFull:
+		return mode, nil
+	default:
+		return "", exitcode.New(exitcode.Validation, "invalid --mode; use ltp, quote, or full")
+	}
+}
+
+func streamDeadline(duration time.Duration, until string, now time.Time) (time.Time, error) {
+	until = strings.TrimSpace(until)
+	if duration < 0 {
+		return time.Time{}, exitcode.New(exitcode.Validation, "--duration cannot be negative")
+	}
+	if duration > 0 && until != "" {
+		return time.Time{}, exitcode.New(exitcode.Validation, "--duration and --until cannot be used together")
+	}
+	if duration > 0 {
+		return now.Add(duration), nil
+	}
+	if until == "" {
+		return time.Time{}, nil
+	}
+
+	var deadline time.Time
+	if clock, err := time.ParseInLocation("15:04", until, istLocation); err == nil {
+		today := now.In(istLocation)
+		deadline = time.Date(today.Year(), today.Month(), today.Day(), clock.Hour(), clock.Minute(), 0, 0, istLocation)
+	} else if t, err := time.ParseInLocation("2006-01-02 15:04:05", until, istLocation); err == nil {
+		deadline = t
+	} else if t, err := time.Parse(time.RFC3339, until); err == nil {
+		deadline = t
+	} else {
+		return time.Time{}, exitcode.New(exitcode.Validation, "--until has invalid format; use HH:MM, YYYY-MM-DD HH:MM:SS, or RFC3339")
+	}
+	if !deadline.After(now) {
+		return time.Time{}, exitcode.New(exitcode.Validation, "--until must be in the future")
+	}
+	return deadline, nil
+}
+
+type streamTick struct {
+	Instrument        string        `json:"instrument"`
+	InstrumentToken   uint32        `json:"instrument_token"`
+	Mode              string        `json:"mode"`
+	Timestamp         *time.Time    `json:"timestamp,omitempty"`
+	LastPrice         float64       `json:"last_price"`
+	NetChange         float64       `json:"net_change"`
+	Volume            uint32        `json:"volume,omitempty"`
+	AveragePrice      float64       `json:"average_price,omitempty"`
+	TotalBuyQuantity  uint32        `json:"total_buy_quantity,omitempty"`
+	TotalSellQuantity uint32        `json:"total_sell_quantity,omitempty"`
+	OI                uint32        `json:"oi,omitempty"`
+	OHLC              *models.OHLC  `json:"ohlc,omitempty"`
+	Depth             *models.Depth `json:"depth,omitempty"`
+}
+
+func newStreamTick(name string, tick models.Tick) streamTick {
+	record := streamTick{
+		Instrument:        name,
+		InstrumentToken:   tick.InstrumentToken,
+		Mode:              tick.Mode,
+		LastPrice:         tick.LastPrice,
+		NetChange:         tick.NetChange,
+		Volume:            tick.VolumeTraded,
+		AveragePrice:      tick.AverageTradePrice,
+		TotalBuyQuantity:  tick.TotalBuyQuantity,
+		TotalSellQuantity: tick.TotalSellQuantity,
+		OI:                tick.OI,
+	}
+	if !tick.Timestamp.Time.IsZero() {
+		record.Timestamp = &tick.Timestamp.Time
+	}
+	if tick.Mode != string(kiteticker.ModeLTP) {
+		record.OHLC = &tick.OHLC
+	}
+	if tick.Mode == string(kiteticker.ModeFull) && !tick.IsIndex {
+		record.Depth = &tick.Depth
+	}
+	return record
+}
+
+func streamTableHeaders(mode kiteticker.Mode) []string {
+	switch mode {
+	case kiteticker.ModeLTP:
+		return []string{"INSTRUMENT", "LTP"}
+	case kiteticker.ModeQuote:
+		return []string{"INSTRUMENT", "LTP", "NET_CHANGE", "OPEN", "HIGH", "LOW", "CLOSE", "VOLUME", "BUY_QTY", "SELL_QTY"}
+	default:
+		return []string{"INSTRUMENT", "LTP", "NET_CHANGE", "OPEN", "HIGH", "LOW", "CLOSE", "VOLUME", "OI", "BID", "ASK", "TIMESTAMP"}
+	}
+}
+
+func streamTableRows(mode kiteticker.Mode, tokens []uint32, names map[uint32]string, latest map[uint32]models.Tick) [][]string {
+	rows := make([][]string, 0, len(tokens))
+	for _, token := range tokens {
+		tick, ok := latest[token]
+		if !ok {
+			continue
+		}
+		row := []string{names[token], formatFloat(tick.LastPrice)}
+		if mode == kiteticker.ModeLTP {
+			rows = append(rows, row)
+			continue
+		}
+		row = append(row,
+			formatFloat(tick.NetChange),
+			formatFloat(tick.OHLC.Open),
+			formatFloat(tick.OHLC.High),
+			formatFloat(tick.OHLC.Low),
+			formatFloat(tick.OHLC.Close),
+			fmt.Sprintf("%d", tick.VolumeTraded),
+		)
+		if mode == kiteticker.ModeQuote {
+			row = append(row, fmt.Sprintf("%d", tick.TotalBuyQuantity), fmt.Sprintf("%d", tick.TotalSellQuantity))
+		} else {
+			row = append(row,
+				fmt.Sprintf("%d", tick.OI),
+				formatFloat(tick.Depth.Buy[0].Price),
+				formatFloat(tick.Depth.Sell[0].Price),
+				formatModelTime(tick.Timestamp.Time),
+			)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+const quoteWatchMinInterval = time.Second
+
+type quoteView struct {
+	data    any
+	headers []string
+	rows    [][]string
+	prices  map[string]float64
+}
+
+func bindQuoteWatchFlag(cmd *cobra.Command, watch *time.Duration) {
+	cmd.Flags().DurationVar(watch, "watch", 0, "Refresh every interval (e.g. 2s), highlighting up/down ticks; minimum 1s")
+}
+
+// validateQuoteWatch keeps polling within Kite's quote API rate limit of one
+// request per second.
+func validateQuoteWatch(watch time.Duration) error {
+	if watch < 0 {
+		return exitcode.New(exitcode.Validation, "--watch cannot be negative")
+	}
+	if watch > 0 && watch < quoteWatchMinInterval {
+		return exitcode.New(exitcode.Validation, fmt.Sprintf("--watch must be at least %s to respect the quote API rate limit", quoteWatchMinInterval))
+	}
+	return nil
+}
+
+// runQuoteView renders one snapshot, or with watch > 0 keeps re-fetching on
+// a ticker until interrupted. Network errors while watching are reported and
+// retried on the next tick; anything else ends the loop.
+func runQuoteView(cmd *cobra.Command, ctx *commandContext, watch time.Duration, fetch func() (quoteView, error)) error {
+	printer := ctx.printer(cmd.OutOrStdout())
+	if watch == 0 {
+		view, err := fetch()
+		if err != nil {
+			return err
+		}
+		if printer.IsJSON() {
+			return printer.JSON(view.data)
+		}
+		return printer.Table(view.headers, view.rows)
+	}
+
+	streamCtx, stop := streamContext(cmd)
+	defer stop()
+
+	live := !printer.IsJSON() && output.IsTerminal(cmd.OutOrStdout())
+	refresh := time.NewTicker(watch)
+	defer refresh.Stop()
+
+	var previous map[string]float64
+	for first := true; ; first = false {
+		view, err := fetch()
+		switch {
+		case err == nil:
+			if err := renderQuoteWatch(cmd, printer, view, previous, live, first, watch); err != nil {
+				return err
+			}
+			previous = view.prices
+		case exitcode.Code(err) == exitcode.Network:
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "quote: %v\n", err)
+		default:
+			return err
+		}
+
+		select {
+		case <-streamCtx.Done():
+			return nil
+		case <-refresh.C:
+		}
+	}
+}
+
+func renderQuoteWatch(
+	cmd *cobra.Command,
+	printer output.Printer,
+	view quoteView,
+	previous map[string]float64,
+	live bool,
+	first bool,
+	watch time.Duration,
+) error {
+	if printer.IsJSON() {
+		return printer.NDJSON(view.data)
+	}
+
+	headers := append(append([]string(nil), view.headers...), "TICK")
+	rows := make([][]string, 0, len(view.rows))
+	for _, row := range view.rows {
+		direction := tickDirection(previous, view.prices, row[0])
+		row = append(append([]string(nil), row...), tickMarker(direction))
+		if live {
+			row = output.HighlightRow(row, direction)
+		}
+		rows = append(rows, row)
+	}
+
+	out := cmd.OutOrStdout()
+	if live {
+		headers = output.HighlightRow(headers, 0)
+		if err := printer.ClearScreen(); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(out, "Updated %s (every %s, Ctrl-C to stop)\n\n", time.Now().Format("15:04:05"), watch); err != nil {
+			return err
+		}
+	} else if !first {
+		if _, err := fmt.Fprintln(out); err != nil {
+			return err
+		}
+	}
+	return printer.Table(headers, rows)
+}
+
+// tickDirection compares a key's price against the previous snapshot:
+// 1 for an up-tick, -1 for a down-tick, 0 when unchanged or unknown.
+func tickDirection(previous, current map[string]float64, key string) int {
+	before, ok := previous[key]
+	if !ok {
+		return 0
+	}
+	switch after := current[key]; {
+	case after > before:
+		return 1
+	case after < before:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func tickMarker(direction int) string {
+	switch direction {
+	case 1:
+		return "▲"
+	case -1:
+		return "▼"
+	default:
+		return "-"
+	}
+}
+
+// quoteBatchSize is the most instruments the quote API accepts in one call.
+const quoteBatchSize = 500
+
+// fetchQuotesBatched requests quotes in chunks of quoteBatchSize, pausing
+// between chunks to stay within the quote API rate limit.
+func fetchQuotesBatched(
+	ctx *commandContext,
+	profileName string,
+	profile *config.Profile,
+	keys []string,
+) (kiteconnect.Quote, error) {
+	quotes := make(kiteconnect.Quote, len(keys))
+	for start := 0; start < len(keys); start += quoteBatchSize {
+		if start > 0 {
+			time.Sleep(quoteWatchMinInterval)
+		}
+		batch := keys[start:min(start+quoteBatchSize, len(keys))]
+		result, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) (kiteconnect.Quote, error) {
+			return client.GetQuote(batch...)
+		})
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(quotes, result)
+	}
+	return quotes, nil
+}
+
+func sortedKeys[M ~map[string]V, V any](m M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type depthLevel struct {
+	BidOrders      uint32  `json:"bid_orders"`
+	BidQuantity    uint32  `json:"bid_quantity"`
+	BidCumQuantity uint64  `json:"bid_cum_quantity"`
+	Bid            float64 `json:"bid"`
+	Ask            float64 `json:"ask"`
+	AskQuantity    uint32  `json:"ask_quantity"`
+	AskCumQuantity uint64  `json:"ask_cum_quantity"`
+	AskOrders      uint32  `json:"ask_orders"`
+}
+
+type depthLadder struct {
+	LastPrice         float64      `json:"last_price"`
+	Spread            float64      `json:"spread"`
+	SpreadPercent     float64      `json:"spread_pct"`
+	TotalBuyQuantity  int          `json:"total_buy_quantity"`
+	TotalSellQuantity int          `json:"total_sell_quantity"`
+	Levels            []depthLevel `json:"levels"`
+}
+
+// buildDepthLadder pairs bid and ask levels with running quantity totals.
+// Spread is only reported when both sides have a best price.
+func buildDepthLadder(depth models.Depth, lastPrice float64, totalBuy, totalSell int) depthLadder {
+	ladder := depthLadder{
+		LastPrice:         lastPrice,
+		TotalBuyQuantity:  totalBuy,
+		TotalSellQuantity: totalSell,
+		Levels:            make([]depthLevel, 0, len(depth.Buy)),
+	}
+
+	var bidCum, askCum uint64
+	for i := range depth.Buy {
+		bid, ask := depth.Buy[i], depth.Sell[i]
+		bidCum += uint64(bid.Quantity)
+		askCum += uint64(ask.Quantity)
+		ladder.Levels = append(ladder.Levels, depthLevel{
+			BidOrders:      bid.Orders,
+			BidQuantity:    bid.Quantity,
+			BidCumQuantity: bidCum,
+			Bid:            bid.Price,
+			Ask:            ask.Price,
+			AskQuantity:    ask.Quantity,
+			AskCumQuantity: askCum,
+			AskOrders:      ask.Orders,
+		})
+	}
+
+	bestBid, bestAsk := depth.Buy[0].Price, depth.Sell[0].Price
+	if bestBid > 0 && bestAsk > 0 {
+		ladder.Spread = bestAsk - bestBid
+		ladder.SpreadPercent = ladder.Spread / ((bestAsk + bestBid) / 2) * 100
+	}
+	return ladder
+}
+
+func depthLadderRows(ladder depthLadder) [][]string {
+	rows := make([][]string, 0, len(ladder.Levels))
+	for _, level := range ladder.Levels {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", level.BidOrders),
+			fmt.Sprintf("%d", level.BidQuantity),
+			fmt.Sprintf("%d", level.BidCumQuantity),
+			formatFloat(level.Bid),
+			formatFloat(level.Ask),
+			fmt.Sprintf("%d", level.AskQuantity),
+			fmt.Sprintf("%d", level.AskCumQuantity),
+			fmt.Sprintf("%d", level.AskOrders),
+		})
+	}
+	return rows
+}
+
 func parseHistoricalTime(raw string, flag string) (time.Time, error) {
 	value := strings.TrimSpace(raw)
 	if value == "" {
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"math"
+	"strings"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/indicators"
+	"github.com/spf13/cobra"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+)
+
+type indicatorRow struct {
+	kiteconnect.HistoricalData
+	Indicators map[string]*float64 `json:"indicators"`
+}
+
+func newQuoteIndicatorsCmd(opts *rootOptions) *cobra.Command {
+	var (
+		indInstrument string
+		indInterval   string
+		indFrom       string
+		indTo         string
+		indSpecs      string
+		indContinuous bool
+	)
+	indicatorsCmd := &cobra.Command{
+		Use:   "indicators",
+		Short: "Compute technical indicators over historical candles",
+		Long: strings.Join([]string{
+			"Fetches historical candles for an EXCHANGE:SYMBOL instrument and computes indicators locally.",
+			"--ind takes a comma-separated list of sma:N, ema:N, rsi:N, macd[:FAST:SLOW:SIGNAL], bbands[:N:K], atr:N and vwap.",
+			"Indicators need history to warm up, so the first rows of each column are empty; widen --from to cover the longest period.",
+			"VWAP resets at the start of each trading day.",
+		}, " "),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			instrumentKey := strings.TrimSpace(indInstrument)
+			if instrumentKey == "" {
+				return exitcode.New(exitcode.Validation, "--instrument is required")
+			}
+			if _, _, err := splitInstrumentKey(instrumentKey); err != nil {
+				return err
+			}
+			interval := strings.TrimSpace(indInterval)
+			if interval == "" {
+				return exitcode.New(exitcode.Validation, "--interval is required")
+			}
+			specs, err := indicators.ParseSpecs(indSpecs)
+			if err != nil {
+				return exitcode.Wrap(exitcode.Validation, "invalid --ind", err)
+			}
+			from, err := parseHistoricalTime(indFrom, "--from")
+			if err != nil {
+				return err
+			}
+			to, err := parseHistoricalTime(indTo, "--to")
+			if err != nil {
+				return err
+			}
+			if from.After(to) {
+				return exitcode.New(exitcode.Validation, "--from must be before or equal to --to")
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			profileName, profile, err := ctx.resolveProfile(true)
+			if err != nil {
+				return err
+			}
+			if err := ensureAccessToken(profile); err != nil {
+				return err
+			}
+
+			instruments, _, err := resolveInstruments(ctx, profileName, profile, []string{instrumentKey})
+			if err != nil {
+				return err
+			}
+			candles, err := callWithAuthRetry(ctx, profileName, profile, func(client kiteAPI) ([]kiteconnect.HistoricalData, error) {
+				return client.GetHistoricalData(instruments[0].InstrumentToken, interval, from, to, indContinuous, false)
+			})
+			if err != nil {
+				return err
+			}
+			storeCandles(cmd, instruments[0].InstrumentToken, interval, indContinuous, strings.ToUpper(instrumentKey), candles)
+
+			columns := indicators.Compute(specs, indicatorCandles(candles))
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(indicatorRows(candles, columns))
+			}
+
+			headers := []string{"DATE", "OPEN", "HIGH", "LOW", "CLOSE", "VOLUME"}
+			for _, column := range columns {
+				headers = append(headers, strings.ToUpper(column.Name))
+			}
+			rows := make([][]string, 0, len(candles))
+			for i, candle := range candles {
+				row := []string{
+					candle.Date.Time.Format("2006-01-02 15:04:05"),
+					formatFloat(candle.Open),
+					formatFloat(candle.High),
+					formatFloat(candle.Low),
+					formatFloat(candle.Close),
+					intToString(candle.Volume),
+				}
+				for _, column := range columns {
+					row = append(row, formatIndicatorValue(column.Values[i]))
+				}
+				rows = append(rows, row)
+			}
+			if len(rows) == 0 {
+				row := []string{"-", "0.00", "0.00", "0.00", "0.00", "0"}
+				for range columns {
+					row = append(row, "-")
+				}
+				rows = append(rows, row)
+			}
+			return printer.Table(headers, rows)
+		},
+	}
+	indicatorsCmd.Flags().StringVar(&indInstrument, "instrument", "", "Instrument as EXCHANGE:SYMBOL")
+	indicatorsCmd.Flags().StringVar(&indInterval, "interval", "", "Candle interval (minute, 3minute, 5minute, 10minute, 15minute, 30minute, 60minute, day)")
+	indicatorsCmd.Flags().StringVar(&indFrom, "from", "", "Start timestamp (YYYY-MM-DD, YYYY-MM-DD HH:MM:SS, or RFC3339)")
+	indicatorsCmd.Flags().StringVar(&indTo, "to", "", "End timestamp (YYYY-MM-DD, YYYY-MM-DD HH:MM:SS, or RFC3339)")
+	indicatorsCmd.Flags().StringVar(&indSpecs, "ind", "", "Indicators, e.g. sma:20,ema:50,rsi:14,macd,bbands:20:2,atr:14,vwap")
+	indicatorsCmd.Flags().BoolVar(&indContinuous, "continuous", false, "Set continuous=true for continuous futures data")
+	return indicatorsCmd
+}
+
+func indicatorCandles(candles []kiteconnect.HistoricalData) []indicators.Candle {
+	out := make([]indicators.Candle, len(candles))
+	for i, candle := range candles {
+		out[i] = indicators.Candle{
+			Time:   candle.Date.Time,
+			Open:   candle.Open,
+			High:   candle.High,
+			Low:    candle.Low,
+			Close:  candle.Close,
+			Volume: float64(candle.Volume),
+		}
+	}
+	return out
+}
+
+func indicatorRows(candles []kiteconnect.HistoricalData, columns []indicators.Column) []indicatorRow {
+	rows := make([]indicatorRow, len(candles))
+	for i, candle := range candles {
+		values := make(map[string]*float64, len(columns))
+		for _, column := range columns {
+			if v := column.Values[i]; !math.IsNaN(v) {
+				values[column.Name] = &v
+			} else {
+				values[column.Name] = nil
+			}
+		}
+		rows[i] = indicatorRow{HistoricalData: candle, Indicators: values}
+	}
+	return rows
+}
+
+func formatIndicatorValue(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+	return formatFloat(v)
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/indicators"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+	"github.com/zerodha/gokiteconnect/v4/models"
+)
+
+func TestIndicatorRowsMarksWarmupAsNull(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, istLocation)
+	var candles []kiteconnect.HistoricalData
+	for i, price := range []float64{100, 102, 104} {
+		candles = append(candles, kiteconnect.HistoricalData{
+			Date:   models.Time{Time: day.AddDate(0, 0, i)},
+			High:   price,
+			Low:    price,
+			Close:  price,
+			Volume: 10,
+		})
+	}
+	specs, err := indicators.ParseSpecs("sma:2")
+	if err != nil {
+		t.Fatalf("parse specs: %v", err)
+	}
+	rows := indicatorRows(candles, indicators.Compute(specs, indicatorCandles(candles)))
+
+	if rows[0].Indicators["sma_2"] != nil {
+		t.Fatalf("expected warm-up value to be nil, got %v", *rows[0].Indicators["sma_2"])
+	}
+	if got := rows[2].Indicators["sma_2"]; got == nil || *got != 103 {
+		t.Fatalf("expected sma_2 103, got %v", got)
+	}
+
+	data, err := json.Marshal(rows[0])
+	if err != nil {
+		t.Fatalf("marshal row: %v", err)
+	}
+	if text := string(data); !strings.Contains(text, `"indicators":{"sma_2":null}`) || !strings.Contains(text, `"close":100`) {
+		t.Fatalf("unexpected JSON row: %s", text)
+	}
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	kiteconnect "github.com/zerodha/gokiteconnect/v4"
+	"github.com/zerodha/gokiteconnect/v4/models"
+)
+
+func TestStreamDeadline(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, istLocation)
+
+	tests := []struct {
+		name     string
+		duration time.Duration
+		until    string
+		want     time.Time
+		errMatch string
+	}{
+		{name: "no limit"},
+		{name: "duration", duration: 90 * time.Second, want: now.Add(90 * time.Second)},
+		{name: "clock time", until: "15:30", want: time.Date(2026, 3, 2, 15, 30, 0, 0, istLocation)},
+		{name: "timestamp", until: "2026-03-03 09:15:00", want: time.Date(2026, 3, 3, 9, 15, 0, 0, istLocation)},
+		{name: "both", duration: time.Minute, until: "15:30", errMatch: "cannot be used together"},
+		{name: "past", until: "09:00", errMatch: "must be in the future"},
+		{name: "garbage", until: "later", errMatch: "invalid format"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := streamDeadline(tc.duration, tc.until, now)
+			if tc.errMatch != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errMatch) {
+					t.Fatalf("expected error containing %q, got %v", tc.errMatch, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tc.want) {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestQuoteStreamSubscribesResolvedTokens(t *testing.T) {
+	seedInstrumentCache(t, "NSE", kiteconnect.Instruments{
+		{InstrumentToken: 408065, Tradingsymbol: "INFY", Exchange: "NSE"},
+	})
+
+	subscribed := make(chan string, 4)
+	tickerURL := newFakeTickerServer(t, func(conn *websocket.Conn) {
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			subscribed <- string(msg)
+			if strings.Contains(string(msg), `"a":"mode"`) {
+				_ = conn.WriteMessage(websocket.BinaryMessage, ltpPacket(408065, 1523.45))
+			}
+		}
+	})
+
+	stdout, _, err := executeCLICommand(
+		t,
+		writeTestConfigWithToken(t),
+		"quote",
+		"stream",
+		"NSE:INFY",
+		"--mode",
+		"ltp",
+		"--duration",
+		"500ms",
+		"--ticker-url",
+		tickerURL,
+	)
+	if err != nil {
+		t.Fatalf("quote stream failed: %v", err)
+	}
+
+	if first := <-subscribed; first != `{"a":"subscribe","v":[408065]}` {
+		t.Fatalf("unexpected subscribe message %q", first)
+	}
+	if second := <-subscribed; second != `{"a":"mode","v":["ltp",[408065]]}` {
+		t.Fatalf("unexpected mode message %q", second)
+	}
+
+	var tick streamTick
+	if err := json.Unmarshal([]byte(strings.TrimSpace(stdout)), &tick); err != nil {
+		t.Fatalf("decode tick %q: %v", stdout, err)
+	}
+	if tick.Instrument != "NSE:INFY" || tick.LastPrice != 1523.45 || tick.Mode != "ltp" {
+		t.Fatalf("unexpected tick %+v", tick)
+	}
+}
+
+func TestQuoteStreamRejectsUnknownMode(t *testing.T) {
+	_, _, err := executeCLICommand(t, writeTestConfigWithToken(t), "quote", "stream", "NSE:INFY", "--mode", "depth")
+	if err == nil || !strings.Contains(err.Error(), "invalid --mode") {
+		t.Fatalf("expected invalid --mode error, got %v", err)
+	}
+}
+
+func TestBuildDepthLadderAccumulatesQuantitiesAndSpread(t *testing.T) {
+	var depth models.Depth
+	for i := range depth.Buy {
+		depth.Buy[i] = models.DepthItem{Price: 100 - float64(i)*0.05, Quantity: uint32(10 * (i + 1)), Orders: uint32(i + 1)}
+		depth.Sell[i] = models.DepthItem{Price: 100.1 + float64(i)*0.05, Quantity: uint32(5 * (i + 1)), Orders: 1}
+	}
+
+	ladder := buildDepthLadder(depth, 100.05, 1500, 900)
+	if len(ladder.Levels) != 5 {
+		t.Fatalf("expected 5 levels, got %d", len(ladder.Levels))
+	}
+	if got := ladder.Levels[4].BidCumQuantity; got != 150 {
+		t.Fatalf("expected cumulative bid quantity 150, got %d", got)
+	}
+	if got := ladder.Levels[2].AskCumQuantity; got != 30 {
+		t.Fatalf("expected cumulative ask quantity 30 at level 3, got %d", got)
+	}
+	if diff := ladder.Spread - 0.1; diff > 1e-9 || diff < -1e-9 {
+		t.Fatalf("expected spread 0.10, got %f", ladder.Spread)
+	}
+	if ladder.SpreadPercent <= 0.0999 || ladder.SpreadPercent >= 0.1001 {
+		t.Fatalf("expected spread ~0.1%%, got %f", ladder.SpreadPercent)
+	}
+	if got := depthLadderRows(ladder)[0]; strings.Join(got, "|") != "1|10|10|100.00|100.10|5|5|1" {
+		t.Fatalf("unexpected first row %v", got)
+	}
+}
+
+func TestBuildDepthLadderOmitsSpreadForOneSidedBook(t *testing.T) {
+	var depth models.Depth
+	depth.Buy[0] = models.DepthItem{Price: 250, Quantity: 40, Orders: 2}
+
+	ladder := buildDepthLadder(depth, 250, 40, 0)
+	if ladder.Spread != 0 || ladder.SpreadPercent != 0 {
+		t.Fatalf("expected no spread for one-sided book, got %f (%f%%)", ladder.Spread, ladder.SpreadPercent)
+	}
+}
+
+func TestQuoteWatchRejectsIntervalsBelowRateLimit(t *testing.T) {
+	for _, sub := range []string{"get", "ltp", "ohlc"} {
+		t.Run(sub, func(t *testing.T) {
+			_, _, err := executeCLICommand(t, writeTestConfigWithToken(t), "quote", sub, "NSE:INFY", "--watch", "500ms")
+			if err == nil || !strings.Contains(err.Error(), "--watch must be at least 1s") {
+				t.Fatalf("expected rate limit validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTickDirectionComparesAgainstPreviousSnapshot(t *testing.T) {
+	previous := map[string]float64{"NSE:INFY": 1500, "NSE:TCS": 4000, "NSE:SBIN": 800}
+	current := map[string]float64{"NSE:INFY": 1501.5, "NSE:TCS": 3999.95, "NSE:SBIN": 800, "NSE:HDFCBANK": 1700}
+
+	tests := map[string]int{
+		"NSE:INFY":     1,
+		"NSE:TCS":      -1,
+		"NSE:SBIN":     0,
+		"NSE:HDFCBANK": 0,
+	}
+	for key, want := range tests {
+		if got := tickDirection(previous, current, key); got != want {
+			t.Fatalf("%s: expected direction %d, got %d", key, want, got)
+		}
+	}
+	if got := tickDirection(nil, current, "NSE:INFY"); got != 0 {
+		t.Fatalf("expected no direction without a previous snapshot, got %d", got)
+	}
+}
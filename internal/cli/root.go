@@ -14,6 +14,15 @@ type rootOptions struct {
 	configPath string
 	outputJSON bool
 	debug      bool
+	paper      bool
+	keyFile    string
+
+	allProfiles bool
+	profiles    []string
+
+	// origins records whether each global flag came from the command line,
+	// the environment or its default.
+	origins map[string]string
 }
 
 func Execute() error {
@@ -32,16 +41,27 @@ func newRootCmd() *cobra.Command {
 	rootCmd.RunE = func(cmd *cobra.Command, _ []string) error {
 		return cmd.Help()
 	}
-	rootCmd.PersistentPreRun = func(cmd *cobra.Command, _ []string) {
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		if err := applyEnvOverrides(cmd.Flags(), opts); err != nil {
+			return err
+		}
+		if err := validateFanOutFlags(cmd, opts); err != nil {
+			return err
+		}
 		if shouldRunAutoUpdate(cmd) {
 			startAutoUpdate()
 		}
+		return nil
 	}
 
-	rootCmd.PersistentFlags().StringVar(&opts.profile, "profile", "", "Profile name (defaults to active profile)")
-	rootCmd.PersistentFlags().StringVar(&opts.configPath, "config", defaultConfigPath, "Path to config file")
-	rootCmd.PersistentFlags().BoolVar(&opts.outputJSON, "json", false, "Render output as JSON")
-	rootCmd.PersistentFlags().BoolVar(&opts.debug, "debug", false, "Enable SDK HTTP debug logs")
+	rootCmd.PersistentFlags().StringVar(&opts.profile, "profile", "", "Profile name (defaults to $ZERODHA_PROFILE, then the active profile)")
+	rootCmd.PersistentFlags().StringVar(&opts.configPath, "config", defaultConfigPath, "Path to config file ($ZERODHA_CONFIG)")
+	rootCmd.PersistentFlags().BoolVar(&opts.outputJSON, "json", false, "Render output as JSON ($ZERODHA_OUTPUT=json)")
+	rootCmd.PersistentFlags().BoolVar(&opts.debug, "debug", false, "Enable SDK HTTP debug logs ($ZERODHA_DEBUG)")
+	rootCmd.PersistentFlags().StringVar(&opts.keyFile, "key-file", "", "File holding the passphrase for an encrypted config ($ZERODHA_KEY_FILE)")
+	rootCmd.PersistentFlags().BoolVar(&opts.allProfiles, "all-profiles", false, "Run a read command for every profile concurrently")
+	rootCmd.PersistentFlags().StringSliceVar(&opts.profiles, "profiles", nil, "Run a read command for these profiles concurrently (comma-separated)")
+	rootCmd.PersistentFlags().BoolVar(&opts.paper, "paper", false, "Route orders, positions and holdings to the local paper-trading account ($ZERODHA_PAPER)")
 
 	rootCmd.AddCommand(
 		newSelfUpdateApplyCmd(),
@@ -59,6 +79,11 @@ func newRootCmd() *cobra.Command {
 		newPositionsCmd(opts),
 		newHoldingsCmd(opts),
 		newMarginsCmd(opts),
+		newWatchlistCmd(opts),
+		newOptionsCmd(opts),
+		newBacktestCmd(opts),
+		newPaperCmd(opts),
+		newPortfolioCmd(opts),
 	)
 
 	return rootCmd
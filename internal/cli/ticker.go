@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/config"
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/spf13/cobra"
+	kiteticker "github.com/zerodha/gokiteconnect/v4/ticker"
+)
+
+const defaultTickerMaxRetries = 20
+
+type tickerFlags struct {
+	url        string
+	maxRetries int
+}
+
+func bindTickerFlags(cmd *cobra.Command, flags *tickerFlags) {
+	cmd.Flags().IntVar(&flags.maxRetries, "max-retries", defaultTickerMaxRetries, "Reconnect attempts before giving up (delay doubles per attempt, capped at 60s)")
+	cmd.Flags().StringVar(&flags.url, "ticker-url", "", "Override the websocket ticker URL")
+	_ = cmd.Flags().MarkHidden("ticker-url")
+}
+
+func validateTickerFlags(flags tickerFlags) error {
+	if flags.maxRetries < 0 {
+		return exitcode.New(exitcode.Validation, fmt.Sprintf("invalid --max-retries %d: must be >= 0", flags.maxRetries))
+	}
+	if raw := strings.TrimSpace(flags.url); raw != "" {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+			return exitcode.New(exitcode.Validation, "--ticker-url must be a ws:// or wss:// URL")
+		}
+	}
+	return nil
+}
+
+// tickerSession wraps a kiteticker.Ticker so commands can stream until
+// interrupted without racing the SDK's callback goroutines on shutdown.
+type tickerSession struct {
+	ticker *kiteticker.Ticker
+	errOut io.Writer
+
+	mu      sync.Mutex
+	conn    io.Closer
+	stopped bool
+}
+
+func newTickerSession(profile config.Profile, flags tickerFlags, errOut io.Writer) *tickerSession {
+	ticker := kiteticker.New(profile.APIKey, profile.AccessToken)
+	if raw := strings.TrimSpace(flags.url); raw != "" {
+		if u, err := url.Parse(raw); err == nil {
+			ticker.SetRootURL(*u)
+		}
+	}
+	// The SDK dereferences a nil connection when a dial fails with auto
+	// reconnect disabled, so retries are always enabled and bounded instead.
+	ticker.SetAutoReconnect(true)
+	ticker.SetReconnectMaxRetries(flags.maxRetries)
+
+	return &tickerSession{ticker: ticker, errOut: errOut}
+}
+
+// do runs fn while holding the session lock, unless the session has stopped.
+// Callbacks use it to serialize writes and drop events that arrive late.
+func (s *tickerSession) do(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	fn()
+}
+
+func (s *tickerSession) logf(format string, args ...any) {
+	s.do(func() {
+		_, _ = fmt.Fprintf(s.errOut, "ticker: "+format+"\n", args...)
+	})
+}
+
+// run connects and blocks until ctx is done or reconnect attempts run out.
+// onConnect is invoked after every (re)connect, e.g. to subscribe tokens.
+func (s *tickerSession) run(ctx context.Context, onConnect func(*kiteticker.Ticker) error) error {
+	failed := make(chan error, 1)
+	fail := func(err error) {
+		select {
+		case failed <- err:
+		default:
+		}
+	}
+
+	s.ticker.OnConnect(func() {
+		s.mu.Lock()
+		s.conn = s.ticker.Conn
+		s.mu.Unlock()
+		if onConnect != nil {
+			if err := onConnect(s.ticker); err != nil {
+				fail(err)
+			}
+		}
+	})
+	s.ticker.OnError(func(err error) {
+		s.logf("%v", err)
+	})
+	s.ticker.OnReconnect(func(attempt int, delay time.Duration) {
+		s.logf("reconnecting (attempt %d) in %s", attempt, delay)
+	})
+	s.ticker.OnNoReconnect(func(attempt int) {
+		fail(fmt.Errorf("giving up after %d reconnect attempts", attempt-1))
+	})
+
+	serveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.ticker.ServeWithContext(serveCtx)
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-failed:
+	}
+	s.stop()
+
+	if err != nil {
+		return exitcode.Wrap(exitcode.Network, "ticker connection failed", err)
+	}
+	return nil
+}
+
+func (s *tickerSession) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+	if s.conn != nil {
+		_ = s.conn.Close()
+	}
+}
+
+// streamContext returns a context cancelled on Ctrl-C/SIGTERM so streaming
+// commands exit cleanly.
+func streamContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	parent := cmd.Context()
+	if parent == nil {
+		parent = context.Background()
+	}
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+}
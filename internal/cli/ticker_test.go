@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// newFakeTickerServer starts a websocket server that hands each accepted
+// connection to handle. The returned URL can be passed via --ticker-url.
+func newFakeTickerServer(t *testing.T, handle func(conn *websocket.Conn)) string {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("api_key") == "" || r.URL.Query().Get("access_token") == "" {
+			http.Error(w, "missing credentials", http.StatusForbidden)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handle(conn)
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// ltpPacket encodes a single LTP-mode tick in the Kite binary frame format.
+func ltpPacket(token uint32, lastPrice float64) []byte {
+	frame := make([]byte, 2+2+8)
+	binary.BigEndian.PutUint16(frame[0:2], 1)
+	binary.BigEndian.PutUint16(frame[2:4], 8)
+	binary.BigEndian.PutUint32(frame[4:8], token)
+	binary.BigEndian.PutUint32(frame[8:12], uint32(lastPrice*100))
+	return frame
+}
+
+func TestValidateTickerFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    tickerFlags
+		errMatch string
+	}{
+		{name: "defaults", flags: tickerFlags{maxRetries: defaultTickerMaxRetries}},
+		{name: "websocket url", flags: tickerFlags{url: "ws://127.0.0.1:9000/"}},
+		{name: "negative retries", flags: tickerFlags{maxRetries: -1}, errMatch: "--max-retries"},
+		{name: "http url", flags: tickerFlags{url: "http://127.0.0.1:9000/"}, errMatch: "--ticker-url"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateTickerFlags(tc.flags)
+			if tc.errMatch == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errMatch) {
+				t.Fatalf("expected error containing %q, got %v", tc.errMatch, err)
+			}
+		})
+	}
+}
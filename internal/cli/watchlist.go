@@ -0,0 +1,622 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jatinbansal1998/zerodha-kite-cli/internal/exitcode"
+	"github.com/spf13/cobra"
+)
+
+const (
+	watchlistScopeGlobal  = "global"
+	watchlistScopeProfile = "profile"
+
+	watchlistFormatText = "text"
+	watchlistFormatCSV  = "csv"
+)
+
+func newWatchlistCmd(opts *rootOptions) *cobra.Command {
+	watchlistCmd := &cobra.Command{
+		Use:   "watchlist",
+		Short: "Manage named instrument watchlists",
+		Long: strings.Join([]string{
+			"Watchlists are named lists of EXCHANGE:SYMBOL instruments stored in the config file.",
+			"Global watchlists are shared by all profiles; --scope profile stores a list on the selected profile.",
+			"When resolving --watchlist, a profile watchlist takes precedence over a global one with the same name.",
+		}, " "),
+	}
+
+	var createScope string
+	createCmd := &cobra.Command{
+		Use:   "create <name> [EXCHANGE:SYMBOL...]",
+		Short: "Create a watchlist, optionally seeded with instruments",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := watchlistName(args[0])
+			if err != nil {
+				return err
+			}
+			instruments, err := normalizeWatchlistInstruments(args[1:])
+			if err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			lists, commit, err := ctx.watchlistScope(createScope)
+			if err != nil {
+				return err
+			}
+			if _, exists := lists[name]; exists {
+				return exitcode.New(exitcode.Validation, fmt.Sprintf("watchlist %q already exists", name))
+			}
+			lists[name] = instruments
+			if err := commit(lists); err != nil {
+				return err
+			}
+
+			return printWatchlistResult(ctx, cmd, name, createScope, lists[name])
+		},
+	}
+	bindWatchlistScopeFlag(createCmd, &createScope)
+
+	var addScope string
+	addCmd := &cobra.Command{
+		Use:   "add <name> <EXCHANGE:SYMBOL> [EXCHANGE:SYMBOL...]",
+		Short: "Add instruments to a watchlist",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := watchlistName(args[0])
+			if err != nil {
+				return err
+			}
+			instruments, err := normalizeWatchlistInstruments(args[1:])
+			if err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			lists, commit, err := ctx.watchlistScope(addScope)
+			if err != nil {
+				return err
+			}
+			existing, ok := lists[name]
+			if !ok {
+				return exitcode.New(exitcode.Config, fmt.Sprintf("watchlist %q not found", name))
+			}
+			lists[name] = mergeWatchlist(existing, instruments)
+			if err := commit(lists); err != nil {
+				return err
+			}
+
+			return printWatchlistResult(ctx, cmd, name, addScope, lists[name])
+		},
+	}
+	bindWatchlistScopeFlag(addCmd, &addScope)
+
+	var removeScope string
+	removeCmd := &cobra.Command{
+		Use:   "remove <name> <EXCHANGE:SYMBOL> [EXCHANGE:SYMBOL...]",
+		Short: "Remove instruments from a watchlist",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := watchlistName(args[0])
+			if err != nil {
+				return err
+			}
+			instruments, err := normalizeWatchlistInstruments(args[1:])
+			if err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			lists, commit, err := ctx.watchlistScope(removeScope)
+			if err != nil {
+				return err
+			}
+			existing, ok := lists[name]
+			if !ok {
+				return exitcode.New(exitcode.Config, fmt.Sprintf("watchlist %q not found", name))
+			}
+			lists[name] = slices.DeleteFunc(slices.Clone(existing), func(instrument string) bool {
+				return slices.Contains(instruments, instrument)
+			})
+			if err := commit(lists); err != nil {
+				return err
+			}
+
+			return printWatchlistResult(ctx, cmd, name, removeScope, lists[name])
+		},
+	}
+	bindWatchlistScopeFlag(removeCmd, &removeScope)
+
+	var deleteScope string
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a watchlist",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := watchlistName(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			lists, commit, err := ctx.watchlistScope(deleteScope)
+			if err != nil {
+				return err
+			}
+			if _, ok := lists[name]; !ok {
+				return exitcode.New(exitcode.Config, fmt.Sprintf("watchlist %q not found", name))
+			}
+			delete(lists, name)
+			if err := commit(lists); err != nil {
+				return err
+			}
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(map[string]string{
+					"status":  "ok",
+					"deleted": name,
+					"scope":   deleteScope,
+				})
+			}
+			return printer.KV([][2]string{
+				{"status", "ok"},
+				{"deleted", name},
+				{"scope", deleteScope},
+			})
+		},
+	}
+	bindWatchlistScopeFlag(deleteCmd, &deleteScope)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List watchlists from the global and selected profile scopes",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			profileName, profile, err := ctx.resolveProfile(false)
+			if err != nil {
+				return err
+			}
+
+			type item struct {
+				Name        string `json:"name"`
+				Scope       string `json:"scope"`
+				Profile     string `json:"profile,omitempty"`
+				Instruments int    `json:"instruments"`
+			}
+			items := make([]item, 0, len(ctx.cfg.Watchlists))
+			for _, name := range sortedKeys(ctx.cfg.Watchlists) {
+				items = append(items, item{Name: name, Scope: watchlistScopeGlobal, Instruments: len(ctx.cfg.Watchlists[name])})
+			}
+			if profile != nil {
+				for _, name := range sortedKeys(profile.Watchlists) {
+					items = append(items, item{Name: name, Scope: watchlistScopeProfile, Profile: profileName, Instruments: len(profile.Watchlists[name])})
+				}
+			}
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(items)
+			}
+			if len(items) == 0 {
+				_, err := fmt.Fprintln(cmd.OutOrStdout(), "No watchlists found. Create one with `zerodha watchlist create <name> EXCHANGE:SYMBOL ...`.")
+				return err
+			}
+
+			rows := make([][]string, 0, len(items))
+			for _, it := range items {
+				scope := it.Scope
+				if it.Profile != "" {
+					scope += ":" + it.Profile
+				}
+				rows = append(rows, []string{it.Name, scope, intToString(it.Instruments)})
+			}
+			return printer.Table([]string{"WATCHLIST", "SCOPE", "INSTRUMENTS"}, rows)
+		},
+	}
+
+	showCmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show the instruments in a watchlist",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := watchlistName(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			instruments, scope, err := ctx.lookupWatchlist(name)
+			if err != nil {
+				return err
+			}
+
+			printer := ctx.printer(cmd.OutOrStdout())
+			if printer.IsJSON() {
+				return printer.JSON(map[string]any{
+					"name":        name,
+					"scope":       scope,
+					"instruments": instruments,
+				})
+			}
+
+			rows := make([][]string, 0, len(instruments))
+			for _, instrument := range instruments {
+				exchange, symbol, _ := strings.Cut(instrument, ":")
+				rows = append(rows, []string{instrument, exchange, symbol})
+			}
+			if len(rows) == 0 {
+				rows = append(rows, []string{"-", "-", "-"})
+			}
+			return printer.Table([]string{"INSTRUMENT", "EXCHANGE", "SYMBOL"}, rows)
+		},
+	}
+
+	var (
+		importScope   string
+		importFile    string
+		importFormat  string
+		importReplace bool
+	)
+	importCmd := &cobra.Command{
+		Use:   "import <name> --file <path>",
+		Short: "Import instruments from a text or CSV file (use - for stdin)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := watchlistName(args[0])
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(importFile) == "" {
+				return exitcode.New(exitcode.Validation, "--file is required")
+			}
+			format, err := watchlistFormat(importFormat, importFile)
+			if err != nil {
+				return err
+			}
+
+			var in io.Reader = cmd.InOrStdin()
+			if importFile != "-" {
+				f, err := os.Open(importFile)
+				if err != nil {
+					return exitcode.Wrap(exitcode.Validation, "open watchlist file", err)
+				}
+				defer f.Close()
+				in = f
+			}
+			instruments, err := readWatchlist(in, format)
+			if err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			lists, commit, err := ctx.watchlistScope(importScope)
+			if err != nil {
+				return err
+			}
+			if importReplace {
+				lists[name] = instruments
+			} else {
+				lists[name] = mergeWatchlist(lists[name], instruments)
+			}
+			if err := commit(lists); err != nil {
+				return err
+			}
+
+			return printWatchlistResult(ctx, cmd, name, importScope, lists[name])
+		},
+	}
+	bindWatchlistScopeFlag(importCmd, &importScope)
+	importCmd.Flags().StringVar(&importFile, "file", "", "Input file path, or - for stdin")
+	importCmd.Flags().StringVar(&importFormat, "format", "", "Input format (text, csv); inferred from the file extension when omitted")
+	importCmd.Flags().BoolVar(&importReplace, "replace", false, "Replace the watchlist instead of merging into it")
+
+	var (
+		exportFile   string
+		exportFormat string
+	)
+	exportCmd := &cobra.Command{
+		Use:   "export <name>",
+		Short: "Export a watchlist as plain text or CSV",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := watchlistName(args[0])
+			if err != nil {
+				return err
+			}
+			format, err := watchlistFormat(exportFormat, exportFile)
+			if err != nil {
+				return err
+			}
+
+			ctx, err := newCommandContext(opts)
+			if err != nil {
+				return err
+			}
+			instruments, _, err := ctx.lookupWatchlist(name)
+			if err != nil {
+				return err
+			}
+
+			if exportFile == "" || exportFile == "-" {
+				return writeWatchlist(cmd.OutOrStdout(), instruments, format)
+			}
+			f, err := os.OpenFile(exportFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				return exitcode.Wrap(exitcode.Validation, "create watchlist file", err)
+			}
+			if err := writeWatchlist(f, instruments, format); err != nil {
+				_ = f.Close()
+				return err
+			}
+			return f.Close()
+		},
+	}
+	exportCmd.Flags().StringVar(&exportFile, "file", "", "Output file path (defaults to stdout)")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "Output format (text, csv); inferred from the file extension when omitted")
+
+	watchlistCmd.AddCommand(createCmd, addCmd, removeCmd, deleteCmd, listCmd, showCmd, importCmd, exportCmd)
+	return watchlistCmd
+}
+
+func bindWatchlistScopeFlag(cmd *cobra.Command, scope *string) {
+	cmd.Flags().StringVar(scope, "scope", watchlistScopeGlobal, "Where the watchlist is stored (global, profile)")
+}
+
+func printWatchlistResult(ctx *commandContext, cmd *cobra.Command, name, scope string, instruments []string) error {
+	printer := ctx.printer(cmd.OutOrStdout())
+	if printer.IsJSON() {
+		return printer.JSON(map[string]any{
+			"status":      "ok",
+			"name":        name,
+			"scope":       scope,
+			"instruments": instruments,
+		})
+	}
+	return printer.KV([][2]string{
+		{"status", "ok"},
+		{"watchlist", name},
+		{"scope", scope},
+		{"instruments", intToString(len(instruments))},
+	})
+}
+
+// watchlistScope returns a mutable copy of the watchlists in scope together
+// with a commit function that stores them back and saves the config.
+func (c *commandContext) watchlistScope(scope string) (map[string][]string, func(map[string][]string) error, error) {
+	switch strings.ToLower(strings.TrimSpace(scope)) {
+	case watchlistScopeGlobal:
+		lists := cloneWatchlists(c.cfg.Watchlists)
+		return lists, func(updated map[string][]string) error {
+			c.cfg.Watchlists = updated
+			return c.save()
+		}, nil
+	case watchlistScopeProfile:
+		profileName, profile, err := c.resolveProfile(true)
+		if err != nil {
+			return nil, nil, err
+		}
+		lists := cloneWatchlists(profile.Watchlists)
+		return lists, func(updated map[string][]string) error {
+			profile.Watchlists = updated
+			c.setProfile(profileName, *profile)
+			return c.save()
+		}, nil
+	default:
+		return nil, nil, exitcode.New(exitcode.Validation, "invalid --scope; use global or profile")
+	}
+}
+
+// lookupWatchlist resolves a watchlist by name, preferring the selected
+// profile's lists over global ones.
+func (c *commandContext) lookupWatchlist(name string) ([]string, string, error) {
+	if _, profile, err := c.resolveProfile(false); err == nil && profile != nil {
+		if instruments, ok := profile.Watchlists[name]; ok {
+			return instruments, watchlistScopeProfile, nil
+		}
+	}
+	if instruments, ok := c.cfg.Watchlists[name]; ok {
+		return instruments, watchlistScopeGlobal, nil
+	}
+	return nil, "", exitcode.New(exitcode.Config, fmt.Sprintf("watchlist %q not found", name))
+}
+
+// quoteInstruments combines positional instruments with a --watchlist.
+func (c *commandContext) quoteInstruments(args []string, watchlist string) ([]string, error) {
+	instruments := slices.Clone(args)
+	if name := strings.TrimSpace(watchlist); name != "" {
+		listed, _, err := c.lookupWatchlist(name)
+		if err != nil {
+			return nil, err
+		}
+		instruments = mergeWatchlist(instruments, listed)
+	}
+	if len(instruments) == 0 {
+		return nil, exitcode.New(exitcode.Validation, "pass at least one EXCHANGE:SYMBOL or --watchlist")
+	}
+	return instruments, nil
+}
+
+func bindQuoteWatchlistFlag(cmd *cobra.Command, watchlist *string) {
+	cmd.Flags().StringVar(watchlist, "watchlist", "", "Include instruments from a named watchlist")
+}
+
+func cloneWatchlists(lists map[string][]string) map[string][]string {
+	cloned := make(map[string][]string, len(lists))
+	for name, instruments := range lists {
+		cloned[name] = slices.Clone(instruments)
+	}
+	return cloned
+}
+
+func watchlistName(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		return "", exitcode.New(exitcode.Validation, "watchlist name cannot be empty")
+	}
+	return name, nil
+}
+
+func normalizeWatchlistInstruments(raw []string) ([]string, error) {
+	instruments := make([]string, 0, len(raw))
+	for _, key := range raw {
+		exchange, symbol, err := splitInstrumentKey(key)
+		if err != nil {
+			return nil, err
+		}
+		instruments = append(instruments, exchange+":"+strings.ToUpper(symbol))
+	}
+	return mergeWatchlist(nil, instruments), nil
+}
+
+// mergeWatchlist appends additions that are not already present, keeping the
+// existing order.
+func mergeWatchlist(existing, additions []string) []string {
+	merged := slices.Clone(existing)
+	if merged == nil {
+		merged = []string{}
+	}
+	for _, instrument := range additions {
+		if !slices.Contains(merged, instrument) {
+			merged = append(merged, instrument)
+		}
+	}
+	return merged
+}
+
+func watchlistFormat(raw, path string) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(raw))
+	if format == "" {
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			return watchlistFormatCSV, nil
+		}
+		return watchlistFormatText, nil
+	}
+	if format != watchlistFormatText && format != watchlistFormatCSV {
+		return "", exitcode.New(exitcode.Validation, "invalid --format; use text or csv")
+	}
+	return format, nil
+}
+
+// readWatchlist parses one EXCHANGE:SYMBOL per line for text input (blank
+// lines and # comments are skipped). CSV input needs either an
+// exchange,tradingsymbol header pair or an instrument column.
+func readWatchlist(in io.Reader, format string) ([]string, error) {
+	var raw []string
+	if format == watchlistFormatText {
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			raw = append(raw, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, exitcode.Wrap(exitcode.Validation, "read watchlist", err)
+		}
+		return normalizeWatchlistInstruments(raw)
+	}
+
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return []string{}, nil
+		}
+		return nil, exitcode.Wrap(exitcode.Validation, "read watchlist csv", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	exchangeCol, hasExchange := columns["exchange"]
+	symbolCol, hasSymbol := columns["tradingsymbol"]
+	if !hasSymbol {
+		symbolCol, hasSymbol = columns["symbol"]
+	}
+	instrumentCol, hasInstrument := columns["instrument"]
+	if !(hasExchange && hasSymbol) && !hasInstrument {
+		return nil, exitcode.New(exitcode.Validation, "watchlist csv needs exchange and tradingsymbol columns, or an instrument column")
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, exitcode.Wrap(exitcode.Validation, "read watchlist csv", err)
+		}
+		field := func(i int) string {
+			if i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if hasExchange && hasSymbol {
+			raw = append(raw, field(exchangeCol)+":"+field(symbolCol))
+		} else {
+			raw = append(raw, field(instrumentCol))
+		}
+	}
+	return normalizeWatchlistInstruments(raw)
+}
+
+func writeWatchlist(out io.Writer, instruments []string, format string) error {
+	if format == watchlistFormatText {
+		for _, instrument := range instruments {
+			if _, err := fmt.Fprintln(out, instrument); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	writer := csv.NewWriter(out)
+	if err := writer.Write([]string{"exchange", "tradingsymbol"}); err != nil {
+		return err
+	}
+	for _, instrument := range instruments {
+		exchange, symbol, _ := strings.Cut(instrument, ":")
+		if err := writer.Write([]string{exchange, symbol}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWatchlistLifecycleGlobalAndProfileScopes(t *testing.T) {
+	configPath := writeTestConfigWithToken(t)
+
+	if _, _, err := executeCLICommand(t, configPath, "watchlist", "create", "banks", "nse:hdfcbank", "NSE:ICICIBANK", "NSE:HDFCBANK"); err != nil {
+		t.Fatalf("create watchlist: %v", err)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "watchlist", "add", "banks", "NSE:SBIN", "NSE:ICICIBANK"); err != nil {
+		t.Fatalf("add to watchlist: %v", err)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "watchlist", "remove", "banks", "NSE:HDFCBANK"); err != nil {
+		t.Fatalf("remove from watchlist: %v", err)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "watchlist", "create", "banks", "--scope", "profile", "NSE:AXISBANK"); err != nil {
+		t.Fatalf("create profile watchlist: %v", err)
+	}
+
+	cfg := loadTestConfig(t, configPath)
+	if got, want := cfg.Watchlists["banks"], []string{"NSE:ICICIBANK", "NSE:SBIN"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected global watchlist %v, got %v", want, got)
+	}
+	if got, want := cfg.Profiles["default"].Watchlists["banks"], []string{"NSE:AXISBANK"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected profile watchlist %v, got %v", want, got)
+	}
+	if cfg.Profiles["default"].AccessToken != "test_access_token" {
+		t.Fatalf("expected profile credentials to be preserved, got %+v", cfg.Profiles["default"])
+	}
+
+	ctx, err := newCommandContext(&rootOptions{configPath: configPath})
+	if err != nil {
+		t.Fatalf("new command context: %v", err)
+	}
+	instruments, scope, err := ctx.lookupWatchlist("banks")
+	if err != nil {
+		t.Fatalf("lookup watchlist: %v", err)
+	}
+	if scope != watchlistScopeProfile || !reflect.DeepEqual(instruments, []string{"NSE:AXISBANK"}) {
+		t.Fatalf("expected profile watchlist to take precedence, got %s %v", scope, instruments)
+	}
+
+	if _, _, err := executeCLICommand(t, configPath, "watchlist", "create", "banks"); err == nil {
+		t.Fatalf("expected duplicate create to fail")
+	}
+	if _, _, err := executeCLICommand(t, configPath, "watchlist", "delete", "banks", "--scope", "profile"); err != nil {
+		t.Fatalf("delete profile watchlist: %v", err)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "watchlist", "add", "missing", "NSE:INFY"); err == nil {
+		t.Fatalf("expected add to a missing watchlist to fail")
+	}
+}
+
+func TestQuoteInstrumentsMergesArgsWithWatchlist(t *testing.T) {
+	configPath := writeTestConfigWithToken(t)
+	if _, _, err := executeCLICommand(t, configPath, "watchlist", "create", "it", "NSE:INFY", "NSE:TCS"); err != nil {
+		t.Fatalf("create watchlist: %v", err)
+	}
+
+	ctx, err := newCommandContext(&rootOptions{configPath: configPath})
+	if err != nil {
+		t.Fatalf("new command context: %v", err)
+	}
+
+	got, err := ctx.quoteInstruments([]string{"NSE:WIPRO", "NSE:INFY"}, "it")
+	if err != nil {
+		t.Fatalf("quote instruments: %v", err)
+	}
+	if want := []string{"NSE:WIPRO", "NSE:INFY", "NSE:TCS"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if _, err := ctx.quoteInstruments(nil, ""); err == nil {
+		t.Fatalf("expected an error without instruments or watchlist")
+	}
+	if _, err := ctx.quoteInstruments(nil, "missing"); err == nil {
+		t.Fatalf("expected an error for an unknown watchlist")
+	}
+}
+
+func TestReadWatchlistFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		want   []string
+	}{
+		{
+			name:   "text with comments",
+			format: watchlistFormatText,
+			input:  "# banks\nnse:sbin\n\nNSE:HDFCBANK\nNSE:SBIN\n",
+			want:   []string{"NSE:SBIN", "NSE:HDFCBANK"},
+		},
+		{
+			name:   "csv exchange and symbol",
+			format: watchlistFormatCSV,
+			input:  "exchange,tradingsymbol\nNSE,INFY\nBSE,TCS\n",
+			want:   []string{"NSE:INFY", "BSE:TCS"},
+		},
+		{
+			name:   "csv instrument column",
+			format: watchlistFormatCSV,
+			input:  "instrument,note\nNSE:INFY,core\n",
+			want:   []string{"NSE:INFY"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := readWatchlist(strings.NewReader(tc.input), tc.format)
+			if err != nil {
+				t.Fatalf("read watchlist: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+
+	if _, err := readWatchlist(strings.NewReader("name\nfoo\n"), watchlistFormatCSV); err == nil {
+		t.Fatalf("expected csv without instrument columns to fail")
+	}
+	if _, err := readWatchlist(strings.NewReader("INFY\n"), watchlistFormatText); err == nil {
+		t.Fatalf("expected instrument without exchange to fail")
+	}
+}
+
+func TestWatchlistImportExportRoundTrip(t *testing.T) {
+	configPath := writeTestConfigWithToken(t)
+	dir := t.TempDir()
+
+	source := filepath.Join(dir, "fno.csv")
+	if err := os.WriteFile(source, []byte("exchange,tradingsymbol\nNFO,NIFTY26OCTFUT\nNSE,INFY\n"), 0o600); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	if _, _, err := executeCLICommand(t, configPath, "watchlist", "import", "fno", "--file", source); err != nil {
+		t.Fatalf("import watchlist: %v", err)
+	}
+
+	stdout, _, err := executeCLICommand(t, configPath, "watchlist", "export", "fno")
+	if err != nil {
+		t.Fatalf("export watchlist: %v", err)
+	}
+	if want := "NFO:NIFTY26OCTFUT\nNSE:INFY\n"; stdout != want {
+		t.Fatalf("expected text export %q, got %q", want, stdout)
+	}
+
+	target := filepath.Join(dir, "out.csv")
+	if _, _, err := executeCLICommand(t, configPath, "watchlist", "export", "fno", "--file", target); err != nil {
+		t.Fatalf("export watchlist csv: %v", err)
+	}
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if !bytes.Equal(data, []byte("exchange,tradingsymbol\nNFO,NIFTY26OCTFUT\nNSE,INFY\n")) {
+		t.Fatalf("unexpected csv export %q", data)
+	}
+}
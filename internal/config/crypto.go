@@ -0,0 +1,178 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	encryptionKDF   = "scrypt"
+	encryptedPrefix = "enc:v1:"
+	checkPlaintext  = "zerodha-config"
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	// ErrPassphraseRequired is returned when an encrypted config is loaded
+	// without a passphrase source.
+	ErrPassphraseRequired = errors.New("config is encrypted; a passphrase is required")
+	// ErrWrongPassphrase is returned when the passphrase does not unlock the config.
+	ErrWrongPassphrase = errors.New("wrong passphrase for encrypted config")
+)
+
+// Encryption records how the secret profile fields are encrypted. Only the
+// KDF parameters and a check value are stored; never the key.
+type Encryption struct {
+	KDF   string `json:"kdf"`
+	Salt  string `json:"salt"`
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	Check string `json:"check"`
+}
+
+// PassphraseFunc supplies the passphrase for an encrypted config.
+type PassphraseFunc func() ([]byte, error)
+
+type sealer struct {
+	params Encryption
+	aead   cipher.AEAD
+}
+
+func newSealer(passphrase []byte) (*sealer, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	params := Encryption{
+		KDF:  encryptionKDF,
+		Salt: base64.StdEncoding.EncodeToString(salt),
+		N:    scryptN,
+		R:    scryptR,
+		P:    scryptP,
+	}
+	s, err := deriveSealer(passphrase, params)
+	if err != nil {
+		return nil, err
+	}
+	check, err := s.seal(checkPlaintext, "check")
+	if err != nil {
+		return nil, err
+	}
+	s.params.Check = check
+	return s, nil
+}
+
+func openSealer(passphrase []byte, params Encryption) (*sealer, error) {
+	s, err := deriveSealer(passphrase, params)
+	if err != nil {
+		return nil, err
+	}
+	if check, err := s.open(params.Check, "check"); err != nil || check != checkPlaintext {
+		return nil, ErrWrongPassphrase
+	}
+	return s, nil
+}
+
+func deriveSealer(passphrase []byte, params Encryption) (*sealer, error) {
+	if params.KDF != encryptionKDF {
+		return nil, fmt.Errorf("unsupported config encryption kdf %q", params.KDF)
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase cannot be empty")
+	}
+	salt, err := base64.StdEncoding.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("decode config salt: %w", err)
+	}
+	key, err := scrypt.Key(passphrase, salt, params.N, params.R, params.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("derive config key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &sealer{params: params, aead: aead}, nil
+}
+
+// seal encrypts value, binding it to label so ciphertexts cannot be swapped
+// between fields or profiles.
+func (s *sealer) seal(value, label string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(value), []byte(label))
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *sealer) open(value, label string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	encoded, ok := strings.CutPrefix(value, encryptedPrefix)
+	if !ok {
+		// Secrets written before encryption was enabled stay readable.
+		return value, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(data) < s.aead.NonceSize() {
+		return "", fmt.Errorf("decode encrypted %s", label)
+	}
+	nonce, ciphertext := data[:s.aead.NonceSize()], data[s.aead.NonceSize():]
+	plain, err := s.aead.Open(nil, nonce, ciphertext, []byte(label))
+	if err != nil {
+		return "", fmt.Errorf("decrypt %s: %w", label, err)
+	}
+	return string(plain), nil
+}
+
+// sealProfiles returns a copy of profiles with secrets encrypted.
+func (s *sealer) sealProfiles(profiles map[string]Profile) (map[string]Profile, error) {
+	return s.mapSecrets(profiles, s.seal)
+}
+
+func (s *sealer) openProfiles(profiles map[string]Profile) (map[string]Profile, error) {
+	return s.mapSecrets(profiles, s.open)
+}
+
+func (s *sealer) mapSecrets(profiles map[string]Profile, fn func(value, label string) (string, error)) (map[string]Profile, error) {
+	out := make(map[string]Profile, len(profiles))
+	for name, profile := range profiles {
+		for field, value := range profile.secretFields() {
+			result, err := fn(*value, "profile/"+name+"/"+field)
+			if err != nil {
+				return nil, err
+			}
+			*value = result
+		}
+		out[name] = profile
+	}
+	return out, nil
+}
+
+func (p *Profile) secretFields() map[string]*string {
+	return map[string]*string{
+		"api_secret":    &p.APISecret,
+		"access_token":  &p.AccessToken,
+		"refresh_token": &p.RefreshToken,
+	}
+}
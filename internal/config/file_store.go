@@ -6,11 +6,32 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"syscall"
 )
 
 type FileStore struct {
-	path string
+	path       string
+	passphrase PassphraseFunc
+	sealer     *sealer
+	// chosen is set once EnableEncryption or DisableEncryption picks how this
+	// process saves; until then saves follow the file as last read under the
+	// lock, so another process's encrypt or decrypt is not undone.
+	chosen bool
+	// opened is the sealer that last decrypted the file; re-reads reuse it
+	// rather than asking for the passphrase again.
+	opened *sealer
+
+	// mu serializes WithLock within the process; the file lock only keeps
+	// other processes out.
+	mu sync.Mutex
+}
+
+// LockedStore is the view of a FileStore handed to a WithLock callback. Its
+// methods assume the lock is held, so they must not be used after the
+// callback returns.
+type LockedStore struct {
+	s *FileStore
 }
 
 func NewFileStore(path string) *FileStore {
@@ -21,55 +42,287 @@ func (s *FileStore) Path() string {
 	return s.path
 }
 
+// SetPassphraseFunc sets where Load gets the passphrase for an encrypted
+// config. It is only called when the file is encrypted.
+func (s *FileStore) SetPassphraseFunc(fn PassphraseFunc) {
+	s.passphrase = fn
+}
+
+// Encrypted reports whether Save encrypts profile secrets.
+func (s *FileStore) Encrypted() bool {
+	return s.sealer != nil
+}
+
+// EnableEncryption derives a new key from passphrase with a fresh salt; the
+// next Save writes profile secrets encrypted with it.
+func (s *FileStore) EnableEncryption(passphrase []byte) error {
+	sealer, err := newSealer(passphrase)
+	if err != nil {
+		return err
+	}
+	s.sealer, s.chosen = sealer, true
+	return nil
+}
+
+// DisableEncryption makes the next Save write secrets in plaintext.
+func (s *FileStore) DisableEncryption() {
+	s.sealer, s.chosen = nil, true
+}
+
 func (s *FileStore) Load() (Config, error) {
-	if err := s.ensureFile(); err != nil {
+	cfg, opened, err := s.read(false)
+	if err != nil {
 		return Config{}, err
 	}
+	s.opened = opened
+	if !s.chosen {
+		s.sealer = opened
+	}
+	return cfg, nil
+}
+
+// WithLock runs fn while holding an exclusive advisory lock shared by every
+// process using this config file. fn reads and updates the config through
+// the LockedStore it is given; calling the store's own Update from fn would
+// deadlock.
+func (s *FileStore) WithLock(fn func(LockedStore) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureDir(); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("open config lock: %w", err)
+	}
+	defer f.Close()
+	if err := lockFile(f); err != nil {
+		return fmt.Errorf("lock config: %w", err)
+	}
+	defer func() { _ = unlockFile(f) }()
+
+	return fn(LockedStore{s: s})
+}
+
+// Update re-reads the config under the lock, lets fn change it and saves the
+// result, so concurrent read-modify-write cycles do not lose each other's
+// changes.
+func (s *FileStore) Update(fn func(*Config) error) (Config, error) {
+	var cfg Config
+	err := s.WithLock(func(locked LockedStore) error {
+		var err error
+		cfg, err = locked.Update(fn)
+		return err
+	})
+	return cfg, err
+}
+
+// Update is FileStore.Update for a caller that already holds the lock.
+func (l LockedStore) Update(fn func(*Config) error) (Config, error) {
+	latest, err := l.Read()
+	if err != nil {
+		return Config{}, err
+	}
+	if err := fn(&latest); err != nil {
+		return Config{}, err
+	}
+	if err := l.s.Save(latest); err != nil {
+		return Config{}, err
+	}
+	return latest, nil
+}
+
+// Read is FileStore.Read for a caller that already holds the lock.
+func (l LockedStore) Read() (Config, error) {
+	return l.s.readAndRemember(true)
+}
+
+// Read returns the config as currently on disk without changing how the
+// store encrypts.
+func (s *FileStore) Read() (Config, error) {
+	return s.readAndRemember(false)
+}
+
+func (s *FileStore) readAndRemember(locked bool) (Config, error) {
+	cfg, opened, err := s.read(locked)
+	if err != nil {
+		return Config{}, err
+	}
+	if opened != nil {
+		s.opened = opened
+	}
+	// Under the lock the file cannot change before the save that follows.
+	if locked && !s.chosen {
+		s.sealer = opened
+	}
+	return cfg, nil
+}
+
+// read decodes the config file. Initializing an empty file or migrating an
+// old one rewrites it, which is done under the lock: without it, read takes
+// the lock and starts over, since another process may have got there first.
+func (s *FileStore) read(locked bool) (Config, *sealer, error) {
+	if err := s.ensureFile(); err != nil {
+		return Config{}, nil, err
+	}
 
 	content, err := os.ReadFile(s.path)
 	if err != nil {
-		return Config{}, fmt.Errorf("read config file: %w", err)
+		return Config{}, nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	var (
+		upgraded []byte
+		from     int
+	)
+	if len(content) > 0 {
+		upgraded, from, err = upgrade(content)
+		if err != nil {
+			return Config{}, nil, err
+		}
+	}
+	if !locked && (len(content) == 0 || upgraded != nil) {
+		var (
+			cfg    Config
+			opened *sealer
+		)
+		err := s.WithLock(func(LockedStore) error {
+			var err error
+			cfg, opened, err = s.read(true)
+			return err
+		})
+		return cfg, opened, err
 	}
 
 	if len(content) == 0 {
 		cfg := Default()
 		if err := s.Save(cfg); err != nil {
-			return Config{}, err
+			return Config{}, nil, err
+		}
+		return cfg, s.sealer, nil
+	}
+	if upgraded != nil {
+		if err := s.saveMigration(content, upgraded, from); err != nil {
+			return Config{}, nil, err
 		}
-		return cfg, nil
+		content = upgraded
 	}
 
-	cfg := Default()
-	if err := json.Unmarshal(content, &cfg); err != nil {
-		return Config{}, fmt.Errorf("decode config file: %w", err)
+	cfg, opened, err := decode(content, s.opened, s.passphrase)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	return cfg, opened, nil
+}
+
+// Unmarshal decodes the content of a config file without touching disk: an
+// older version is upgraded in memory and encrypted secrets are opened with
+// a passphrase from passphrase.
+func Unmarshal(content []byte, passphrase PassphraseFunc) (Config, error) {
+	upgraded, _, err := upgrade(content)
+	if err != nil {
+		return Config{}, err
 	}
+	if upgraded != nil {
+		content = upgraded
+	}
+	cfg, _, err := decode(content, nil, passphrase)
+	return cfg, err
+}
+
+// Marshal encodes cfg in the config file format, encrypting profile secrets
+// with a key derived from passphrase unless it is nil.
+func Marshal(cfg Config, passphrase []byte) ([]byte, error) {
+	var sealer *sealer
+	if passphrase != nil {
+		var err error
+		if sealer, err = newSealer(passphrase); err != nil {
+			return nil, err
+		}
+	}
+	return encode(cfg, sealer)
+}
 
-	if cfg.Version == 0 {
-		cfg.Version = CurrentVersion
+// decode unmarshals current-version content, reusing opened to decrypt it
+// when its key matches and asking passphrase otherwise.
+func decode(content []byte, opened *sealer, passphrase PassphraseFunc) (Config, *sealer, error) {
+	cfg := Default()
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return Config{}, nil, fmt.Errorf("decode config file: %w", err)
 	}
 	if cfg.Profiles == nil {
 		cfg.Profiles = make(map[string]Profile)
 	}
+	if cfg.Encryption == nil {
+		return cfg, nil, nil
+	}
+	opened, err := unlock(&cfg, opened, passphrase)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	return cfg, opened, nil
+}
 
-	return cfg, nil
+func unlock(cfg *Config, opened *sealer, passphrase PassphraseFunc) (*sealer, error) {
+	sealer := opened
+	if sealer == nil || sealer.params != *cfg.Encryption {
+		if passphrase == nil {
+			return nil, ErrPassphraseRequired
+		}
+		secret, err := passphrase()
+		if err != nil {
+			return nil, err
+		}
+		sealer, err = openSealer(secret, *cfg.Encryption)
+		if err != nil {
+			return nil, err
+		}
+	}
+	profiles, err := sealer.openProfiles(cfg.Profiles)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Profiles = profiles
+	cfg.Encryption = nil
+	return sealer, nil
 }
 
 func (s *FileStore) Save(cfg Config) error {
 	if err := s.ensureDir(); err != nil {
 		return err
 	}
+	data, err := encode(cfg, s.sealer)
+	if err != nil {
+		return err
+	}
+	return s.writeFile(data)
+}
 
+func encode(cfg Config, sealer *sealer) ([]byte, error) {
 	cfg.Version = CurrentVersion
 	if cfg.Profiles == nil {
 		cfg.Profiles = make(map[string]Profile)
 	}
+	cfg.Encryption = nil
+	if sealer != nil {
+		profiles, err := sealer.sealProfiles(cfg.Profiles)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt config secrets: %w", err)
+		}
+		params := sealer.params
+		cfg.Profiles = profiles
+		cfg.Encryption = &params
+	}
 
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
-		return fmt.Errorf("encode config file: %w", err)
+		return nil, fmt.Errorf("encode config file: %w", err)
 	}
-	data = append(data, '\n')
+	return append(data, '\n'), nil
+}
 
+// writeFile atomically replaces the config file with data.
+func (s *FileStore) writeFile(data []byte) error {
 	dir := filepath.Dir(s.path)
 	tmpFile, err := os.CreateTemp(dir, "config-*.tmp")
 	if err != nil {
@@ -122,7 +375,15 @@ func (s *FileStore) ensureFile() error {
 		return fmt.Errorf("stat config file: %w", err)
 	}
 
-	if err := os.WriteFile(s.path, []byte{}, 0o600); err != nil {
+	// O_EXCL so a file another process just wrote is not truncated.
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("create config file: %w", err)
+	}
+	if err := f.Close(); err != nil {
 		return fmt.Errorf("create config file: %w", err)
 	}
 	if err := os.Chmod(s.path, 0o600); err != nil {
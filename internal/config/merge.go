@@ -0,0 +1,118 @@
+package config
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Merge applies the changes made between base and ours on top of theirs, so
+// saving does not undo what another process wrote in the meantime. Profiles
+// are merged field by field; a profile removed by either side stays removed.
+func Merge(base, ours, theirs Config) Config {
+	merged := theirs.Clone()
+	if ours.ActiveProfile != base.ActiveProfile {
+		merged.ActiveProfile = ours.ActiveProfile
+	}
+	if !reflect.DeepEqual(ours.RiskFreeRate, base.RiskFreeRate) {
+		merged.RiskFreeRate = ours.RiskFreeRate
+	}
+	merged.Watchlists = mergeMap(base.Watchlists, ours.Watchlists, merged.Watchlists)
+
+	for name, baseProfile := range base.Profiles {
+		ourProfile, ok := ours.Profiles[name]
+		if !ok {
+			delete(merged.Profiles, name)
+			continue
+		}
+		theirProfile, ok := merged.Profiles[name]
+		if !ok || reflect.DeepEqual(baseProfile, ourProfile) {
+			continue
+		}
+		merged.Profiles[name] = mergeProfile(baseProfile, ourProfile, theirProfile)
+	}
+	for name, ourProfile := range ours.Profiles {
+		if _, ok := base.Profiles[name]; ok {
+			continue
+		}
+		if theirProfile, ok := merged.Profiles[name]; ok {
+			ourProfile = mergeProfile(Profile{}, ourProfile, theirProfile)
+		}
+		merged.Profiles[name] = ourProfile
+	}
+	return merged
+}
+
+// Clone returns a deep copy of c.
+func (c Config) Clone() Config {
+	clone := c
+	clone.Profiles = make(map[string]Profile, len(c.Profiles))
+	for name, profile := range c.Profiles {
+		clone.Profiles[name] = profile.clone()
+	}
+	clone.Watchlists = cloneWatchlists(c.Watchlists)
+	if c.RiskFreeRate != nil {
+		rate := *c.RiskFreeRate
+		clone.RiskFreeRate = &rate
+	}
+	if c.Encryption != nil {
+		encryption := *c.Encryption
+		clone.Encryption = &encryption
+	}
+	return clone
+}
+
+func (p Profile) clone() Profile {
+	p.Watchlists = cloneWatchlists(p.Watchlists)
+	if p.Credentials != nil {
+		creds := *p.Credentials
+		creds.Sources = maps.Clone(creds.Sources)
+		p.Credentials = &creds
+	}
+	return p
+}
+
+func cloneWatchlists(watchlists map[string][]string) map[string][]string {
+	if watchlists == nil {
+		return nil
+	}
+	out := make(map[string][]string, len(watchlists))
+	for name, symbols := range watchlists {
+		out[name] = slices.Clone(symbols)
+	}
+	return out
+}
+
+func mergeProfile(base, ours, theirs Profile) Profile {
+	theirWatchlists := theirs.Watchlists
+	b, o, t := reflect.ValueOf(base), reflect.ValueOf(ours), reflect.ValueOf(&theirs).Elem()
+	for i := range t.NumField() {
+		if !reflect.DeepEqual(o.Field(i).Interface(), b.Field(i).Interface()) {
+			t.Field(i).Set(o.Field(i))
+		}
+	}
+	theirs.Watchlists = mergeMap(base.Watchlists, ours.Watchlists, theirWatchlists)
+	return theirs
+}
+
+func mergeMap[V any](base, ours, theirs map[string]V) map[string]V {
+	merged := maps.Clone(theirs)
+	for key, value := range ours {
+		if baseValue, ok := base[key]; ok && reflect.DeepEqual(baseValue, value) {
+			continue
+		}
+		if merged == nil {
+			merged = make(map[string]V)
+		}
+		merged[key] = value
+	}
+	for key := range base {
+		if _, ok := ours[key]; !ok {
+			delete(merged, key)
+		}
+	}
+	if len(merged) == 0 && theirs == nil {
+		return nil
+	}
+	return merged
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+// ErrNewerVersion is returned when the config file was written by a newer
+// CLI than this one.
+var ErrNewerVersion = errors.New("config file was written by a newer version of zerodha")
+
+// migration upgrades a decoded config document by one version. Documents are
+// migrated before decryption, so a migration must not rewrite secret fields.
+type migration func(doc map[string]any) error
+
+// migrations[v] upgrades a version v document to version v+1. Append a step
+// here and bump CurrentVersion together.
+var migrations = []migration{
+	migrateV0ToV1,
+}
+
+// migrateV0ToV1 upgrades files written before the version field existed; the
+// layout is otherwise unchanged.
+func migrateV0ToV1(doc map[string]any) error {
+	if _, ok := doc["profiles"]; !ok {
+		doc["profiles"] = map[string]any{}
+	}
+	return nil
+}
+
+// upgrade returns content migrated to CurrentVersion along with the version
+// it started from, or nil data when content is already current.
+func upgrade(content []byte) ([]byte, int, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, 0, fmt.Errorf("decode config file: %w", err)
+	}
+	version, err := documentVersion(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version == CurrentVersion {
+		return nil, version, nil
+	}
+	if version > CurrentVersion {
+		return nil, 0, fmt.Errorf("%w (file version %d, this CLI supports up to %d); upgrade with `zerodha update`", ErrNewerVersion, version, CurrentVersion)
+	}
+
+	if err := upgradeDocument(doc, version, CurrentVersion, migrations); err != nil {
+		return nil, 0, err
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, 0, fmt.Errorf("encode migrated config: %w", err)
+	}
+	return append(data, '\n'), version, nil
+}
+
+// saveMigration keeps the original file next to the config as
+// <path>.v<old version>.bak, then writes the upgraded one. The caller holds
+// the config lock.
+func (s *FileStore) saveMigration(original, upgraded []byte, from int) error {
+	if err := os.WriteFile(s.BackupPath(from), original, 0o600); err != nil {
+		return fmt.Errorf("back up config before migration: %w", err)
+	}
+	return s.writeFile(upgraded)
+}
+
+// BackupPath is where the version v file is copied before it is migrated.
+func (s *FileStore) BackupPath(version int) string {
+	return fmt.Sprintf("%s.v%d.bak", s.path, version)
+}
+
+func upgradeDocument(doc map[string]any, from, to int, steps []migration) error {
+	if to > len(steps) {
+		return fmt.Errorf("no config migration from version %d", len(steps))
+	}
+	for version := from; version < to; version++ {
+		if err := steps[version](doc); err != nil {
+			return fmt.Errorf("migrate config from version %d: %w", version, err)
+		}
+		doc["version"] = version + 1
+	}
+	return nil
+}
+
+func documentVersion(doc map[string]any) (int, error) {
+	raw, ok := doc["version"]
+	if !ok || raw == nil {
+		return 0, nil
+	}
+	value, ok := raw.(float64)
+	if !ok || value < 0 || value != math.Trunc(value) {
+		return 0, fmt.Errorf("config file has invalid version %v", raw)
+	}
+	return int(value), nil
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpgradeDocumentRunsEachStepInOrder(t *testing.T) {
+	var ran []int
+	steps := []migration{
+		func(doc map[string]any) error { ran = append(ran, 0); return nil },
+		func(doc map[string]any) error {
+			ran = append(ran, 1)
+			doc["renamed"] = doc["old"]
+			delete(doc, "old")
+			return nil
+		},
+		func(doc map[string]any) error { ran = append(ran, 2); return nil },
+	}
+
+	doc := map[string]any{"version": float64(1), "old": "value"}
+	if err := upgradeDocument(doc, 1, 3, steps); err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if len(ran) != 2 || ran[0] != 1 || ran[1] != 2 {
+		t.Fatalf("expected steps 1 and 2 to run, got %v", ran)
+	}
+	if doc["version"] != 3 || doc["renamed"] != "value" {
+		t.Fatalf("unexpected migrated document: %v", doc)
+	}
+}
+
+func TestUpgradeDocumentStopsAtFailingStep(t *testing.T) {
+	steps := []migration{
+		func(doc map[string]any) error { return errors.New("boom") },
+		func(doc map[string]any) error { t.Fatal("later step should not run"); return nil },
+	}
+	doc := map[string]any{}
+	err := upgradeDocument(doc, 0, 2, steps)
+	if err == nil || !strings.Contains(err.Error(), "from version 0") {
+		t.Fatalf("expected the failing step to be reported, got %v", err)
+	}
+	if _, ok := doc["version"]; ok {
+		t.Fatalf("expected version to stay unset after a failed step, got %v", doc["version"])
+	}
+
+	if err := upgradeDocument(map[string]any{}, 0, 3, steps); err == nil {
+		t.Fatalf("expected an error when a migration step is missing")
+	}
+}
+
+func TestMigrateV0ToV1(t *testing.T) {
+	doc := map[string]any{"active_profile": "default"}
+	if err := migrateV0ToV1(doc); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if _, ok := doc["profiles"].(map[string]any); !ok {
+		t.Fatalf("expected an empty profiles map, got %v", doc["profiles"])
+	}
+}
+
+func TestLoadMigratesAndBacksUpOldConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	original := []byte(`{"active_profile":"default","profiles":{"default":{"api_key":"key","api_secret":"secret"}}}`)
+	if err := os.WriteFile(path, original, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	store := NewFileStore(path)
+	cfg, err := store.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Version != CurrentVersion || cfg.Profiles["default"].APISecret != "secret" {
+		t.Fatalf("unexpected migrated config: %+v", cfg)
+	}
+
+	backup, err := os.ReadFile(store.BackupPath(0))
+	if err != nil {
+		t.Fatalf("read backup: %v", err)
+	}
+	if string(backup) != string(original) {
+		t.Fatalf("expected the backup to hold the original file, got %s", backup)
+	}
+	var onDisk map[string]any
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if err := json.Unmarshal(content, &onDisk); err != nil {
+		t.Fatalf("decode migrated file: %v", err)
+	}
+	if onDisk["version"] != float64(CurrentVersion) {
+		t.Fatalf("expected the migrated file to be written, got %s", content)
+	}
+}
+
+func TestLoadMigratesUnderLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	original := []byte(`{"profiles":{"default":{"api_key":"key"}}}`)
+	if err := os.WriteFile(path, original, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	// Another process holds the lock and updates the config meanwhile.
+	locked, release := make(chan struct{}), make(chan struct{})
+	updated := make(chan error, 1)
+	go func() {
+		updated <- NewFileStore(path).WithLock(func(store LockedStore) error {
+			close(locked)
+			<-release
+			_, err := store.Update(func(cfg *Config) error {
+				cfg.Profiles["other"] = Profile{APIKey: "other"}
+				return nil
+			})
+			return err
+		})
+	}()
+	<-locked
+
+	type result struct {
+		cfg Config
+		err error
+	}
+	loaded := make(chan result, 1)
+	go func() {
+		cfg, err := NewFileStore(path).Load()
+		loaded <- result{cfg, err}
+	}()
+	select {
+	case <-loaded:
+		t.Fatalf("expected Load to wait for the lock before migrating")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-updated; err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got := <-loaded
+	if got.err != nil {
+		t.Fatalf("load: %v", got.err)
+	}
+	if got.cfg.Version != CurrentVersion || got.cfg.Profiles["other"].APIKey != "other" {
+		t.Fatalf("expected Load to see the locked update, got %+v", got.cfg)
+	}
+	backup, err := os.ReadFile(NewFileStore(path).BackupPath(0))
+	if err != nil || string(backup) != string(original) {
+		t.Fatalf("expected the backup to hold the original file, got %s (%v)", backup, err)
+	}
+}
+
+func TestLoadCurrentVersionDoesNotBackUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	store := NewFileStore(path)
+	if err := store.Save(Default()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := store.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	matches, err := filepath.Glob(path + ".v*.bak")
+	if err != nil || len(matches) != 0 {
+		t.Fatalf("expected no backup for a current config, got %v (%v)", matches, err)
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := []byte(`{"version":99,"profiles":{}}`)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	_, err := NewFileStore(path).Load()
+	if !errors.Is(err, ErrNewerVersion) || !strings.Contains(err.Error(), "99") {
+		t.Fatalf("expected a newer-version error, got %v", err)
+	}
+	after, readErr := os.ReadFile(path)
+	if readErr != nil || string(after) != string(content) {
+		t.Fatalf("expected the newer config to be left untouched, got %s (%v)", after, readErr)
+	}
+}
+
+func TestLoadRejectsInvalidVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"version":"two"}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := NewFileStore(path).Load(); err == nil || !strings.Contains(err.Error(), "invalid version") {
+		t.Fatalf("expected an invalid version error, got %v", err)
+	}
+}
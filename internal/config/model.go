@@ -5,17 +5,34 @@ import "time"
 const CurrentVersion = 1
 
 type Config struct {
-	Version       int                `json:"version"`
-	ActiveProfile string             `json:"active_profile,omitempty"`
-	Profiles      map[string]Profile `json:"profiles"`
+	Version       int                 `json:"version"`
+	ActiveProfile string              `json:"active_profile,omitempty"`
+	Profiles      map[string]Profile  `json:"profiles"`
+	Watchlists    map[string][]string `json:"watchlists,omitempty"`
+	RiskFreeRate  *float64            `json:"risk_free_rate,omitempty"`
+	Encryption    *Encryption         `json:"encryption,omitempty"`
 }
 
 type Profile struct {
-	APIKey       string    `json:"api_key,omitempty"`
-	APISecret    string    `json:"api_secret,omitempty"`
-	AccessToken  string    `json:"access_token,omitempty"`
-	RefreshToken string    `json:"refresh_token,omitempty"`
-	LastLoginAt  time.Time `json:"last_login_at,omitempty"`
+	APIKey       string              `json:"api_key,omitempty"`
+	APISecret    string              `json:"api_secret,omitempty"`
+	AccessToken  string              `json:"access_token,omitempty"`
+	RefreshToken string              `json:"refresh_token,omitempty"`
+	LastLoginAt  time.Time           `json:"last_login_at,omitempty"`
+	BaseURL      string              `json:"base_url,omitempty"`
+	Credentials  *Credentials        `json:"credentials,omitempty"`
+	Watchlists   map[string][]string `json:"watchlists,omitempty"`
+}
+
+// Credentials reads a profile's key, secret and tokens from somewhere other
+// than the config file.
+type Credentials struct {
+	// Sources maps api_key, api_secret, access_token or refresh_token to
+	// "env:NAME" or "cmd:COMMAND". They take precedence over Helper.
+	Sources map[string]string `json:"sources,omitempty"`
+	// Helper is a git-style credential helper run as `<helper> get|store|erase`.
+	// When set, changed secrets such as refreshed tokens are stored through it.
+	Helper string `json:"helper,omitempty"`
 }
 
 func Default() Config {
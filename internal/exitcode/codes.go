@@ -16,6 +16,11 @@ const (
 	Network    = 13
 	API        = 14
 	Internal   = 15
+
+	// Returned by `auth status` so scheduled jobs can tell a stale session
+	// from one that was never set up.
+	SessionExpired = 16
+	SessionMissing = 17
 )
 
 type codedError struct {
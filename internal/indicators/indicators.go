@@ -0,0 +1,187 @@
+package indicators
+
+import (
+	"math"
+	"time"
+)
+
+// Candle is one OHLCV bar. Indicator outputs are aligned with the input
+// slice; bars before an indicator has enough history are NaN.
+type Candle struct {
+	Time   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+func Closes(candles []Candle) []float64 {
+	out := make([]float64, len(candles))
+	for i, c := range candles {
+		out[i] = c.Close
+	}
+	return out
+}
+
+func nans(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+// SMA is the simple moving average over period values.
+func SMA(values []float64, period int) []float64 {
+	out := nans(len(values))
+	if period <= 0 {
+		return out
+	}
+	sum := 0.0
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out
+}
+
+// EMA is the exponential moving average with smoothing 2/(period+1), seeded
+// with the SMA of the first period values. Leading NaNs in values are
+// skipped, so an EMA can be taken of another indicator's output.
+func EMA(values []float64, period int) []float64 {
+	return smoothed(values, period, 2/float64(period+1))
+}
+
+// wilder is Wilder's smoothing (alpha 1/period) used by RSI and ATR.
+func wilder(values []float64, period int) []float64 {
+	return smoothed(values, period, 1/float64(period))
+}
+
+func smoothed(values []float64, period int, alpha float64) []float64 {
+	out := nans(len(values))
+	if period <= 0 {
+		return out
+	}
+	start := 0
+	for start < len(values) && math.IsNaN(values[start]) {
+		start++
+	}
+	if len(values)-start < period {
+		return out
+	}
+	sum := 0.0
+	for _, v := range values[start : start+period] {
+		sum += v
+	}
+	prev := sum / float64(period)
+	out[start+period-1] = prev
+	for i := start + period; i < len(values); i++ {
+		prev += alpha * (values[i] - prev)
+		out[i] = prev
+	}
+	return out
+}
+
+// RSI is Wilder's relative strength index over closing prices.
+func RSI(closes []float64, period int) []float64 {
+	out := nans(len(closes))
+	if len(closes) < 2 {
+		return out
+	}
+	gains := nans(len(closes))
+	losses := nans(len(closes))
+	for i := 1; i < len(closes); i++ {
+		change := closes[i] - closes[i-1]
+		gains[i] = math.Max(change, 0)
+		losses[i] = math.Max(-change, 0)
+	}
+	avgGain := wilder(gains, period)
+	avgLoss := wilder(losses, period)
+	for i := range out {
+		switch {
+		case math.IsNaN(avgGain[i]):
+		case avgLoss[i] == 0:
+			out[i] = 100
+		default:
+			out[i] = 100 - 100/(1+avgGain[i]/avgLoss[i])
+		}
+	}
+	return out
+}
+
+// MACD returns the MACD line (fast EMA minus slow EMA), its signal EMA and
+// the histogram (line minus signal).
+func MACD(closes []float64, fast, slow, signal int) (line, sig, hist []float64) {
+	fastEMA := EMA(closes, fast)
+	slowEMA := EMA(closes, slow)
+	line = make([]float64, len(closes))
+	for i := range line {
+		line[i] = fastEMA[i] - slowEMA[i]
+	}
+	sig = EMA(line, signal)
+	hist = make([]float64, len(closes))
+	for i := range hist {
+		hist[i] = line[i] - sig[i]
+	}
+	return line, sig, hist
+}
+
+// BollingerBands returns the SMA middle band and the bands k population
+// standard deviations above and below it.
+func BollingerBands(values []float64, period int, k float64) (middle, upper, lower []float64) {
+	middle = SMA(values, period)
+	upper = nans(len(values))
+	lower = nans(len(values))
+	for i := range values {
+		if math.IsNaN(middle[i]) {
+			continue
+		}
+		variance := 0.0
+		for _, v := range values[i-period+1 : i+1] {
+			variance += (v - middle[i]) * (v - middle[i])
+		}
+		width := k * math.Sqrt(variance/float64(period))
+		upper[i] = middle[i] + width
+		lower[i] = middle[i] - width
+	}
+	return middle, upper, lower
+}
+
+// ATR is Wilder's average true range. The first bar's true range is its
+// high-low range since it has no previous close.
+func ATR(candles []Candle, period int) []float64 {
+	ranges := make([]float64, len(candles))
+	for i, c := range candles {
+		ranges[i] = c.High - c.Low
+		if i > 0 {
+			prev := candles[i-1].Close
+			ranges[i] = math.Max(ranges[i], math.Max(math.Abs(c.High-prev), math.Abs(c.Low-prev)))
+		}
+	}
+	return wilder(ranges, period)
+}
+
+// VWAP is the volume-weighted average of the typical price (H+L+C)/3,
+// reset at the start of each calendar day in the candle's time zone.
+func VWAP(candles []Candle) []float64 {
+	out := nans(len(candles))
+	var pv, volume float64
+	var session string
+	for i, c := range candles {
+		if day := c.Time.Format(time.DateOnly); day != session {
+			session, pv, volume = day, 0, 0
+		}
+		pv += (c.High + c.Low + c.Close) / 3 * c.Volume
+		volume += c.Volume
+		if volume > 0 {
+			out[i] = pv / volume
+		}
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func assertClose(t *testing.T, name string, got, want, tol float64) {
+	t.Helper()
+	if math.Abs(got-want) > tol || math.IsNaN(got) {
+		t.Fatalf("expected %s %.6f (±%g), got %.6f", name, want, tol, got)
+	}
+}
+
+func assertNaN(t *testing.T, name string, got float64) {
+	t.Helper()
+	if !math.IsNaN(got) {
+		t.Fatalf("expected %s to be NaN during warm-up, got %f", name, got)
+	}
+}
+
+func ramp(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = float64(i + 1)
+	}
+	return out
+}
+
+func TestSMAAndEMA(t *testing.T) {
+	values := ramp(10)
+	sma := SMA(values, 3)
+	assertNaN(t, "sma[1]", sma[1])
+	assertClose(t, "sma[2]", sma[2], 2, 1e-12)
+	assertClose(t, "sma[9]", sma[9], 9, 1e-12)
+
+	// On a unit ramp an SMA-seeded EMA lags the price by (period-1)/2.
+	ema := EMA(values, 3)
+	assertNaN(t, "ema[1]", ema[1])
+	for i := 2; i < len(values); i++ {
+		assertClose(t, "ema", ema[i], values[i]-1, 1e-12)
+	}
+}
+
+// Closing prices and RSI values are the 14-day worked example published by
+// StockCharts (ChartSchool, "Relative Strength Index").
+func TestRSIMatchesReferenceValues(t *testing.T) {
+	closes := []float64{
+		44.3389, 44.0902, 44.1497, 43.6124, 44.3278, 44.8264, 45.0955, 45.4245, 45.8433, 46.0826,
+		45.8931, 46.0328, 45.6140, 46.2820, 46.2820, 46.0028, 46.0328, 46.4116, 46.2222, 45.6439,
+		46.2122, 46.2521, 45.7137, 46.4515, 45.7835, 45.3548, 44.0288, 44.1783, 44.2181, 44.5672,
+		43.4205, 42.6628, 43.1314,
+	}
+	rsi := RSI(closes, 14)
+	assertNaN(t, "rsi[13]", rsi[13])
+	assertClose(t, "rsi[14]", rsi[14], 70.53, 0.01)
+	assertClose(t, "rsi[15]", rsi[15], 66.32, 0.01)
+	assertClose(t, "rsi[32]", rsi[32], 37.77, 0.01)
+
+	if got := RSI(ramp(20), 14)[19]; got != 100 {
+		t.Fatalf("expected RSI 100 with no losses, got %f", got)
+	}
+}
+
+func TestMACDOnRamp(t *testing.T) {
+	line, signal, hist := MACD(ramp(60), 12, 26, 9)
+	assertNaN(t, "macd[24]", line[24])
+	assertClose(t, "macd[25]", line[25], 7, 1e-9)
+	assertNaN(t, "signal[32]", signal[32])
+	assertClose(t, "signal[33]", signal[33], 7, 1e-9)
+	assertClose(t, "hist[59]", hist[59], 0, 1e-9)
+}
+
+func TestBollingerBands(t *testing.T) {
+	middle, upper, lower := BollingerBands([]float64{2, 4, 4, 4, 5, 5, 7, 9}, 8, 2)
+	assertNaN(t, "middle[6]", middle[6])
+	assertClose(t, "middle", middle[7], 5, 1e-12)
+	assertClose(t, "upper", upper[7], 9, 1e-12)
+	assertClose(t, "lower", lower[7], 1, 1e-12)
+}
+
+func TestATR(t *testing.T) {
+	candles := []Candle{
+		{High: 10, Low: 8, Close: 9},
+		{High: 12, Low: 10, Close: 11}, // gap up: TR = 12-9 = 3
+		{High: 11, Low: 7, Close: 8},   // TR = 4
+		{High: 9, Low: 8, Close: 8.5},  // TR = 1
+	}
+	atr := ATR(candles, 3)
+	assertNaN(t, "atr[1]", atr[1])
+	assertClose(t, "atr[2]", atr[2], 3, 1e-12)
+	assertClose(t, "atr[3]", atr[3], (3*2+1)/3.0, 1e-12)
+}
+
+func TestVWAPResetsEachDay(t *testing.T) {
+	day := time.Date(2026, 10, 16, 9, 15, 0, 0, time.UTC)
+	candles := []Candle{
+		{Time: day, High: 102, Low: 98, Close: 100, Volume: 100},
+		{Time: day.Add(time.Minute), High: 112, Low: 108, Close: 110, Volume: 300},
+		{Time: day.AddDate(0, 0, 1), High: 52, Low: 48, Close: 50, Volume: 10},
+		{Time: day.AddDate(0, 0, 1).Add(time.Minute), High: 1, Low: 1, Close: 1, Volume: 0},
+	}
+	vwap := VWAP(candles)
+	assertClose(t, "vwap[0]", vwap[0], 100, 1e-12)
+	assertClose(t, "vwap[1]", vwap[1], 107.5, 1e-12)
+	assertClose(t, "vwap[2]", vwap[2], 50, 1e-12)
+	assertClose(t, "vwap[3]", vwap[3], 50, 1e-12)
+}
+
+func TestParseSpecs(t *testing.T) {
+	specs, err := ParseSpecs("sma:20, EMA:50,rsi:14,macd,bbands:20:2.5,atr:14,vwap")
+	if err != nil {
+		t.Fatalf("parse specs: %v", err)
+	}
+	var names []string
+	for _, column := range Compute(specs, make([]Candle, 3)) {
+		names = append(names, column.Name)
+	}
+	want := []string{
+		"sma_20", "ema_50", "rsi_14",
+		"macd_12_26_9", "macd_12_26_9_signal", "macd_12_26_9_hist",
+		"bbands_20_2.5_upper", "bbands_20_2.5_middle", "bbands_20_2.5_lower",
+		"atr_14", "vwap",
+	}
+	if len(names) != len(want) {
+		t.Fatalf("expected columns %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("expected columns %v, got %v", want, names)
+		}
+	}
+
+	for _, raw := range []string{"", "foo:3", "sma:0", "sma:2.5", "rsi:14:2", "vwap:5"} {
+		if _, err := ParseSpecs(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package indicators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Spec is one parsed indicator request such as "ema:50" or "bbands:20:2".
+type Spec struct {
+	Name   string
+	Params []float64
+}
+
+// Column is one named output series, aligned with the input candles.
+type Column struct {
+	Name   string
+	Values []float64
+}
+
+type definition struct {
+	defaults []float64
+	integer  []bool
+}
+
+var definitions = map[string]definition{
+	"sma":    {defaults: []float64{20}, integer: []bool{true}},
+	"ema":    {defaults: []float64{20}, integer: []bool{true}},
+	"rsi":    {defaults: []float64{14}, integer: []bool{true}},
+	"macd":   {defaults: []float64{12, 26, 9}, integer: []bool{true, true, true}},
+	"bbands": {defaults: []float64{20, 2}, integer: []bool{true, false}},
+	"atr":    {defaults: []float64{14}, integer: []bool{true}},
+	"vwap":   {},
+}
+
+// ParseSpecs parses a comma-separated list of NAME[:PARAM...] entries.
+// Omitted trailing parameters take their defaults.
+func ParseSpecs(raw string) ([]Spec, error) {
+	var specs []Spec
+	for entry := range strings.SplitSeq(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		def, ok := definitions[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown indicator %q (use sma, ema, rsi, macd, bbands, atr, vwap)", parts[0])
+		}
+		if len(parts)-1 > len(def.defaults) {
+			return nil, fmt.Errorf("%s takes at most %d parameter(s)", name, len(def.defaults))
+		}
+
+		params := append([]float64(nil), def.defaults...)
+		for i, part := range parts[1:] {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil || value <= 0 {
+				return nil, fmt.Errorf("invalid %s parameter %q; must be a positive number", name, part)
+			}
+			if def.integer[i] && value != float64(int(value)) {
+				return nil, fmt.Errorf("invalid %s period %q; must be a whole number", name, part)
+			}
+			params[i] = value
+		}
+		specs = append(specs, Spec{Name: name, Params: params})
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no indicators given")
+	}
+	return specs, nil
+}
+
+// Compute evaluates the specs over candles. Most indicators produce one
+// column; macd and bbands produce three.
+func Compute(specs []Spec, candles []Candle) []Column {
+	closes := Closes(candles)
+	var columns []Column
+	for _, spec := range specs {
+		p := spec.Params
+		switch spec.Name {
+		case "sma":
+			columns = append(columns, Column{Name: spec.label(), Values: SMA(closes, int(p[0]))})
+		case "ema":
+			columns = append(columns, Column{Name: spec.label(), Values: EMA(closes, int(p[0]))})
+		case "rsi":
+			columns = append(columns, Column{Name: spec.label(), Values: RSI(closes, int(p[0]))})
+		case "atr":
+			columns = append(columns, Column{Name: spec.label(), Values: ATR(candles, int(p[0]))})
+		case "vwap":
+			columns = append(columns, Column{Name: spec.label(), Values: VWAP(candles)})
+		case "macd":
+			line, signal, hist := MACD(closes, int(p[0]), int(p[1]), int(p[2]))
+			label := spec.label()
+			columns = append(columns,
+				Column{Name: label, Values: line},
+				Column{Name: label + "_signal", Values: signal},
+				Column{Name: label + "_hist", Values: hist},
+			)
+		case "bbands":
+			middle, upper, lower := BollingerBands(closes, int(p[0]), p[1])
+			label := spec.label()
+			columns = append(columns,
+				Column{Name: label + "_upper", Values: upper},
+				Column{Name: label + "_middle", Values: middle},
+				Column{Name: label + "_lower", Values: lower},
+			)
+		}
+	}
+	return columns
+}
+
+// label names a spec's output, e.g. sma_20, macd_12_26_9, bbands_20_2.
+func (s Spec) label() string {
+	parts := []string{s.Name}
+	for _, p := range s.Params {
+		parts = append(parts, strconv.FormatFloat(p, 'f', -1, 64))
+	}
+	return strings.Join(parts, "_")
+}
@@ -0,0 +1,492 @@
+// Package kitefake serves a fake Kite Connect HTTP API backed by fixture data,
+// so commands can be exercised end to end without api.kite.trade.
+package kitefake
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Credentials accepted by the fake server.
+const (
+	APIKey       = "fake_api_key"
+	APISecret    = "fake_api_secret"
+	RequestToken = "fake_request_token"
+	AccessToken  = "fake_access_token"
+	RefreshToken = "fake_refresh_token"
+)
+
+//go:embed fixtures
+var fixtures embed.FS
+
+// Request is a call the server received, kept for assertions.
+type Request struct {
+	Method string
+	Path   string
+	Form   url.Values
+}
+
+// Server is a running fake Kite API. Orders, GTTs and MF orders placed
+// through it are appended to the fixture data for later reads.
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	renewals     int
+	nextID       int
+	orderStatus  string
+	requests     []Request
+	orders       []map[string]any
+	gtts         []map[string]any
+	mfOrders     []map[string]any
+}
+
+// New starts a fake server. Callers must Close it.
+func New() *Server {
+	s := &Server{
+		accessToken:  AccessToken,
+		refreshToken: RefreshToken,
+		nextID:       1,
+		orderStatus:  "OPEN",
+	}
+	mustDecode("fixtures/orders.json", &s.orders)
+	mustDecode("fixtures/gtts.json", &s.gtts)
+	mustDecode("fixtures/mf_orders.json", &s.mfOrders)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /session/token", s.handleSession)
+	mux.HandleFunc("DELETE /session/token", s.authed(s.handleInvalidate))
+	mux.HandleFunc("POST /session/refresh_token", s.handleRenew)
+
+	mux.HandleFunc("GET /user/profile", s.authed(s.fixture("profile.json")))
+	mux.HandleFunc("GET /user/margins", s.authed(s.fixture("margins.json")))
+
+	mux.HandleFunc("GET /quote", s.authed(s.handleQuote("full")))
+	mux.HandleFunc("GET /quote/ltp", s.authed(s.handleQuote("ltp")))
+	mux.HandleFunc("GET /quote/ohlc", s.authed(s.handleQuote("ohlc")))
+	mux.HandleFunc("GET /instruments/{exchange}", s.authed(s.handleInstruments))
+	mux.HandleFunc("GET /instruments/historical/{token}/{interval}", s.authed(s.handleHistorical))
+
+	mux.HandleFunc("GET /orders", s.authed(s.handleOrders))
+	mux.HandleFunc("GET /orders/{id}", s.authed(s.handleOrderHistory))
+	mux.HandleFunc("GET /orders/{id}/trades", s.authed(s.handleOrderTrades))
+	mux.HandleFunc("POST /orders/{variety}", s.authed(s.handlePlaceOrder))
+	mux.HandleFunc("PUT /orders/{variety}/{id}", s.authed(s.handleModifyOrder))
+	mux.HandleFunc("DELETE /orders/{variety}/{id}", s.authed(s.handleCancelOrder))
+	mux.HandleFunc("GET /trades", s.authed(s.fixture("trades.json")))
+
+	mux.HandleFunc("GET /portfolio/positions", s.authed(s.fixture("positions.json")))
+	mux.HandleFunc("GET /portfolio/holdings", s.authed(s.fixture("holdings.json")))
+
+	mux.HandleFunc("GET /gtt/triggers", s.authed(s.handleGTTs))
+	mux.HandleFunc("GET /gtt/triggers/{id}", s.authed(s.handleGTT))
+	mux.HandleFunc("POST /gtt/triggers", s.authed(s.handlePlaceGTT))
+	mux.HandleFunc("DELETE /gtt/triggers/{id}", s.authed(s.handleDeleteGTT))
+
+	mux.HandleFunc("GET /mf/orders", s.authed(s.handleMFOrders))
+	mux.HandleFunc("POST /mf/orders", s.authed(s.handlePlaceMFOrder))
+	mux.HandleFunc("GET /mf/sips", s.authed(s.fixture("mf_sips.json")))
+	mux.HandleFunc("GET /mf/holdings", s.authed(s.fixture("mf_holdings.json")))
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "GeneralException", "route not found: "+r.Method+" "+r.URL.Path)
+	})
+
+	s.Server = httptest.NewServer(s.record(mux))
+	return s
+}
+
+// ExpireAccessToken rotates the server-side access token so the current one
+// is rejected with a TokenException until it is renewed.
+func (s *Server) ExpireAccessToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accessToken = AccessToken + "_rotated"
+}
+
+// AccessToken returns the access token the server currently accepts.
+func (s *Server) AccessToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accessToken
+}
+
+// Renewals counts successful refresh-token renewals.
+func (s *Server) Renewals() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.renewals
+}
+
+// SetOrderStatus sets the status that orders placed from now on start in,
+// e.g. COMPLETE to fill them at once. It defaults to OPEN.
+func (s *Server) SetOrderStatus(status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orderStatus = status
+}
+
+// Requests returns every request received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// LastRequest returns the most recent request for method and path.
+func (s *Server) LastRequest(method, path string) (Request, bool) {
+	requests := s.Requests()
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].Method == method && requests[i].Path == path {
+			return requests[i], true
+		}
+	}
+	return Request{}, false
+}
+
+func (s *Server) record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Form: r.Form})
+		s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authed(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		want := "token " + APIKey + ":" + s.accessToken
+		s.mu.Unlock()
+		if r.Header.Get("Authorization") != want {
+			writeError(w, http.StatusForbidden, "TokenException", "Incorrect `api_key` or `access_token`.")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) fixture(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		var data any
+		mustDecode("fixtures/"+name, &data)
+		writeData(w, data)
+	}
+}
+
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request) {
+	if !validChecksum(r.Form, "request_token", RequestToken) {
+		writeError(w, http.StatusForbidden, "TokenException", "Token is invalid or has expired.")
+		return
+	}
+	s.mu.Lock()
+	access, refresh := s.accessToken, s.refreshToken
+	s.mu.Unlock()
+
+	var profile map[string]any
+	mustDecode("fixtures/profile.json", &profile)
+	profile["api_key"] = APIKey
+	profile["access_token"] = access
+	profile["refresh_token"] = refresh
+	profile["public_token"] = "fake_public_token"
+	profile["login_time"] = "2026-10-16 08:45:00"
+	writeData(w, profile)
+}
+
+func (s *Server) handleInvalidate(w http.ResponseWriter, _ *http.Request) {
+	writeData(w, true)
+}
+
+func (s *Server) handleRenew(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	current := s.refreshToken
+	s.mu.Unlock()
+	if !validChecksum(r.Form, "refresh_token", current) {
+		writeError(w, http.StatusForbidden, "TokenException", "Token is invalid or has expired.")
+		return
+	}
+
+	s.mu.Lock()
+	s.renewals++
+	s.accessToken = fmt.Sprintf("%s_renewed_%d", AccessToken, s.renewals)
+	s.refreshToken = fmt.Sprintf("%s_renewed_%d", RefreshToken, s.renewals)
+	tokens := map[string]any{"user_id": "AB1234", "access_token": s.accessToken, "refresh_token": s.refreshToken}
+	s.mu.Unlock()
+	writeData(w, tokens)
+}
+
+func validChecksum(form url.Values, field, want string) bool {
+	if form.Get("api_key") != APIKey || form.Get(field) != want {
+		return false
+	}
+	sum := sha256.Sum256([]byte(APIKey + want + APISecret))
+	return form.Get("checksum") == hex.EncodeToString(sum[:])
+}
+
+func (s *Server) handleQuote(mode string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var quotes map[string]map[string]any
+		mustDecode("fixtures/quotes.json", &quotes)
+		out := make(map[string]any)
+		for _, key := range r.Form["i"] {
+			quote, ok := quotes[key]
+			if !ok {
+				continue
+			}
+			switch mode {
+			case "ltp":
+				out[key] = map[string]any{"instrument_token": quote["instrument_token"], "last_price": quote["last_price"]}
+			case "ohlc":
+				out[key] = map[string]any{"instrument_token": quote["instrument_token"], "last_price": quote["last_price"], "ohlc": quote["ohlc"]}
+			default:
+				out[key] = quote
+			}
+		}
+		writeData(w, out)
+	}
+}
+
+func (s *Server) handleInstruments(w http.ResponseWriter, r *http.Request) {
+	data, err := fixtures.ReadFile("fixtures/instruments_" + strings.ToUpper(r.PathValue("exchange")) + ".csv")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InputException", "unknown exchange")
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	_, _ = w.Write(data)
+}
+
+func (s *Server) handleHistorical(w http.ResponseWriter, r *http.Request) {
+	var candles map[string][]any
+	mustDecode("fixtures/historical.json", &candles)
+	series := candles[r.PathValue("token")]
+	if series == nil {
+		series = []any{}
+	}
+	writeData(w, map[string]any{"candles": series})
+}
+
+func (s *Server) handleOrders(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeData(w, s.orders)
+}
+
+func (s *Server) findOrder(id string) map[string]any {
+	for _, order := range s.orders {
+		if order["order_id"] == id {
+			return order
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleOrderHistory(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.findOrder(r.PathValue("id"))
+	if order == nil {
+		writeError(w, http.StatusBadRequest, "InputException", "Invalid order_id.")
+		return
+	}
+	writeData(w, []any{order})
+}
+
+func (s *Server) handleOrderTrades(w http.ResponseWriter, r *http.Request) {
+	var trades []map[string]any
+	mustDecode("fixtures/trades.json", &trades)
+	out := []map[string]any{}
+	for _, trade := range trades {
+		if trade["order_id"] == r.PathValue("id") {
+			out = append(out, trade)
+		}
+	}
+	writeData(w, out)
+}
+
+func (s *Server) handlePlaceOrder(w http.ResponseWriter, r *http.Request) {
+	if r.Form.Get("tradingsymbol") == "" || r.Form.Get("quantity") == "" {
+		writeError(w, http.StatusBadRequest, "InputException", "Missing tradingsymbol or quantity.")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := fmt.Sprintf("2610160000%05d", s.nextID)
+	s.nextID++
+	quantity, _ := strconv.ParseFloat(r.Form.Get("quantity"), 64)
+	price, _ := strconv.ParseFloat(r.Form.Get("price"), 64)
+	s.orders = append(s.orders, map[string]any{
+		"order_id":                  id,
+		"status":                    s.orderStatus,
+		"order_timestamp":           "2026-10-16 11:00:00",
+		"exchange_update_timestamp": "2026-10-16 11:00:00",
+		"variety":                   r.PathValue("variety"),
+		"exchange":                  r.Form.Get("exchange"),
+		"tradingsymbol":             r.Form.Get("tradingsymbol"),
+		"order_type":                r.Form.Get("order_type"),
+		"transaction_type":          r.Form.Get("transaction_type"),
+		"validity":                  r.Form.Get("validity"),
+		"product":                   r.Form.Get("product"),
+		"quantity":                  quantity,
+		"price":                     price,
+		"pending_quantity":          quantity,
+		"tag":                       r.Form.Get("tag"),
+	})
+	writeData(w, map[string]any{"order_id": id})
+}
+
+func (s *Server) handleModifyOrder(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.findOrder(r.PathValue("id"))
+	if order == nil || order["status"] != "OPEN" {
+		writeError(w, http.StatusBadRequest, "InputException", "Order cannot be modified.")
+		return
+	}
+	for _, field := range []string{"quantity", "price", "trigger_price"} {
+		if value, err := strconv.ParseFloat(r.Form.Get(field), 64); err == nil && value > 0 {
+			order[field] = value
+		}
+	}
+	if orderType := r.Form.Get("order_type"); orderType != "" {
+		order["order_type"] = orderType
+	}
+	writeData(w, map[string]any{"order_id": order["order_id"]})
+}
+
+func (s *Server) handleCancelOrder(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.findOrder(r.PathValue("id"))
+	if order == nil || order["status"] != "OPEN" {
+		writeError(w, http.StatusBadRequest, "InputException", "Order cannot be cancelled.")
+		return
+	}
+	order["status"] = "CANCELLED"
+	order["cancelled_quantity"] = order["pending_quantity"]
+	order["pending_quantity"] = 0
+	writeData(w, map[string]any{"order_id": order["order_id"]})
+}
+
+func (s *Server) handleGTTs(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeData(w, s.gtts)
+}
+
+func (s *Server) findGTT(id string) (int, map[string]any) {
+	for i, gtt := range s.gtts {
+		if fmt.Sprint(gtt["id"]) == id {
+			return i, gtt
+		}
+	}
+	return -1, nil
+}
+
+func (s *Server) handleGTT(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, gtt := s.findGTT(r.PathValue("id"))
+	if gtt == nil {
+		writeError(w, http.StatusNotFound, "InputException", "Trigger not found.")
+		return
+	}
+	writeData(w, gtt)
+}
+
+func (s *Server) handlePlaceGTT(w http.ResponseWriter, r *http.Request) {
+	var condition map[string]any
+	var orders []any
+	if err := json.Unmarshal([]byte(r.Form.Get("condition")), &condition); err != nil {
+		writeError(w, http.StatusBadRequest, "InputException", "Invalid condition.")
+		return
+	}
+	if err := json.Unmarshal([]byte(r.Form.Get("orders")), &orders); err != nil {
+		writeError(w, http.StatusBadRequest, "InputException", "Invalid orders.")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := 200000 + s.nextID
+	s.nextID++
+	s.gtts = append(s.gtts, map[string]any{
+		"id":         id,
+		"type":       r.Form.Get("type"),
+		"created_at": "2026-10-16 11:00:00",
+		"updated_at": "2026-10-16 11:00:00",
+		"status":     "active",
+		"condition":  condition,
+		"orders":     orders,
+	})
+	writeData(w, map[string]any{"trigger_id": id})
+}
+
+func (s *Server) handleDeleteGTT(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, gtt := s.findGTT(r.PathValue("id"))
+	if gtt == nil {
+		writeError(w, http.StatusNotFound, "InputException", "Trigger not found.")
+		return
+	}
+	s.gtts = append(s.gtts[:i], s.gtts[i+1:]...)
+	writeData(w, map[string]any{"trigger_id": gtt["id"]})
+}
+
+func (s *Server) handleMFOrders(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeData(w, s.mfOrders)
+}
+
+func (s *Server) handlePlaceMFOrder(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := fmt.Sprintf("fake-mf-%d", s.nextID)
+	s.nextID++
+	amount, _ := strconv.ParseFloat(r.Form.Get("amount"), 64)
+	s.mfOrders = append(s.mfOrders, map[string]any{
+		"order_id":         id,
+		"tradingsymbol":    r.Form.Get("tradingsymbol"),
+		"status":           "OPEN",
+		"order_timestamp":  "2026-10-16 11:00:00",
+		"transaction_type": r.Form.Get("transaction_type"),
+		"variety":          "regular",
+		"amount":           amount,
+		"tag":              r.Form.Get("tag"),
+	})
+	writeData(w, map[string]any{"order_id": id})
+}
+
+func writeData(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "data": data})
+}
+
+func writeError(w http.ResponseWriter, status int, errorType, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": "error", "error_type": errorType, "message": message})
+}
+
+func mustDecode(name string, v any) {
+	data, err := fixtures.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		panic(fmt.Sprintf("decode %s: %v", name, err))
+	}
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Chart draws ys against xs as an ASCII plot, one column per point, with a
+// dashed zero line and labels for the top, zero and bottom rows.
+func (p Printer) Chart(xs, ys []float64, height int) error {
+	if len(xs) == 0 || len(xs) != len(ys) {
+		return nil
+	}
+	height = max(height, 3)
+
+	top, bottom := 0.0, 0.0
+	for _, y := range ys {
+		top = math.Max(top, y)
+		bottom = math.Min(bottom, y)
+	}
+	if top == bottom {
+		top++
+	}
+	rowOf := func(y float64) int {
+		return int(math.Round((top - y) / (top - bottom) * float64(height-1)))
+	}
+	zero := rowOf(0)
+
+	grid := make([][]rune, height)
+	for r := range grid {
+		fill := ' '
+		if r == zero {
+			fill = '-'
+		}
+		grid[r] = []rune(strings.Repeat(string(fill), len(xs)))
+	}
+	for c, y := range ys {
+		grid[rowOf(y)][c] = '*'
+	}
+
+	labelWidth := max(len(formatChartValue(top)), len(formatChartValue(bottom)), 1)
+	var b strings.Builder
+	for r, line := range grid {
+		label := ""
+		switch r {
+		case 0:
+			label = formatChartValue(top)
+		case zero:
+			label = "0"
+		case height - 1:
+			label = formatChartValue(bottom)
+		}
+		fmt.Fprintf(&b, "%*s | %s\n", labelWidth, label, string(line))
+	}
+
+	first := formatChartValue(xs[0])
+	last := formatChartValue(xs[len(xs)-1])
+	mid := formatChartValue(xs[len(xs)/2])
+	axis := []rune(strings.Repeat(" ", max(len(xs), len(first)+len(mid)+len(last)+2)))
+	copy(axis, []rune(first))
+	copy(axis[max(len(xs)/2-len(mid)/2, len(first)+1):], []rune(mid))
+	copy(axis[len(axis)-len(last):], []rune(last))
+	fmt.Fprintf(&b, "%*s   %s\n", labelWidth, "", strings.TrimRight(string(axis), " "))
+
+	_, err := fmt.Fprint(p.out, b.String())
+	return err
+}
+
+func formatChartValue(v float64) string {
+	return fmt.Sprintf("%.0f", v)
+}
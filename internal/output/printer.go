@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"text/tabwriter"
 )
@@ -53,3 +54,91 @@ func (p Printer) KV(keyVals [][2]string) error {
 func (p Printer) IsJSON() bool {
 	return p.asJSON
 }
+
+func (p Printer) NDJSON(data any) error {
+	return json.NewEncoder(p.out).Encode(data)
+}
+
+// StreamTable writes rows as they arrive using fixed column widths, since
+// tabwriter can only align a table once every row is known.
+type StreamTable struct {
+	out    io.Writer
+	widths []int
+}
+
+func (p Printer) StreamTable(headers []string) (*StreamTable, error) {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = max(len(header), 10)
+	}
+	t := &StreamTable{out: p.out, widths: widths}
+	if err := t.Row(headers); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *StreamTable) Row(cols []string) error {
+	var b strings.Builder
+	for i, col := range cols {
+		if i > 0 {
+			b.WriteString("  ")
+		}
+		if i == len(cols)-1 || i >= len(t.widths) {
+			b.WriteString(col)
+			continue
+		}
+		t.widths[i] = max(t.widths[i], len(col))
+		fmt.Fprintf(&b, "%-*s", t.widths[i], col)
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(t.out, b.String())
+	return err
+}
+
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ClearScreen moves the cursor home and clears the terminal so a table can be
+// redrawn in place.
+func (p Printer) ClearScreen() error {
+	_, err := io.WriteString(p.out, "\x1b[H\x1b[2J")
+	return err
+}
+
+const (
+	ansiDefault = "\x1b[39m"
+	ansiGreen   = "\x1b[32m"
+	ansiRed     = "\x1b[31m"
+	ansiReset   = "\x1b[0m"
+)
+
+// HighlightRow colours a table row green (direction > 0), red (< 0) or the
+// default colour. Every row gets an escape prefix of the same length so
+// tabwriter column alignment is unaffected.
+func HighlightRow(row []string, direction int) []string {
+	if len(row) == 0 {
+		return row
+	}
+	color := ansiDefault
+	switch {
+	case direction > 0:
+		color = ansiGreen
+	case direction < 0:
+		color = ansiRed
+	}
+
+	out := append([]string(nil), row...)
+	out[0] = color + out[0]
+	out[len(out)-1] += ansiReset
+	return out
+}
@@ -0,0 +1,87 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type ids.
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes the handful of Thrift compact protocol constructs
+// the parquet footer and page headers need.
+type compactWriter struct {
+	buf     bytes.Buffer
+	lastIDs []int16
+	lastID  int16
+}
+
+func (w *compactWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - w.lastID; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.varint(zigzag(int64(id)))
+	}
+	w.lastID = id
+}
+
+func (w *compactWriter) varint(v uint64) {
+	w.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.fieldHeader(id, compactI32)
+	w.varint(zigzag(int64(v)))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.fieldHeader(id, compactI64)
+	w.varint(zigzag(v))
+}
+
+func (w *compactWriter) string(id int16, v string) {
+	w.fieldHeader(id, compactBinary)
+	w.rawString(v)
+}
+
+func (w *compactWriter) rawString(v string) {
+	w.varint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+func (w *compactWriter) listHeader(id int16, elemType byte, size int) {
+	w.fieldHeader(id, compactList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elemType)
+	w.varint(uint64(size))
+}
+
+// beginStruct starts a nested struct, either as field id of the enclosing
+// struct or, with id 0, as a list element.
+func (w *compactWriter) beginStruct(id int16) {
+	if id != 0 {
+		w.fieldHeader(id, compactStruct)
+	}
+	w.lastIDs = append(w.lastIDs, w.lastID)
+	w.lastID = 0
+}
+
+func (w *compactWriter) endStruct() {
+	w.buf.WriteByte(0)
+	w.lastID = w.lastIDs[len(w.lastIDs)-1]
+	w.lastIDs = w.lastIDs[:len(w.lastIDs)-1]
+}
@@ -0,0 +1,218 @@
+// Package parquet writes small, flat Apache Parquet files: required columns,
+// PLAIN encoding, no compression and a single row group. That is enough for
+// candle exports to load directly into pandas, DuckDB, Spark and friends.
+package parquet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+type ColumnType int
+
+const (
+	Int64 ColumnType = iota
+	Double
+	// TimestampMillis is an INT64 of milliseconds since the Unix epoch (UTC).
+	TimestampMillis
+	String
+)
+
+// Column holds one column's values in the slice matching its Type: Int64s
+// for Int64 and TimestampMillis, Doubles for Double, Strings for String.
+type Column struct {
+	Name    string
+	Type    ColumnType
+	Int64s  []int64
+	Doubles []float64
+	Strings []string
+}
+
+const magic = "PAR1"
+
+// Parquet physical types, converted types and enums from parquet.thrift.
+const (
+	physicalInt64     = 2
+	physicalDouble    = 5
+	physicalByteArray = 6
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	repetitionRequired = 0
+	encodingPlain      = 0
+	encodingRLE        = 3
+	codecUncompressed  = 0
+	pageTypeData       = 0
+)
+
+var ErrColumnLength = errors.New("parquet columns must all have the same number of values")
+
+func (c Column) len() int {
+	switch c.Type {
+	case Double:
+		return len(c.Doubles)
+	case String:
+		return len(c.Strings)
+	default:
+		return len(c.Int64s)
+	}
+}
+
+func (c Column) physicalType() int32 {
+	switch c.Type {
+	case Double:
+		return physicalDouble
+	case String:
+		return physicalByteArray
+	default:
+		return physicalInt64
+	}
+}
+
+func (c Column) plainValues() []byte {
+	var out []byte
+	switch c.Type {
+	case Double:
+		out = make([]byte, 0, 8*len(c.Doubles))
+		for _, v := range c.Doubles {
+			out = binary.LittleEndian.AppendUint64(out, math.Float64bits(v))
+		}
+	case String:
+		for _, v := range c.Strings {
+			out = binary.LittleEndian.AppendUint32(out, uint32(len(v)))
+			out = append(out, v...)
+		}
+	default:
+		out = make([]byte, 0, 8*len(c.Int64s))
+		for _, v := range c.Int64s {
+			out = binary.LittleEndian.AppendUint64(out, uint64(v))
+		}
+	}
+	return out
+}
+
+type chunkInfo struct {
+	offset int64
+	size   int64
+}
+
+// Write encodes columns as a complete parquet file.
+func Write(w io.Writer, columns []Column) error {
+	if len(columns) == 0 {
+		return errors.New("parquet file needs at least one column")
+	}
+	rows := columns[0].len()
+	for _, column := range columns {
+		if column.len() != rows {
+			return fmt.Errorf("%w: %q has %d, expected %d", ErrColumnLength, column.Name, column.len(), rows)
+		}
+	}
+
+	offset := int64(len(magic))
+	if _, err := io.WriteString(w, magic); err != nil {
+		return err
+	}
+
+	chunks := make([]chunkInfo, len(columns))
+	for i, column := range columns {
+		data := column.plainValues()
+		header := pageHeader(rows, len(data))
+		if _, err := w.Write(header); err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		size := int64(len(header) + len(data))
+		chunks[i] = chunkInfo{offset: offset, size: size}
+		offset += size
+	}
+
+	footer := fileMetadata(columns, chunks, rows)
+	if _, err := w.Write(footer); err != nil {
+		return err
+	}
+	if _, err := w.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer)))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, magic)
+	return err
+}
+
+func pageHeader(rows, size int) []byte {
+	var w compactWriter
+	w.i32(1, pageTypeData)
+	w.i32(2, int32(size))
+	w.i32(3, int32(size))
+	w.beginStruct(5)
+	w.i32(1, int32(rows))
+	w.i32(2, encodingPlain)
+	w.i32(3, encodingRLE)
+	w.i32(4, encodingRLE)
+	w.endStruct()
+	w.buf.WriteByte(0)
+	return w.buf.Bytes()
+}
+
+func fileMetadata(columns []Column, chunks []chunkInfo, rows int) []byte {
+	var w compactWriter
+	w.i32(1, 1)
+
+	w.listHeader(2, compactStruct, len(columns)+1)
+	w.beginStruct(0)
+	w.string(4, "schema")
+	w.i32(5, int32(len(columns)))
+	w.endStruct()
+	for _, column := range columns {
+		w.beginStruct(0)
+		w.i32(1, column.physicalType())
+		w.i32(3, repetitionRequired)
+		w.string(4, column.Name)
+		switch column.Type {
+		case TimestampMillis:
+			w.i32(6, convertedTimestampMillis)
+		case String:
+			w.i32(6, convertedUTF8)
+		}
+		w.endStruct()
+	}
+
+	w.i64(3, int64(rows))
+
+	var total int64
+	for _, chunk := range chunks {
+		total += chunk.size
+	}
+	w.listHeader(4, compactStruct, 1)
+	w.beginStruct(0)
+	w.listHeader(1, compactStruct, len(columns))
+	for i, column := range columns {
+		w.beginStruct(0)
+		w.i64(2, chunks[i].offset)
+		w.beginStruct(3)
+		w.i32(1, column.physicalType())
+		w.listHeader(2, compactI32, 2)
+		w.varint(zigzag(encodingPlain))
+		w.varint(zigzag(encodingRLE))
+		w.listHeader(3, compactBinary, 1)
+		w.rawString(column.Name)
+		w.i32(4, codecUncompressed)
+		w.i64(5, int64(rows))
+		w.i64(6, chunks[i].size)
+		w.i64(7, chunks[i].size)
+		w.i64(9, chunks[i].offset)
+		w.endStruct()
+		w.endStruct()
+	}
+	w.i64(2, total)
+	w.i64(3, int64(rows))
+	w.endStruct()
+
+	w.string(6, "zerodha-kite-cli")
+	w.buf.WriteByte(0)
+	return w.buf.Bytes()
+}
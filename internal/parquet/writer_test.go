@@ -0,0 +1,155 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+// compactReader decodes Thrift compact structs into field-id keyed maps so
+// the tests can check the footer independently of the writer.
+type compactReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *compactReader) byte() byte {
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *compactReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) zigzag() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) value(typ byte) any {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case compactI32, compactI64:
+		return r.zigzag()
+	case compactBinary:
+		n := int(r.uvarint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case compactList:
+		header := r.byte()
+		size, elem := int(header>>4), header&0x0f
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		items := make([]any, size)
+		for i := range items {
+			items[i] = r.value(elem)
+		}
+		return items
+	case compactStruct:
+		return r.readStruct()
+	}
+	panic("unsupported compact type")
+}
+
+func (r *compactReader) readStruct() map[int16]any {
+	fields := make(map[int16]any)
+	var last int16
+	for {
+		header := r.byte()
+		if header == 0 {
+			return fields
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(header & 0x0f)
+		last = id
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	columns := []Column{
+		{Name: "date", Type: TimestampMillis, Int64s: []int64{1760572800000, 1760659200000}},
+		{Name: "close", Type: Double, Doubles: []float64{1520.5, -3.25}},
+		{Name: "volume", Type: Int64, Int64s: []int64{12345, 0}},
+		{Name: "symbol", Type: String, Strings: []string{"INFY", "TCS"}},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, columns); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	data := buf.Bytes()
+	if string(data[:4]) != magic || string(data[len(data)-4:]) != magic {
+		t.Fatalf("expected PAR1 magic at both ends")
+	}
+
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := (&compactReader{data: data[len(data)-8-footerLen : len(data)-8]}).readStruct()
+	if footer[3] != int64(2) {
+		t.Fatalf("expected num_rows 2, got %v", footer[3])
+	}
+	schema := footer[2].([]any)
+	if len(schema) != 5 || schema[0].(map[int16]any)[5] != int64(4) {
+		t.Fatalf("expected root schema with 4 children, got %v", schema)
+	}
+	if date := schema[1].(map[int16]any); date[4] != "date" || date[1] != int64(physicalInt64) || date[6] != int64(convertedTimestampMillis) {
+		t.Fatalf("unexpected date schema: %v", date)
+	}
+	if symbol := schema[4].(map[int16]any); symbol[1] != int64(physicalByteArray) || symbol[6] != int64(convertedUTF8) {
+		t.Fatalf("unexpected symbol schema: %v", symbol)
+	}
+
+	chunks := footer[4].([]any)[0].(map[int16]any)[1].([]any)
+	var values [][]byte
+	for i, chunk := range chunks {
+		meta := chunk.(map[int16]any)[3].(map[int16]any)
+		if path := meta[3].([]any); path[0] != columns[i].Name {
+			t.Fatalf("expected path %q, got %v", columns[i].Name, path)
+		}
+		reader := &compactReader{data: data, pos: int(meta[9].(int64))}
+		page := reader.readStruct()
+		if page[5].(map[int16]any)[1] != int64(2) {
+			t.Fatalf("expected 2 values in page, got %v", page)
+		}
+		size := int(page[3].(int64))
+		if int64(reader.pos+size)-meta[9].(int64) != meta[7].(int64) {
+			t.Fatalf("chunk size %v does not match header plus data", meta[7])
+		}
+		values = append(values, data[reader.pos:reader.pos+size])
+	}
+
+	if got := int64(binary.LittleEndian.Uint64(values[0][8:])); got != 1760659200000 {
+		t.Fatalf("expected second timestamp, got %d", got)
+	}
+	if got := math.Float64frombits(binary.LittleEndian.Uint64(values[1][8:])); got != -3.25 {
+		t.Fatalf("expected second close -3.25, got %f", got)
+	}
+	if got := int64(binary.LittleEndian.Uint64(values[2])); got != 12345 {
+		t.Fatalf("expected first volume 12345, got %d", got)
+	}
+	if got := string(values[3][4:8]) + string(values[3][12:]); got != "INFYTCS" {
+		t.Fatalf("expected length-prefixed strings, got %q", values[3])
+	}
+}
+
+func TestWriteRejectsRaggedColumns(t *testing.T) {
+	err := Write(&bytes.Buffer{}, []Column{
+		{Name: "a", Type: Int64, Int64s: []int64{1, 2}},
+		{Name: "b", Type: Double, Doubles: []float64{1}},
+	})
+	if !errors.Is(err, ErrColumnLength) {
+		t.Fatalf("expected ErrColumnLength, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+type OptionType string
+
+const (
+	Call OptionType = "CE"
+	Put  OptionType = "PE"
+)
+
+const (
+	daysPerYear = 365.0
+
+	ivLowerBound = 1e-4
+	ivUpperBound = 5.0
+	ivTolerance  = 1e-8
+	ivMaxIter    = 100
+)
+
+var (
+	ErrInvalidInput  = errors.New("spot, strike and time to expiry must be positive")
+	ErrPriceBounds   = errors.New("option price is outside no-arbitrage bounds")
+	ErrNoConvergence = errors.New("implied volatility did not converge")
+)
+
+// Greeks are per unit of the underlying. Theta is per calendar day and vega
+// is per one volatility point (1%).
+type Greeks struct {
+	Delta float64 `json:"delta"`
+	Gamma float64 `json:"gamma"`
+	Theta float64 `json:"theta"`
+	Vega  float64 `json:"vega"`
+}
+
+func ParseOptionType(raw string) (OptionType, error) {
+	switch strings.ToUpper(strings.TrimSpace(raw)) {
+	case "CE", "CALL", "C":
+		return Call, nil
+	case "PE", "PUT", "P":
+		return Put, nil
+	default:
+		return "", fmt.Errorf("unknown option type %q", raw)
+	}
+}
+
+// Model is the pricing model, chosen by what the underlying price is.
+type Model string
+
+const (
+	// BlackScholes prices off spot, which drifts at the risk-free rate.
+	BlackScholes Model = "black-scholes"
+	// Black76 prices off a futures price, which has no drift; only the payoff
+	// is discounted.
+	Black76 Model = "black-76"
+)
+
+// Price returns the Black-Scholes price of a European option. years is the
+// time to expiry in years, rate the continuously compounded risk-free rate and
+// vol the annualised volatility, both as decimals.
+func Price(opt OptionType, spot, strike, years, rate, vol float64) float64 {
+	return BlackScholes.Price(opt, spot, strike, years, rate, vol)
+}
+
+func ComputeGreeks(opt OptionType, spot, strike, years, rate, vol float64) Greeks {
+	return BlackScholes.Greeks(opt, spot, strike, years, rate, vol)
+}
+
+func ImpliedVolatility(opt OptionType, price, spot, strike, years, rate float64) (float64, error) {
+	return BlackScholes.ImpliedVolatility(opt, price, spot, strike, years, rate)
+}
+
+// Price returns the model price of a European option on underlying, which is
+// spot for BlackScholes and the futures price for Black76.
+func (m Model) Price(opt OptionType, underlying, strike, years, rate, vol float64) float64 {
+	years = math.Max(years, 0)
+	forward := underlying * m.growth(rate, years)
+	discount := math.Exp(-rate * years)
+	if years == 0 || vol <= 0 {
+		return discount * intrinsic(opt, forward, strike)
+	}
+
+	d1, d2 := d1d2(forward, strike, years, vol)
+	if opt == Put {
+		return discount * (strike*normCDF(-d2) - forward*normCDF(-d1))
+	}
+	return discount * (forward*normCDF(d1) - strike*normCDF(d2))
+}
+
+// Greeks are with respect to underlying, so Black76 delta and gamma are per
+// unit of the futures price.
+func (m Model) Greeks(opt OptionType, underlying, strike, years, rate, vol float64) Greeks {
+	if years <= 0 || vol <= 0 || underlying <= 0 || strike <= 0 {
+		return Greeks{}
+	}
+
+	carry := m.carry(rate)
+	forward := underlying * m.growth(rate, years)
+	discount := math.Exp(-rate * years)
+	// scale turns the forward's sensitivities into the underlying's.
+	scale := discount * m.growth(rate, years)
+	d1, d2 := d1d2(forward, strike, years, vol)
+	sqrtT := math.Sqrt(years)
+	pdf := normPDF(d1)
+
+	greeks := Greeks{
+		Gamma: scale * pdf / (underlying * vol * sqrtT),
+		Vega:  scale * underlying * pdf * sqrtT / 100,
+	}
+	decay := -scale * underlying * pdf * vol / (2 * sqrtT)
+	if opt == Put {
+		greeks.Delta = scale * (normCDF(d1) - 1)
+		greeks.Theta = (decay + (carry-rate)*scale*underlying*normCDF(-d1) + rate*discount*strike*normCDF(-d2)) / daysPerYear
+	} else {
+		greeks.Delta = scale * normCDF(d1)
+		greeks.Theta = (decay - (carry-rate)*scale*underlying*normCDF(d1) - rate*discount*strike*normCDF(d2)) / daysPerYear
+	}
+	return greeks
+}
+
+// ImpliedVolatility solves for the volatility that reproduces price, using
+// Newton-Raphson steps and falling back to bisection when vega is too small.
+func (m Model) ImpliedVolatility(opt OptionType, price, underlying, strike, years, rate float64) (float64, error) {
+	if underlying <= 0 || strike <= 0 || years <= 0 {
+		return 0, ErrInvalidInput
+	}
+
+	discount := math.Exp(-rate * years)
+	forward := underlying * m.growth(rate, years)
+	lower := discount * intrinsic(opt, forward, strike)
+	upper := discount * forward
+	if opt == Put {
+		upper = discount * strike
+	}
+	if price < lower-ivTolerance || price >= upper {
+		return 0, ErrPriceBounds
+	}
+
+	lo, hi := ivLowerBound, ivUpperBound
+	if m.Price(opt, underlying, strike, years, rate, lo) > price {
+		return lo, nil
+	}
+	if m.Price(opt, underlying, strike, years, rate, hi) < price {
+		return 0, ErrNoConvergence
+	}
+
+	vol := 0.3
+	for range ivMaxIter {
+		diff := m.Price(opt, underlying, strike, years, rate, vol) - price
+		if math.Abs(diff) < ivTolerance {
+			return vol, nil
+		}
+		if diff > 0 {
+			hi = vol
+		} else {
+			lo = vol
+		}
+
+		vega := m.Greeks(opt, underlying, strike, years, rate, vol).Vega * 100
+		next := vol - diff/vega
+		if vega < 1e-10 || next <= lo || next >= hi {
+			next = (lo + hi) / 2
+		}
+		vol = next
+	}
+	if hi-lo < 1e-6 {
+		return vol, nil
+	}
+	return 0, ErrNoConvergence
+}
+
+// carry is the underlying's drift: the risk-free rate for spot and zero for
+// a futures price.
+func (m Model) carry(rate float64) float64 {
+	if m == Black76 {
+		return 0
+	}
+	return rate
+}
+
+func (m Model) growth(rate, years float64) float64 {
+	return math.Exp(m.carry(rate) * years)
+}
+
+func d1d2(forward, strike, years, vol float64) (float64, float64) {
+	sqrtT := math.Sqrt(years)
+	d1 := (math.Log(forward/strike) + vol*vol/2*years) / (vol * sqrtT)
+	return d1, d1 - vol*sqrtT
+}
+
+func intrinsic(opt OptionType, underlying, strike float64) float64 {
+	if opt == Put {
+		return math.Max(strike-underlying, 0)
+	}
+	return math.Max(underlying-strike, 0)
+}
+
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+func normPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
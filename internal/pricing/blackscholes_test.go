@@ -0,0 +1,120 @@
+package pricing
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func assertClose(t *testing.T, name string, got, want, tol float64) {
+	t.Helper()
+	if math.Abs(got-want) > tol {
+		t.Fatalf("expected %s %.6f (±%g), got %.6f", name, want, tol, got)
+	}
+}
+
+// Reference values are from Hull, Options, Futures, and Other Derivatives.
+func TestPriceMatchesReferenceValues(t *testing.T) {
+	assertClose(t, "call", Price(Call, 42, 40, 0.5, 0.10, 0.20), 4.76, 0.005)
+	assertClose(t, "put", Price(Put, 42, 40, 0.5, 0.10, 0.20), 0.81, 0.005)
+}
+
+func TestPutCallParity(t *testing.T) {
+	spot, strike, years, rate, vol := 24850.0, 25000.0, 12.0/365, 0.065, 0.14
+	call := Price(Call, spot, strike, years, rate, vol)
+	put := Price(Put, spot, strike, years, rate, vol)
+	assertClose(t, "parity", call-put, spot-strike*math.Exp(-rate*years), 1e-9)
+}
+
+func TestBlack76MatchesReferenceValues(t *testing.T) {
+	assertClose(t, "put", Black76.Price(Put, 20, 20, 4.0/12, 0.09, 0.25), 1.12, 0.005)
+
+	forward, strike, years, rate, vol := 25120.0, 25000.0, 12.0/365, 0.065, 0.14
+	call := Black76.Price(Call, forward, strike, years, rate, vol)
+	put := Black76.Price(Put, forward, strike, years, rate, vol)
+	assertClose(t, "parity", call-put, (forward-strike)*math.Exp(-rate*years), 1e-9)
+
+	// A futures price is spot grown at the risk-free rate, so both models
+	// agree on price once the forward is discounted back to spot.
+	spot := forward * math.Exp(-rate*years)
+	assertClose(t, "spot equivalent", call, Price(Call, spot, strike, years, rate, vol), 1e-9)
+
+	greeks := Black76.Greeks(Call, forward, strike, years, rate, vol)
+	bump := 0.01
+	delta := (Black76.Price(Call, forward+bump, strike, years, rate, vol) - Black76.Price(Call, forward-bump, strike, years, rate, vol)) / (2 * bump)
+	assertClose(t, "delta", greeks.Delta, delta, 1e-6)
+	step := 0.01 / 365
+	theta := (Black76.Price(Call, forward, strike, years-step, rate, vol) - Black76.Price(Call, forward, strike, years+step, rate, vol)) / (2 * step) / 365
+	assertClose(t, "theta", greeks.Theta, theta, 1e-4)
+
+	iv, err := Black76.ImpliedVolatility(Call, call, forward, strike, years, rate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, "iv", iv, vol, 1e-5)
+}
+
+func TestComputeGreeksMatchesReferenceValues(t *testing.T) {
+	years := 20.0 / 52
+	call := ComputeGreeks(Call, 49, 50, years, 0.05, 0.20)
+	assertClose(t, "call delta", call.Delta, 0.522, 0.001)
+	assertClose(t, "gamma", call.Gamma, 0.066, 0.001)
+	assertClose(t, "call theta", call.Theta, -4.31/365, 0.0001)
+	assertClose(t, "vega", call.Vega, 0.121, 0.001)
+
+	put := ComputeGreeks(Put, 49, 50, years, 0.05, 0.20)
+	assertClose(t, "put delta", put.Delta, call.Delta-1, 1e-12)
+	assertClose(t, "put gamma", put.Gamma, call.Gamma, 1e-12)
+	assertClose(t, "put theta", put.Theta, -1.85/365, 0.0001)
+}
+
+func TestImpliedVolatilityRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		opt    OptionType
+		spot   float64
+		strike float64
+		years  float64
+		vol    float64
+	}{
+		{name: "atm call", opt: Call, spot: 25000, strike: 25000, years: 7.0 / 365, vol: 0.12},
+		{name: "otm put", opt: Put, spot: 25000, strike: 24000, years: 30.0 / 365, vol: 0.18},
+		{name: "itm call", opt: Call, spot: 1500, strike: 1300, years: 0.25, vol: 0.35},
+		{name: "high vol", opt: Put, spot: 100, strike: 110, years: 1, vol: 1.5},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			price := Price(tc.opt, tc.spot, tc.strike, tc.years, 0.065, tc.vol)
+			got, err := ImpliedVolatility(tc.opt, price, tc.spot, tc.strike, tc.years, 0.065)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, "iv", got, tc.vol, 1e-5)
+		})
+	}
+}
+
+func TestImpliedVolatilityRejectsInvalidInputs(t *testing.T) {
+	if _, err := ImpliedVolatility(Call, 10, 100, 100, 0, 0.05); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+	if _, err := ImpliedVolatility(Call, 1, 120, 100, 0.5, 0.05); !errors.Is(err, ErrPriceBounds) {
+		t.Fatalf("expected ErrPriceBounds below intrinsic, got %v", err)
+	}
+	if _, err := ImpliedVolatility(Call, 130, 120, 100, 0.5, 0.05); !errors.Is(err, ErrPriceBounds) {
+		t.Fatalf("expected ErrPriceBounds above spot, got %v", err)
+	}
+}
+
+func TestParseOptionType(t *testing.T) {
+	for raw, want := range map[string]OptionType{"ce": Call, "CALL": Call, "PE": Put, " put ": Put} {
+		got, err := ParseOptionType(raw)
+		if err != nil || got != want {
+			t.Fatalf("expected %s for %q, got %s (%v)", want, raw, got, err)
+		}
+	}
+	if _, err := ParseOptionType("FUT"); err == nil {
+		t.Fatalf("expected error for FUT")
+	}
+}
@@ -0,0 +1,125 @@
+package pricing
+
+import (
+	"math"
+	"time"
+)
+
+// Future marks a futures leg, which pays off linearly in the underlying.
+const Future OptionType = "FUT"
+
+// Leg is one position in a multi-leg book. Quantity is signed (negative for
+// shorts) and Expiry is the exact expiry moment. Vol is used to value an
+// option before expiry with Model; legs without a volatility are valued at
+// intrinsic.
+type Leg struct {
+	Type       OptionType
+	Strike     float64
+	Quantity   float64
+	EntryPrice float64
+	Expiry     time.Time
+	Vol        float64
+	Model      Model
+}
+
+type PayoffPoint struct {
+	Price float64 `json:"price"`
+	PnL   float64 `json:"pnl"`
+}
+
+// Value returns the per-unit value of the leg at the given underlying price
+// and evaluation time.
+func (l Leg) Value(price float64, at time.Time, rate float64) float64 {
+	if l.Type == Future {
+		return price
+	}
+	years := l.Expiry.Sub(at).Hours() / 24 / daysPerYear
+	if years <= 0 || l.Vol <= 0 {
+		return intrinsic(l.Type, price, l.Strike)
+	}
+	return l.Model.Price(l.Type, price, l.Strike, years, rate, l.Vol)
+}
+
+func PnL(legs []Leg, price float64, at time.Time, rate float64) float64 {
+	total := 0.0
+	for _, leg := range legs {
+		total += leg.Quantity * (leg.Value(price, at, rate) - leg.EntryPrice)
+	}
+	return total
+}
+
+func Payoff(legs []Leg, prices []float64, at time.Time, rate float64) []PayoffPoint {
+	points := make([]PayoffPoint, 0, len(prices))
+	for _, price := range prices {
+		points = append(points, PayoffPoint{Price: price, PnL: PnL(legs, price, at, rate)})
+	}
+	return points
+}
+
+// PriceGrid returns steps evenly spaced prices from low to high inclusive.
+func PriceGrid(low, high float64, steps int) []float64 {
+	if steps < 2 || high <= low {
+		return []float64{low}
+	}
+	grid := make([]float64, steps)
+	step := (high - low) / float64(steps-1)
+	for i := range grid {
+		grid[i] = low + float64(i)*step
+	}
+	return grid
+}
+
+// Breakevens returns the prices where the payoff crosses zero, linearly
+// interpolated between adjacent points.
+func Breakevens(points []PayoffPoint) []float64 {
+	var breakevens []float64
+	for i, point := range points {
+		if point.PnL == 0 {
+			if i == 0 || points[i-1].PnL != 0 {
+				breakevens = append(breakevens, point.Price)
+			}
+			continue
+		}
+		if i == 0 {
+			continue
+		}
+		prev := points[i-1]
+		if prev.PnL != 0 && (prev.PnL < 0) != (point.PnL < 0) {
+			breakevens = append(breakevens, prev.Price+(point.Price-prev.Price)*(-prev.PnL)/(point.PnL-prev.PnL))
+		}
+	}
+	return breakevens
+}
+
+// Extremes returns the highest and lowest points of the payoff.
+func Extremes(points []PayoffPoint) (PayoffPoint, PayoffPoint) {
+	if len(points) == 0 {
+		return PayoffPoint{}, PayoffPoint{}
+	}
+	high, low := points[0], points[0]
+	for _, point := range points[1:] {
+		if point.PnL > high.PnL {
+			high = point
+		}
+		if point.PnL < low.PnL {
+			low = point
+		}
+	}
+	return high, low
+}
+
+// UpperTailSlope is the P&L change per unit rise in the underlying once it is
+// above every strike: long calls and futures gain, short ones lose. A
+// non-zero slope means profit or loss is unbounded on the upside.
+func UpperTailSlope(legs []Leg) float64 {
+	slope := 0.0
+	for _, leg := range legs {
+		if leg.Type == Call || leg.Type == Future {
+			slope += leg.Quantity
+		}
+	}
+	if math.Abs(slope) < 1e-9 {
+		return 0
+	}
+	return slope
+}
@@ -0,0 +1,71 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPayoffBullCallSpread(t *testing.T) {
+	expiry := time.Date(2026, 10, 27, 15, 30, 0, 0, time.UTC)
+	legs := []Leg{
+		{Type: Call, Strike: 25000, Quantity: 75, EntryPrice: 200, Expiry: expiry},
+		{Type: Call, Strike: 25200, Quantity: -75, EntryPrice: 120, Expiry: expiry},
+	}
+
+	points := Payoff(legs, PriceGrid(24800, 25400, 7), expiry, 0.065)
+	high, low := Extremes(points)
+	assertClose(t, "max profit", high.PnL, 75*(200-80), 1e-9)
+	assertClose(t, "max loss", low.PnL, -75*80, 1e-9)
+
+	breakevens := Breakevens(points)
+	if len(breakevens) != 1 {
+		t.Fatalf("expected one breakeven, got %v", breakevens)
+	}
+	assertClose(t, "breakeven", breakevens[0], 25080, 1e-9)
+
+	if slope := UpperTailSlope(legs); slope != 0 {
+		t.Fatalf("expected capped upside, got slope %f", slope)
+	}
+}
+
+func TestPayoffShortStraddleWithFuturesHedge(t *testing.T) {
+	expiry := time.Date(2026, 10, 27, 15, 30, 0, 0, time.UTC)
+	legs := []Leg{
+		{Type: Call, Strike: 25000, Quantity: -75, EntryPrice: 150, Expiry: expiry},
+		{Type: Put, Strike: 25000, Quantity: -75, EntryPrice: 140, Expiry: expiry},
+	}
+
+	breakevens := Breakevens(Payoff(legs, PriceGrid(24000, 26000, 201), expiry, 0))
+	if len(breakevens) != 2 {
+		t.Fatalf("expected two breakevens, got %v", breakevens)
+	}
+	assertClose(t, "lower breakeven", breakevens[0], 24710, 1e-6)
+	assertClose(t, "upper breakeven", breakevens[1], 25290, 1e-6)
+	if slope := UpperTailSlope(legs); slope != -75 {
+		t.Fatalf("expected unlimited upside loss, got slope %f", slope)
+	}
+
+	legs = append(legs, Leg{Type: Future, Quantity: 75, EntryPrice: 25010})
+	if slope := UpperTailSlope(legs); slope != 0 {
+		t.Fatalf("expected futures hedge to flatten the tail, got %f", slope)
+	}
+	assertClose(t, "hedged pnl", PnL(legs, 26000, expiry, 0), -75*1000+150*75+140*75+75*990, 1e-9)
+}
+
+func TestLegValueBeforeExpiryUsesVolatility(t *testing.T) {
+	expiry := time.Date(2026, 10, 27, 15, 30, 0, 0, time.UTC)
+	leg := Leg{Type: Put, Strike: 25000, Quantity: 1, Expiry: expiry, Vol: 0.15}
+	at := expiry.Add(-7 * 24 * time.Hour)
+
+	want := Price(Put, 25000, 25000, 7.0/365, 0.065, 0.15)
+	assertClose(t, "value", leg.Value(25000, at, 0.065), want, 1e-9)
+	assertClose(t, "expiry value", leg.Value(24900, expiry, 0.065), 100, 1e-9)
+}
+
+func TestBreakevensOnGridPoint(t *testing.T) {
+	points := []PayoffPoint{{Price: 1, PnL: -2}, {Price: 2, PnL: 0}, {Price: 3, PnL: 0}, {Price: 4, PnL: 5}}
+	got := Breakevens(points)
+	if len(got) != 1 || got[0] != 2 {
+		t.Fatalf("expected single breakeven at 2, got %v", got)
+	}
+}